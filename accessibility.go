@@ -0,0 +1,515 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxContrastSamples caps how many text elements are checked for color contrast
+const maxContrastSamples = 400
+
+// maxReportedViolations caps how many elements are listed per accessibility rule
+const maxReportedViolations = 20
+
+// Accessibility rules
+const (
+	RuleColorContrast   = "color-contrast"
+	RuleFormLabel       = "form-label"
+	RuleAccessibleName  = "accessible-name"
+	RuleARIA            = "aria"
+	RuleLandmarks       = "landmarks"
+	RuleHiddenFocusable = "hidden-focusable"
+	RuleTabindex        = "tabindex"
+	RuleKeyboard        = "keyboard"
+)
+
+// AccessibilityScore holds accessibility metrics
+type AccessibilityScore struct {
+	Score           float64                  `json:"score"`
+	MaxScore        float64                  `json:"max_score"`
+	ContrastChecked int                      `json:"contrast_checked"` // Text elements with a measurable contrast
+	ContrastIssues  []ContrastIssue          `json:"contrast_issues"`
+	Landmarks       LandmarkSummary          `json:"landmarks"`
+	Violations      []AccessibilityViolation `json:"violations"`
+	Keyboard        KeyboardReport           `json:"keyboard"`
+	RuleCounts      map[string]int           `json:"rule_counts"` // Violations per rule, including unlisted ones
+	Issues          []string                 `json:"issues"`
+}
+
+// ContrastIssue is a text element whose color contrast is below the WCAG AA minimum
+type ContrastIssue struct {
+	Selector   string  `json:"selector"`
+	Text       string  `json:"text"`
+	Foreground string  `json:"foreground"`
+	Background string  `json:"background"`
+	Ratio      float64 `json:"ratio"`
+	Required   float64 `json:"required"`
+	LargeText  bool    `json:"large_text"`
+}
+
+// LandmarkSummary counts the page's landmark regions
+type LandmarkSummary struct {
+	Main          int `json:"main"`
+	Banner        int `json:"banner"`
+	ContentInfo   int `json:"contentinfo"`
+	Navigation    int `json:"navigation"`
+	UnlabeledNavs int `json:"unlabeled_navigation"` // Navigation landmarks without a label, when there are several
+	OutsideText   int `json:"outside_text"`         // Words of visible text outside any landmark
+}
+
+// AccessibilityViolation is one element breaking an accessibility rule
+type AccessibilityViolation struct {
+	Rule     string `json:"rule"`
+	Selector string `json:"selector"`
+	Snippet  string `json:"snippet"`
+	Message  string `json:"message"`
+}
+
+// accessibleNameFunction is a JavaScript function that computes a simplified accessible name:
+// aria-labelledby, aria-label, associated labels, text content with image alts, then title
+const accessibleNameFunction = `(el) => {
+	const text = (node) => {
+		if (node.nodeType === Node.TEXT_NODE) return node.textContent;
+		if (node.nodeType !== Node.ELEMENT_NODE) return '';
+		if (node.getAttribute('aria-hidden') === 'true') return '';
+		if (node.tagName === 'IMG' || (node.tagName === 'INPUT' && node.type === 'image')) return node.getAttribute('alt') || '';
+		if (node.tagName === 'svg') {
+			const title = node.querySelector('title');
+			return node.getAttribute('aria-label') || (title ? title.textContent : '');
+		}
+		if (node.getAttribute('aria-label')) return node.getAttribute('aria-label');
+		return Array.from(node.childNodes).map(text).join(' ');
+	};
+	const clean = (s) => (s || '').trim().replace(/\s+/g, ' ');
+	const labelledBy = (el.getAttribute('aria-labelledby') || '').split(/\s+/).filter(Boolean)
+		.map(id => document.getElementById(id)).filter(Boolean).map(text).join(' ');
+	if (clean(labelledBy)) return clean(labelledBy);
+	if (clean(el.getAttribute('aria-label'))) return clean(el.getAttribute('aria-label'));
+	if (el.labels && el.labels.length) {
+		const fromLabels = clean(Array.from(el.labels).map(text).join(' '));
+		if (fromLabels) return fromLabels;
+	}
+	if (el.tagName === 'INPUT' && ['submit', 'reset', 'button'].includes(el.type)) {
+		return clean(el.value) || (el.type === 'button' ? '' : el.type);
+	}
+	if (el.tagName === 'INPUT' && el.type === 'image') return clean(el.getAttribute('alt'));
+	if (!['INPUT', 'SELECT', 'TEXTAREA'].includes(el.tagName)) {
+		const content = clean(text(el));
+		if (content) return content;
+	}
+	return clean(el.getAttribute('title'));
+}`
+
+// accessibilityScript collects the data of every accessibility rule in one DOM pass
+const accessibilityScript = `(options) => {
+	const selectorOf = ` + cssSelectorFunction + `;
+	const nameOf = ` + accessibleNameFunction + `;
+	const snippetOf = (el) => el.outerHTML.replace(/\s+/g, ' ').slice(0, 120);
+	const isRendered = (el) => {
+		const style = window.getComputedStyle(el);
+		return style.display !== 'none' && style.visibility !== 'hidden' && el.getClientRects().length > 0;
+	};
+	const violations = [];
+	const report = (rule, el, message) => violations.push({ rule: rule, selector: selectorOf(el), snippet: snippetOf(el), message: message });
+
+	// Color contrast: text elements with their color and the first opaque background behind them
+	const contrast = [];
+	const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT);
+	const seen = new Set();
+	while (walker.nextNode() && contrast.length < options.maxContrastSamples) {
+		const node = walker.currentNode;
+		const el = node.parentElement;
+		if (!el || seen.has(el) || !node.textContent.trim() || ['SCRIPT', 'STYLE', 'NOSCRIPT'].includes(el.tagName)) continue;
+		seen.add(el);
+		if (!isRendered(el)) continue;
+		const style = window.getComputedStyle(el);
+		let background = '';
+		for (let n = el; n; n = n.parentElement) {
+			const s = window.getComputedStyle(n);
+			if (s.backgroundImage && s.backgroundImage !== 'none') { background = 'image'; break; }
+			const color = s.backgroundColor;
+			if (color && color !== 'transparent' && !/rgba\(.*,\s*0\)$/.test(color)) { background = color; break; }
+		}
+		contrast.push({
+			selector: selectorOf(el),
+			text: node.textContent.trim().replace(/\s+/g, ' ').slice(0, 80),
+			color: style.color,
+			background: background || 'rgb(255, 255, 255)',
+			opacity: parseFloat(style.opacity),
+			fontSize: parseFloat(style.fontSize),
+			fontWeight: parseInt(style.fontWeight, 10) || 400
+		});
+	}
+
+	// Form controls need a label; a placeholder disappears as soon as the user types
+	document.querySelectorAll('input, select, textarea').forEach(el => {
+		if (el.tagName === 'INPUT' && ['hidden', 'submit', 'reset', 'button', 'image'].includes(el.type)) return;
+		if (!isRendered(el) || nameOf(el)) return;
+		report('` + RuleFormLabel + `', el, el.getAttribute('placeholder') ? 'Form control is only labeled by its placeholder' : 'Form control has no label');
+	});
+
+	// Links and buttons need an accessible name
+	document.querySelectorAll('a[href], button, [role="button"], [role="link"], input[type="submit"], input[type="button"], input[type="image"]').forEach(el => {
+		if (!isRendered(el) || nameOf(el)) return;
+		report('` + RuleAccessibleName + `', el, (el.tagName === 'A' || el.getAttribute('role') === 'link' ? 'Link' : 'Button') + ' has no accessible name');
+	});
+
+	// ARIA: valid roles, known attributes and references to existing ids
+	const roles = new Set(options.roles);
+	const attributes = new Set(options.attributes);
+	document.querySelectorAll('*').forEach(el => {
+		const role = el.getAttribute('role');
+		if (role !== null) {
+			const invalid = role.trim().split(/\s+/).filter(r => r && !roles.has(r.toLowerCase()));
+			if (!role.trim() || invalid.length === role.trim().split(/\s+/).length) {
+				report('` + RuleARIA + `', el, 'Invalid role "' + role + '"');
+			}
+		}
+		for (const attr of el.attributes) {
+			if (!attr.name.startsWith('aria-')) continue;
+			if (!attributes.has(attr.name)) {
+				report('` + RuleARIA + `', el, 'Unknown attribute ' + attr.name);
+			} else if (['aria-labelledby', 'aria-describedby', 'aria-controls', 'aria-owns'].includes(attr.name)) {
+				const missing = attr.value.split(/\s+/).filter(id => id && !document.getElementById(id));
+				if (missing.length) report('` + RuleARIA + `', el, attr.name + ' references missing id "' + missing.join('", "') + '"');
+			}
+		}
+	});
+	if (document.body.getAttribute('aria-hidden') === 'true') {
+		report('` + RuleARIA + `', document.body, 'aria-hidden="true" on the body hides the whole page');
+	}
+
+	// Focusable elements inside aria-hidden are reachable by keyboard but announced as nothing
+	const focusable = 'a[href], button, input, select, textarea, iframe, [tabindex], [contenteditable="true"]';
+	document.querySelectorAll('[aria-hidden="true"]').forEach(hidden => {
+		[hidden, ...hidden.querySelectorAll(focusable)].forEach(el => {
+			if (!el.matches(focusable) || el.disabled || el.getAttribute('tabindex') === '-1' || !isRendered(el)) return;
+			report('` + RuleHiddenFocusable + `', el, 'Focusable element is hidden from assistive technology');
+		});
+	});
+
+	// tabindex: positive values break the reading order, tabindex on plain elements needs a role
+	document.querySelectorAll('[tabindex]').forEach(el => {
+		const value = parseInt(el.getAttribute('tabindex'), 10);
+		if (value > 0) {
+			report('` + RuleTabindex + `', el, 'tabindex="' + value + '" overrides the natural focus order');
+		} else if (value === 0 && ['DIV', 'SPAN', 'P', 'LI', 'TD', 'SECTION'].includes(el.tagName) && !el.getAttribute('role')) {
+			report('` + RuleTabindex + `', el, 'Focusable ' + el.tagName.toLowerCase() + ' has no role');
+		}
+	});
+
+	// Landmarks
+	const landmarkSelector = 'main, [role="main"], header, [role="banner"], footer, [role="contentinfo"], nav, [role="navigation"], aside, [role="complementary"], [role="region"][aria-label], [role="region"][aria-labelledby], section[aria-label], section[aria-labelledby], form[aria-label], [role="search"]';
+	const topLevel = (el) => !el.parentElement.closest('article, aside, main, nav, section, [role="main"], [role="region"]');
+	const navs = Array.from(document.querySelectorAll('nav, [role="navigation"]'));
+	let outsideText = 0;
+	const outsideWalker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT);
+	while (outsideWalker.nextNode()) {
+		const node = outsideWalker.currentNode;
+		const el = node.parentElement;
+		if (!el || !node.textContent.trim() || ['SCRIPT', 'STYLE', 'NOSCRIPT'].includes(el.tagName)) continue;
+		if (el.closest(landmarkSelector) || el.closest('[aria-hidden="true"], dialog, [role="dialog"]') || !isRendered(el)) continue;
+		outsideText += node.textContent.trim().split(/\s+/).length;
+	}
+	const landmarks = {
+		main: document.querySelectorAll('main, [role="main"]').length,
+		banner: Array.from(document.querySelectorAll('header, [role="banner"]')).filter(el => el.getAttribute('role') === 'banner' || topLevel(el)).length,
+		contentinfo: Array.from(document.querySelectorAll('footer, [role="contentinfo"]')).filter(el => el.getAttribute('role') === 'contentinfo' || topLevel(el)).length,
+		navigation: navs.length,
+		unlabeled_navigation: navs.length > 1 ? navs.filter(el => !el.getAttribute('aria-label') && !el.getAttribute('aria-labelledby')).length : 0,
+		outside_text: outsideText
+	};
+
+	return { contrast: contrast, violations: violations, landmarks: landmarks };
+}`
+
+// accessibilityResult is what accessibilityScript returns
+type accessibilityResult struct {
+	Contrast   []contrastSample         `json:"contrast"`
+	Violations []AccessibilityViolation `json:"violations"`
+	Landmarks  LandmarkSummary          `json:"landmarks"`
+}
+
+// contrastSample is a text element with its computed colors
+type contrastSample struct {
+	Selector   string  `json:"selector"`
+	Text       string  `json:"text"`
+	Color      string  `json:"color"`
+	Background string  `json:"background"`
+	Opacity    float64 `json:"opacity"`
+	FontSize   float64 `json:"fontSize"`
+	FontWeight int     `json:"fontWeight"`
+}
+
+// validARIARoles are the non-abstract WAI-ARIA 1.2 roles, plus the DPUB and graphics module roles
+var validARIARoles = strings.Fields(`alert alertdialog application article banner blockquote button caption cell
+	checkbox code columnheader combobox complementary contentinfo definition deletion dialog directory document
+	emphasis feed figure form generic grid gridcell group heading img insertion link list listbox listitem log
+	main marquee math menu menubar menuitem menuitemcheckbox menuitemradio meter navigation none note option
+	paragraph presentation progressbar radio radiogroup region row rowgroup rowheader scrollbar search searchbox
+	separator slider spinbutton status strong subscript superscript switch tab table tablist tabpanel term
+	textbox time timer toolbar tooltip tree treegrid treeitem doc-abstract doc-acknowledgments doc-afterword
+	doc-appendix doc-backlink doc-biblioentry doc-bibliography doc-biblioref doc-chapter doc-colophon
+	doc-conclusion doc-cover doc-credit doc-credits doc-dedication doc-endnote doc-endnotes doc-epigraph
+	doc-epilogue doc-errata doc-example doc-footnote doc-foreword doc-glossary doc-glossref doc-index
+	doc-introduction doc-noteref doc-notice doc-pagebreak doc-pagelist doc-part doc-preface doc-prologue
+	doc-pullquote doc-qna doc-subtitle doc-tip doc-toc graphics-document graphics-object graphics-symbol`)
+
+// validARIAAttributes are the WAI-ARIA 1.2 states and properties
+var validARIAAttributes = strings.Fields(`aria-activedescendant aria-atomic aria-autocomplete aria-braillelabel
+	aria-brailleroledescription aria-busy aria-checked aria-colcount aria-colindex aria-colindextext aria-colspan
+	aria-controls aria-current aria-describedby aria-description aria-details aria-disabled aria-dropeffect
+	aria-errormessage aria-expanded aria-flowto aria-grabbed aria-haspopup aria-hidden aria-invalid
+	aria-keyshortcuts aria-label aria-labelledby aria-level aria-live aria-modal aria-multiline
+	aria-multiselectable aria-orientation aria-owns aria-placeholder aria-posinset aria-pressed aria-readonly
+	aria-relevant aria-required aria-roledescription aria-rowcount aria-rowindex aria-rowindextext aria-rowspan
+	aria-selected aria-setsize aria-sort aria-valuemax aria-valuemin aria-valuenow aria-valuetext`)
+
+// accessibilityRuleWeights are the points each rule contributes to the accessibility score
+var accessibilityRuleWeights = map[string]float64{
+	RuleColorContrast:   20,
+	RuleFormLabel:       15,
+	RuleAccessibleName:  15,
+	RuleARIA:            15,
+	RuleLandmarks:       10,
+	RuleHiddenFocusable: 5,
+	RuleTabindex:        5,
+	RuleKeyboard:        15,
+}
+
+// auditAccessibility checks color contrast, form labels, accessible names, ARIA usage, landmarks,
+// focusable elements hidden from assistive technology and tabindex anti-patterns, then tabs
+// through the page to audit keyboard navigation
+func (a *SEOAuditor) auditAccessibility(page playwright.Page) AccessibilityScore {
+	score := AccessibilityScore{
+		MaxScore:       100,
+		ContrastIssues: []ContrastIssue{},
+		Violations:     []AccessibilityViolation{},
+		RuleCounts:     map[string]int{},
+		Issues:         []string{},
+	}
+
+	var result accessibilityResult
+	raw, err := page.Evaluate(accessibilityScript, map[string]interface{}{
+		"roles":              validARIARoles,
+		"attributes":         validARIAAttributes,
+		"maxContrastSamples": maxContrastSamples,
+	})
+	if err == nil {
+		err = decodeInto(raw, &result)
+	}
+	if err != nil {
+		score.Issues = append(score.Issues, fmt.Sprintf("Unable to run accessibility checks: %v", err))
+		return score
+	}
+
+	// Color contrast
+	for _, sample := range result.Contrast {
+		issue, measured := checkContrast(sample)
+		if !measured {
+			continue
+		}
+		score.ContrastChecked++
+		if issue != nil {
+			score.RuleCounts[RuleColorContrast]++
+			score.ContrastIssues = append(score.ContrastIssues, *issue)
+		}
+	}
+	sort.SliceStable(score.ContrastIssues, func(i, j int) bool {
+		return score.ContrastIssues[i].Ratio < score.ContrastIssues[j].Ratio
+	})
+	if len(score.ContrastIssues) > maxReportedViolations {
+		score.ContrastIssues = score.ContrastIssues[:maxReportedViolations]
+	}
+	if score.ContrastChecked > 0 {
+		passing := 1 - float64(score.RuleCounts[RuleColorContrast])/float64(score.ContrastChecked)
+		score.Score += accessibilityRuleWeights[RuleColorContrast] * passing
+	} else {
+		score.Score += accessibilityRuleWeights[RuleColorContrast]
+	}
+	if count := score.RuleCounts[RuleColorContrast]; count > 0 {
+		score.Issues = append(score.Issues, fmt.Sprintf("%d of %d text elements have insufficient color contrast", count, score.ContrastChecked))
+	}
+
+	// Landmarks
+	score.Landmarks = result.Landmarks
+	landmarkProblems := landmarkIssues(result.Landmarks)
+	for _, problem := range landmarkProblems {
+		score.RuleCounts[RuleLandmarks]++
+		score.Issues = append(score.Issues, problem)
+	}
+	score.Score += ruleScore(RuleLandmarks, len(landmarkProblems))
+
+	// Element rules
+	listed := map[string]int{}
+	for _, violation := range result.Violations {
+		score.RuleCounts[violation.Rule]++
+		if listed[violation.Rule] < maxReportedViolations {
+			listed[violation.Rule]++
+			score.Violations = append(score.Violations, violation)
+		}
+	}
+	for _, rule := range []struct {
+		name    string
+		message string
+	}{
+		{RuleFormLabel, "%d form control(s) have no label"},
+		{RuleAccessibleName, "%d link(s) or button(s) have no accessible name"},
+		{RuleARIA, "%d ARIA role or attribute error(s)"},
+		{RuleHiddenFocusable, "%d focusable element(s) are hidden from assistive technology"},
+		{RuleTabindex, "%d tabindex anti-pattern(s)"},
+	} {
+		count := score.RuleCounts[rule.name]
+		score.Score += ruleScore(rule.name, count)
+		if count > 0 {
+			score.Issues = append(score.Issues, fmt.Sprintf(rule.message, count))
+		}
+	}
+
+	// Keyboard navigation moves focus, so it goes last
+	score.Keyboard = auditKeyboardNavigation(page)
+	score.RuleCounts[RuleKeyboard] = score.Keyboard.problems()
+	score.Score += ruleScore(RuleKeyboard, score.RuleCounts[RuleKeyboard])
+	score.Issues = append(score.Issues, score.Keyboard.Issues...)
+
+	score.Score = math.Round(score.Score*10) / 10
+	return score
+}
+
+// ruleScore awards a rule's full weight without violations, half for one or two, and nothing beyond
+func ruleScore(rule string, violations int) float64 {
+	switch {
+	case violations == 0:
+		return accessibilityRuleWeights[rule]
+	case violations <= 2:
+		return accessibilityRuleWeights[rule] / 2
+	}
+	return 0
+}
+
+// landmarkIssues explains what is wrong with the page's landmark structure
+func landmarkIssues(landmarks LandmarkSummary) []string {
+	issues := []string{}
+	switch {
+	case landmarks.Main == 0:
+		issues = append(issues, "No main landmark")
+	case landmarks.Main > 1:
+		issues = append(issues, fmt.Sprintf("%d main landmarks; a page should have exactly one", landmarks.Main))
+	}
+	if landmarks.Banner > 1 {
+		issues = append(issues, fmt.Sprintf("%d top-level banner landmarks (header)", landmarks.Banner))
+	}
+	if landmarks.ContentInfo > 1 {
+		issues = append(issues, fmt.Sprintf("%d top-level contentinfo landmarks (footer)", landmarks.ContentInfo))
+	}
+	if landmarks.UnlabeledNavs > 0 {
+		issues = append(issues, fmt.Sprintf("%d of %d navigation landmarks have no label to tell them apart", landmarks.UnlabeledNavs, landmarks.Navigation))
+	}
+	if landmarks.OutsideText > 50 {
+		issues = append(issues, fmt.Sprintf("%d words of content are outside any landmark", landmarks.OutsideText))
+	}
+	return issues
+}
+
+var cssColorPattern = regexp.MustCompile(`rgba?\(\s*([\d.]+)[,\s]+([\d.]+)[,\s]+([\d.]+)(?:\s*[,/]\s*([\d.]+%?))?\s*\)`)
+
+// rgba is a color with components in 0-255 and alpha in 0-1
+type rgba struct {
+	r, g, b, a float64
+}
+
+// parseCSSColor parses the rgb() and rgba() colors getComputedStyle returns
+func parseCSSColor(value string) (rgba, bool) {
+	match := cssColorPattern.FindStringSubmatch(value)
+	if match == nil {
+		return rgba{}, false
+	}
+	color := rgba{a: 1}
+	color.r, _ = strconv.ParseFloat(match[1], 64)
+	color.g, _ = strconv.ParseFloat(match[2], 64)
+	color.b, _ = strconv.ParseFloat(match[3], 64)
+	if alpha := match[4]; alpha != "" {
+		if strings.HasSuffix(alpha, "%") {
+			percent, _ := strconv.ParseFloat(strings.TrimSuffix(alpha, "%"), 64)
+			color.a = percent / 100
+		} else {
+			color.a, _ = strconv.ParseFloat(alpha, 64)
+		}
+	}
+	return color, true
+}
+
+// over composites a translucent color over an opaque background
+func (c rgba) over(background rgba) rgba {
+	return rgba{
+		r: c.r*c.a + background.r*(1-c.a),
+		g: c.g*c.a + background.g*(1-c.a),
+		b: c.b*c.a + background.b*(1-c.a),
+		a: 1,
+	}
+}
+
+// luminance is the WCAG relative luminance of an opaque color
+func (c rgba) luminance() float64 {
+	channel := func(v float64) float64 {
+		v /= 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.r) + 0.7152*channel(c.g) + 0.0722*channel(c.b)
+}
+
+func (c rgba) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", int(math.Round(c.r)), int(math.Round(c.g)), int(math.Round(c.b)))
+}
+
+// contrastRatio is the WCAG contrast ratio between two opaque colors, from 1 to 21
+func contrastRatio(a, b rgba) float64 {
+	la, lb := a.luminance(), b.luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// checkContrast measures a text sample against WCAG AA: 4.5:1, or 3:1 for large text (24px, or
+// 18.66px bold). It reports false when the background is an image and can't be measured.
+func checkContrast(sample contrastSample) (*ContrastIssue, bool) {
+	foreground, okForeground := parseCSSColor(sample.Color)
+	background, okBackground := parseCSSColor(sample.Background)
+	if !okForeground || !okBackground {
+		return nil, false
+	}
+	background = background.over(rgba{255, 255, 255, 1})
+	if sample.Opacity > 0 && sample.Opacity < 1 {
+		foreground.a *= sample.Opacity
+	}
+	foreground = foreground.over(background)
+
+	large := sample.FontSize >= 24 || (sample.FontSize >= 18.66 && sample.FontWeight >= 700)
+	required := 4.5
+	if large {
+		required = 3
+	}
+	ratio := contrastRatio(foreground, background)
+	if ratio >= required {
+		return nil, true
+	}
+	return &ContrastIssue{
+		Selector:   sample.Selector,
+		Text:       sample.Text,
+		Foreground: foreground.String(),
+		Background: background.String(),
+		Ratio:      math.Round(ratio*100) / 100,
+		Required:   required,
+		LargeText:  large,
+	}, true
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseCSSColor(t *testing.T) {
+	tests := []struct {
+		value string
+		want  rgba
+		ok    bool
+	}{
+		{"rgb(255, 0, 0)", rgba{255, 0, 0, 1}, true},
+		{"rgba(0, 0, 0, 0.5)", rgba{0, 0, 0, 0.5}, true},
+		{"rgb(10 20 30 / 50%)", rgba{10, 20, 30, 0.5}, true},
+		{"transparent", rgba{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseCSSColor(tt.value)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseCSSColor(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		a, b rgba
+		want float64
+	}{
+		{rgba{0, 0, 0, 1}, rgba{255, 255, 255, 1}, 21},
+		{rgba{255, 255, 255, 1}, rgba{0, 0, 0, 1}, 21},
+		{rgba{119, 119, 119, 1}, rgba{255, 255, 255, 1}, 4.48},
+		{rgba{128, 128, 128, 1}, rgba{128, 128, 128, 1}, 1},
+	}
+	for _, tt := range tests {
+		if got := contrastRatio(tt.a, tt.b); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("contrastRatio(%v, %v) = %.2f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckContrast(t *testing.T) {
+	tests := []struct {
+		name         string
+		sample       contrastSample
+		wantMeasured bool
+		wantRatio    float64 // 0 when the sample passes
+	}{
+		{"black on white", contrastSample{Color: "rgb(0, 0, 0)", Background: "rgb(255, 255, 255)", FontSize: 16}, true, 0},
+		{"grey body text", contrastSample{Color: "rgb(150, 150, 150)", Background: "rgb(255, 255, 255)", FontSize: 16}, true, 2.96},
+		{"grey large text", contrastSample{Color: "rgb(130, 130, 130)", Background: "rgb(255, 255, 255)", FontSize: 24}, true, 0},
+		{"translucent text", contrastSample{Color: "rgba(0, 0, 0, 0.3)", Background: "rgb(255, 255, 255)", FontSize: 16}, true, 2.11},
+		{"transparent background over white", contrastSample{Color: "rgb(0, 0, 0)", Background: "rgba(0, 0, 0, 0)", FontSize: 16}, true, 0},
+		{"unreadable colors", contrastSample{Color: "currentcolor", Background: "rgb(255, 255, 255)"}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue, measured := checkContrast(tt.sample)
+			if measured != tt.wantMeasured {
+				t.Fatalf("measured = %v, want %v", measured, tt.wantMeasured)
+			}
+			switch {
+			case tt.wantRatio == 0 && issue != nil:
+				t.Errorf("unexpected issue %+v", issue)
+			case tt.wantRatio > 0 && (issue == nil || math.Abs(issue.Ratio-tt.wantRatio) > 0.01):
+				t.Errorf("issue = %+v, want ratio %.2f", issue, tt.wantRatio)
+			}
+		})
+	}
+}
+
+func TestRuleScore(t *testing.T) {
+	weight := accessibilityRuleWeights[RuleAccessibleName]
+	tests := []struct {
+		violations int
+		want       float64
+	}{
+		{0, weight},
+		{1, weight / 2},
+		{2, weight / 2},
+		{3, 0},
+	}
+	for _, tt := range tests {
+		if got := ruleScore(RuleAccessibleName, tt.violations); got != tt.want {
+			t.Errorf("ruleScore(%d) = %v, want %v", tt.violations, got, tt.want)
+		}
+	}
+}
+
+func TestLandmarkIssues(t *testing.T) {
+	tests := []struct {
+		name      string
+		landmarks LandmarkSummary
+		want      []string
+	}{
+		{
+			name:      "well structured",
+			landmarks: LandmarkSummary{Main: 1, Banner: 1, ContentInfo: 1, Navigation: 2, OutsideText: 10},
+			want:      []string{},
+		},
+		{
+			name:      "no main",
+			landmarks: LandmarkSummary{Banner: 1},
+			want:      []string{"No main landmark"},
+		},
+		{
+			name:      "everything wrong",
+			landmarks: LandmarkSummary{Main: 2, Banner: 2, ContentInfo: 3, Navigation: 3, UnlabeledNavs: 2, OutsideText: 120},
+			want: []string{
+				"2 main landmarks; a page should have exactly one",
+				"2 top-level banner landmarks (header)",
+				"3 top-level contentinfo landmarks (footer)",
+				"2 of 3 navigation landmarks have no label to tell them apart",
+				"120 words of content are outside any landmark",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := landmarkIssues(tt.landmarks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("landmarkIssues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
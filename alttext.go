@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"golang.org/x/image/draw"
+)
+
+// maxAltTextLength is the longest alt text screen readers announce comfortably
+const maxAltTextLength = 125
+
+// maxAltThumbnails caps how many flagged images are captured as thumbnails
+const maxAltThumbnails = 20
+
+// altThumbnailSize is the longest side of an alt text thumbnail, in pixels
+const altThumbnailSize = 96
+
+// decorativeImageSize is the width and height at or below which an image is treated as an icon or spacer
+const decorativeImageSize = 32
+
+// Alt text statuses
+const (
+	AltStatusGood           = "good"
+	AltStatusMissing        = "missing"
+	AltStatusDecorative     = "decorative"      // alt="" on an image that is decorative
+	AltStatusEmpty          = "empty"           // alt="" on an image that conveys content
+	AltStatusFilename       = "filename"        // e.g. "IMG_0042.jpg"
+	AltStatusDuplicate      = "duplicate"       // Same alt as images showing something else
+	AltStatusKeywordStuffed = "keyword_stuffed" // Repeated words or a list of search terms
+	AltStatusTooLong        = "too_long"
+)
+
+// AltTextReport classifies the alt text of every image of the page
+type AltTextReport struct {
+	Images []ImageAltText `json:"images"`
+	Counts map[string]int `json:"counts"` // Images per status
+}
+
+// ImageAltText is the alt text analysis of one image
+type ImageAltText struct {
+	Src             string   `json:"src"`
+	Selector        string   `json:"selector"`
+	Alt             string   `json:"alt"`
+	Status          string   `json:"status"`
+	LooksDecorative bool     `json:"looks_decorative"`
+	Problems        []string `json:"problems"`
+	Thumbnail       string   `json:"thumbnail,omitempty"` // JPEG data URI of the rendered image
+	InMainContent   bool     `json:"in_main_content"`
+}
+
+// acceptable reports whether the image's alt text needs no changes
+func (i ImageAltText) acceptable() bool {
+	return i.Status == AltStatusGood || i.Status == AltStatusDecorative
+}
+
+// imageAltScript reads every image's alt attribute and the context that tells whether it is decorative
+const imageAltScript = `(mainSelector) => {
+	const selectorOf = ` + cssSelectorFunction + `;
+	const main = document.querySelector(mainSelector) || document.body;
+	return Array.from(document.images).map(img => {
+		const rect = img.getBoundingClientRect();
+		const role = (img.getAttribute('role') || '').toLowerCase();
+		const control = img.closest('a[href], button');
+		let controlText = '';
+		if (control) {
+			const clone = control.cloneNode(true);
+			clone.querySelectorAll('img').forEach(i => i.remove());
+			controlText = ((clone.textContent || '') + ' ' + (control.getAttribute('aria-label') || '')).trim();
+		}
+		return {
+			src: img.currentSrc || img.src,
+			selector: selectorOf(img),
+			hasAlt: img.hasAttribute('alt'),
+			alt: (img.getAttribute('alt') || '').trim().replace(/\s+/g, ' '),
+			width: Math.round(rect.width),
+			height: Math.round(rect.height),
+			visible: rect.width > 0 && rect.height > 0,
+			hidden: role === 'presentation' || role === 'none' || !!img.closest('[aria-hidden="true"]'),
+			soleControlContent: !!control && controlText === '',
+			inFigure: !!img.closest('figure'),
+			inMainContent: main.contains(img)
+		};
+	});
+}`
+
+// imageAltEntry is one image returned by imageAltScript
+type imageAltEntry struct {
+	Src                string `json:"src"`
+	Selector           string `json:"selector"`
+	HasAlt             bool   `json:"hasAlt"`
+	Alt                string `json:"alt"`
+	Width              int    `json:"width"`
+	Height             int    `json:"height"`
+	Visible            bool   `json:"visible"`
+	Hidden             bool   `json:"hidden"`
+	SoleControlContent bool   `json:"soleControlContent"` // The image alone names a link or button
+	InFigure           bool   `json:"inFigure"`
+	InMainContent      bool   `json:"inMainContent"`
+}
+
+var (
+	imageFilenamePattern = regexp.MustCompile(`(?i)\.(jpe?g|png|gif|webp|avif|svg|bmp|tiff?)$`)
+	cameraNamePattern    = regexp.MustCompile(`(?i)^(img|image|dsc|dscn|dscf|pic|photo|picture|screenshot|screen shot|pxl|gopr)[\s_-]*\d+`)
+)
+
+// auditAltText classifies every image's alt text in one DOM pass: missing, empty on decorative or
+// content images, filename-like, duplicated across different images, keyword-stuffed or too long.
+// Flagged images get a thumbnail so reviewers can see what the alt text should describe. Images
+// inside mainSelector are marked so content scoring can leave out logos and navigation icons.
+func auditAltText(page playwright.Page, mainSelector, lang string) (AltTextReport, error) {
+	report := AltTextReport{
+		Images: []ImageAltText{},
+		Counts: map[string]int{},
+	}
+
+	var entries []imageAltEntry
+	result, err := page.Evaluate(imageAltScript, mainSelector)
+	if err != nil {
+		return report, err
+	}
+	if err := decodeInto(result, &entries); err != nil {
+		return report, err
+	}
+
+	// The same alt on images with different sources describes at least one of them wrongly
+	sourcesByAlt := map[string]map[string]bool{}
+	for _, entry := range entries {
+		if key := strings.ToLower(entry.Alt); key != "" {
+			if sourcesByAlt[key] == nil {
+				sourcesByAlt[key] = map[string]bool{}
+			}
+			sourcesByAlt[key][entry.Src] = true
+		}
+	}
+
+	thumbnails := 0
+	for _, entry := range entries {
+		result := classifyAltText(entry, len(sourcesByAlt[strings.ToLower(entry.Alt)]), lang)
+		if !result.acceptable() && entry.Visible && thumbnails < maxAltThumbnails {
+			if thumbnail, err := imageThumbnail(page, entry.Selector); err == nil {
+				result.Thumbnail = thumbnail
+				thumbnails++
+			}
+		}
+		report.Counts[result.Status]++
+		report.Images = append(report.Images, result)
+	}
+	return report, nil
+}
+
+// classifyAltText picks the status of one image's alt text. sharedSources is how many distinct
+// images use the same alt.
+func classifyAltText(entry imageAltEntry, sharedSources int, lang string) ImageAltText {
+	result := ImageAltText{
+		Src:             entry.Src,
+		Selector:        entry.Selector,
+		Alt:             entry.Alt,
+		LooksDecorative: looksDecorative(entry),
+		Problems:        []string{},
+		InMainContent:   entry.InMainContent,
+	}
+
+	switch {
+	case !entry.HasAlt:
+		result.Status = AltStatusMissing
+		result.Problems = append(result.Problems, "No alt attribute, so screen readers announce the file name")
+	case entry.Alt == "":
+		if result.LooksDecorative {
+			result.Status = AltStatusDecorative
+		} else {
+			result.Status = AltStatusEmpty
+			if entry.SoleControlContent {
+				result.Problems = append(result.Problems, "Empty alt on the only content of a link or button, which is left without a name")
+			} else {
+				result.Problems = append(result.Problems, "Empty alt marks a content image as decorative")
+			}
+		}
+	case isFilenameAlt(entry.Alt, entry.Src):
+		result.Status = AltStatusFilename
+		result.Problems = append(result.Problems, fmt.Sprintf("Alt text %q is a file name, not a description", entry.Alt))
+	case isKeywordStuffed(entry.Alt, lang):
+		result.Status = AltStatusKeywordStuffed
+		result.Problems = append(result.Problems, "Alt text repeats words or lists search terms instead of describing the image")
+	case len([]rune(entry.Alt)) > maxAltTextLength:
+		result.Status = AltStatusTooLong
+		result.Problems = append(result.Problems, fmt.Sprintf("Alt text is %d characters; keep it under %d and move details to a caption", len([]rune(entry.Alt)), maxAltTextLength))
+	case sharedSources > 1:
+		result.Status = AltStatusDuplicate
+		result.Problems = append(result.Problems, fmt.Sprintf("Alt text %q is used for %d different images", entry.Alt, sharedSources))
+	default:
+		result.Status = AltStatusGood
+	}
+	return result
+}
+
+// looksDecorative reports whether an image carries no content: hidden from assistive technology,
+// tiny, or not rendered. Linked images without other text and figures are never decorative.
+func looksDecorative(entry imageAltEntry) bool {
+	if entry.SoleControlContent || entry.InFigure {
+		return false
+	}
+	if entry.Hidden || !entry.Visible {
+		return true
+	}
+	return entry.Width <= decorativeImageSize && entry.Height <= decorativeImageSize
+}
+
+// isFilenameAlt reports whether alt text is a file name, a camera default or the image's own file name.
+// Hyphens and digits alone prove nothing: "state-of-the-art" and "2023-10-05" are fine alt text.
+func isFilenameAlt(alt, src string) bool {
+	if imageFilenamePattern.MatchString(alt) || cameraNamePattern.MatchString(alt) {
+		return true
+	}
+	base := path.Base(strings.SplitN(strings.SplitN(src, "?", 2)[0], "#", 2)[0])
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	return stem != "" && strings.EqualFold(alt, stem)
+}
+
+// isKeywordStuffed reports whether alt text repeats a content word three times or more, or is a
+// comma-separated list of four or more short terms
+func isKeywordStuffed(alt, lang string) bool {
+	stopwords := topicStopwordSets[lang]
+	counts := map[string]int{}
+	for _, word := range splitWords(strings.ToLower(alt), lang) {
+		if stopwords[word] || len([]rune(word)) < 3 {
+			continue
+		}
+		counts[word]++
+		if counts[word] >= 3 {
+			return true
+		}
+	}
+
+	terms := strings.FieldsFunc(alt, func(r rune) bool { return r == ',' || r == '|' || r == ';' })
+	if len(terms) < 4 {
+		return false
+	}
+	for _, term := range terms {
+		if len(strings.Fields(term)) > 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// imageThumbnail screenshots a rendered image and scales it down to a small JPEG data URI
+func imageThumbnail(page playwright.Page, selector string) (string, error) {
+	shot, err := page.Locator(selector).First().Screenshot(playwright.LocatorScreenshotOptions{
+		Type:    playwright.ScreenshotTypePng,
+		Timeout: playwright.Float(2000),
+	})
+	if err != nil {
+		return "", err
+	}
+	src, _, err := image.Decode(bytes.NewReader(shot))
+	if err != nil {
+		return "", err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > altThumbnailSize || height > altThumbnailSize {
+		if width >= height {
+			width, height = altThumbnailSize, max(1, height*altThumbnailSize/width)
+		} else {
+			width, height = max(1, width*altThumbnailSize/height), altThumbnailSize
+		}
+	}
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(thumbnail, thumbnail.Bounds(), src, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 70}); err != nil {
+		return "", err
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import "testing"
+
+func TestIsFilenameAlt(t *testing.T) {
+	tests := []struct {
+		alt, src string
+		want     bool
+	}{
+		{"IMG_0042.jpg", "https://example.com/a.jpg", true},
+		{"hero.png", "https://example.com/b.png", true},
+		{"DSC 1234", "https://example.com/c.jpg", true},
+		{"product-shot", "https://example.com/img/product-shot.webp?w=400", true},
+		{"hero_banner_2x", "https://example.com/img/hero_banner_2x.png", true},
+		{"state-of-the-art", "https://example.com/d.jpg", false},
+		{"covid-19", "https://example.com/e.jpg", false},
+		{"2023-10-05", "https://example.com/f.jpg", false},
+		{"A red bicycle leaning on a wall", "https://example.com/bike.jpg", false},
+	}
+	for _, tt := range tests {
+		if got := isFilenameAlt(tt.alt, tt.src); got != tt.want {
+			t.Errorf("isFilenameAlt(%q, %q) = %v, want %v", tt.alt, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestIsKeywordStuffed(t *testing.T) {
+	tests := []struct {
+		alt  string
+		want bool
+	}{
+		{"Cheap shoes, cheap shoes online, buy cheap shoes", true},
+		{"shoes, sneakers, boots, sandals", true},
+		{"A pair of running shoes on a wooden floor", false},
+		{"Red, white and blue flag", false},
+	}
+	for _, tt := range tests {
+		if got := isKeywordStuffed(tt.alt, "en"); got != tt.want {
+			t.Errorf("isKeywordStuffed(%q) = %v, want %v", tt.alt, got, tt.want)
+		}
+	}
+}
+
+func TestLooksDecorative(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry imageAltEntry
+		want  bool
+	}{
+		{"content image", imageAltEntry{Width: 600, Height: 400, Visible: true}, false},
+		{"icon", imageAltEntry{Width: 16, Height: 16, Visible: true}, true},
+		{"wide banner", imageAltEntry{Width: 800, Height: 20, Visible: true}, false},
+		{"tall sidebar strip", imageAltEntry{Width: 24, Height: 600, Visible: true}, false},
+		{"hidden from assistive technology", imageAltEntry{Width: 600, Height: 400, Visible: true, Hidden: true}, true},
+		{"not rendered", imageAltEntry{Width: 600, Height: 400}, true},
+		{"icon naming a link", imageAltEntry{Width: 16, Height: 16, Visible: true, SoleControlContent: true}, false},
+		{"figure", imageAltEntry{Width: 16, Height: 16, Visible: true, InFigure: true}, false},
+	}
+	for _, tt := range tests {
+		if got := looksDecorative(tt.entry); got != tt.want {
+			t.Errorf("%s: looksDecorative() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyAltText(t *testing.T) {
+	content := imageAltEntry{Src: "https://example.com/bike.jpg", HasAlt: true, Width: 600, Height: 400, Visible: true}
+	with := func(alt string) imageAltEntry {
+		entry := content
+		entry.Alt = alt
+		return entry
+	}
+	icon := imageAltEntry{Src: "https://example.com/icon.svg", HasAlt: true, Width: 16, Height: 16, Visible: true}
+	missing := content
+	missing.HasAlt = false
+
+	tests := []struct {
+		name   string
+		entry  imageAltEntry
+		shared int
+		want   string
+	}{
+		{"good", with("A red bicycle leaning on a wall"), 1, AltStatusGood},
+		{"missing", missing, 1, AltStatusMissing},
+		{"empty on a decorative icon", icon, 1, AltStatusDecorative},
+		{"empty on a content image", with(""), 1, AltStatusEmpty},
+		{"file name", with("IMG_0042.jpg"), 1, AltStatusFilename},
+		{"keyword stuffed", with("bike, bikes, cheap bikes, bike shop"), 1, AltStatusKeywordStuffed},
+		{"too long", with("A red bicycle with a wicker basket full of sunflowers leans against a weathered brick wall beside an open bakery door on a quiet morning street"), 1, AltStatusTooLong},
+		{"shared by different images", with("Bicycle"), 3, AltStatusDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := classifyAltText(tt.entry, tt.shared, "en")
+			if result.Status != tt.want {
+				t.Errorf("status = %q, want %q", result.Status, tt.want)
+			}
+			if result.acceptable() != (len(result.Problems) == 0) {
+				t.Errorf("acceptable() = %v with problems %q", result.acceptable(), result.Problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ConsentResult records how a cookie consent banner was handled before measurement
+type ConsentResult struct {
+	CMPDetected       bool            `json:"cmp_detected"`
+	CMP               string          `json:"cmp"`
+	RequestedAction   string          `json:"requested_action"`
+	Applied           bool            `json:"applied"`
+	BannerVisible     bool            `json:"banner_visible"` // Banner still visible when the checks ran
+	PreConsentCookies []ConsentCookie `json:"pre_consent_cookies,omitempty"`
+	Issues            []string        `json:"issues"`
+}
+
+// ConsentCookie describes a cookie that was present before consent was given
+type ConsentCookie struct {
+	Name       string `json:"name"`
+	Domain     string `json:"domain"`
+	ThirdParty bool   `json:"third_party"`
+	Session    bool   `json:"session"`
+}
+
+// consentPlatform describes how to find and answer a consent management platform banner
+type consentPlatform struct {
+	name   string
+	banner string
+	accept string
+	reject string
+}
+
+// consentPlatforms lists the consent management platforms we know how to handle
+var consentPlatforms = []consentPlatform{
+	{
+		name:   "OneTrust",
+		banner: "#onetrust-banner-sdk",
+		accept: "#onetrust-accept-btn-handler",
+		reject: "#onetrust-reject-all-handler",
+	},
+	{
+		name:   "Cookiebot",
+		banner: "#CybotCookiebotDialog",
+		accept: "#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll, #CybotCookiebotDialogBodyButtonAccept",
+		reject: "#CybotCookiebotDialogBodyButtonDecline",
+	},
+	{
+		name:   "Didomi",
+		banner: "#didomi-notice, #didomi-popup",
+		accept: "#didomi-notice-agree-button",
+		reject: "#didomi-notice-disagree-button",
+	},
+	{
+		name:   "Quantcast Choice",
+		banner: ".qc-cmp2-container",
+		accept: ".qc-cmp2-summary-buttons button[mode='primary']",
+		reject: ".qc-cmp2-summary-buttons button[mode='secondary']",
+	},
+	{
+		name:   "TrustArc",
+		banner: "#truste-consent-track, #truste-consent-content",
+		accept: "#truste-consent-button",
+		reject: "#truste-consent-required",
+	},
+	{
+		name:   "Usercentrics",
+		banner: "#usercentrics-root [data-testid='uc-banner-content'], #usercentrics-cmp-ui",
+		accept: "[data-testid='uc-accept-all-button']",
+		reject: "[data-testid='uc-deny-all-button']",
+	},
+	{
+		name:   "CookieYes",
+		banner: ".cky-consent-container",
+		accept: ".cky-btn-accept",
+		reject: ".cky-btn-reject",
+	},
+	{
+		name:   "Complianz",
+		banner: "#cmplz-cookiebanner-container .cmplz-cookiebanner",
+		accept: ".cmplz-btn.cmplz-accept",
+		reject: ".cmplz-btn.cmplz-deny",
+	},
+	{
+		name:   "Osano",
+		banner: ".osano-cm-dialog",
+		accept: ".osano-cm-accept-all",
+		reject: ".osano-cm-denyAll",
+	},
+	{
+		name:   "iubenda",
+		banner: "#iubenda-cs-banner",
+		accept: ".iubenda-cs-accept-btn",
+		reject: ".iubenda-cs-reject-btn",
+	},
+	{
+		name:   "Klaro",
+		banner: ".klaro .cookie-notice, .klaro .cookie-modal",
+		accept: ".klaro .cm-btn-success, .klaro .cm-btn-accept-all",
+		reject: ".klaro .cm-btn-decline",
+	},
+	{
+		name:   "Borlabs Cookie",
+		banner: "#BorlabsCookieBox",
+		accept: "#BorlabsCookieBox a._brlbs-btn-accept-all",
+		reject: "#BorlabsCookieBox a._brlbs-refuse-btn, #BorlabsCookieBox ._brlbs-refuse",
+	},
+	{
+		name:   "Axeptio",
+		banner: "#axeptio_overlay",
+		accept: "#axeptio_btn_acceptAll",
+		reject: "#axeptio_btn_dismiss",
+	},
+}
+
+// genericConsentPlatform is used when no known platform matched but a cookie banner with
+// recognisable button labels is visible
+var genericConsentPlatform = consentPlatform{
+	name:   "Generic cookie banner",
+	banner: "[id*='cookie' i]:visible, [class*='cookie-banner' i]:visible, [class*='cookie-consent' i]:visible, [id*='consent' i]:visible, [aria-label*='cookie' i]:visible",
+	accept: `button:text-matches("^\\s*(accept|allow|agree|ok|aceitar|aceito|concordo|aceptar|acepto|akzeptieren|alle akzeptieren|zustimmen|accepter|tout accepter|j'accepte|accetta|accetto)(\\s+(all|todos|todas|tudo|tous|tout|alle|tutti))?(\\s+(cookies))?\\s*$", "i")`,
+	reject: `button:text-matches("^\\s*(reject|decline|deny|refuse|rejeitar|recusar|rechazar|ablehnen|alle ablehnen|refuser|tout refuser|rifiuta)(\\s+(all|todos|todas|tudo|tous|tout|alle|tutti))?(\\s+(cookies))?\\s*$", "i")`,
+}
+
+// handleConsent detects a consent banner and optionally accepts or rejects it before the checks run
+func (a *SEOAuditor) handleConsent(page playwright.Page, targetURL string, opts AuditOptions) ConsentResult {
+	result := ConsentResult{
+		RequestedAction: opts.Consent,
+		Issues:          []string{},
+	}
+
+	// Consent banners are usually injected after load, give them a moment to appear
+	selectors := []string{}
+	for _, p := range consentPlatforms {
+		selectors = append(selectors, p.banner)
+	}
+	page.Locator(strings.Join(selectors, ", ")).First().WaitFor(playwright.LocatorWaitForOptions{
+		State:   playwright.WaitForSelectorStateVisible,
+		Timeout: playwright.Float(3000),
+	})
+
+	platform, found := detectConsentPlatform(page)
+	result.CMPDetected = found
+	if found {
+		result.CMP = platform.name
+		result.BannerVisible = true
+	}
+
+	// Cookies must be read before any button is clicked
+	if opts.ReportPreConsentCookies {
+		result.PreConsentCookies = collectConsentCookies(page, targetURL)
+		if len(result.PreConsentCookies) > 0 {
+			thirdParty := 0
+			for _, cookie := range result.PreConsentCookies {
+				if cookie.ThirdParty {
+					thirdParty++
+				}
+			}
+			result.Issues = append(result.Issues, fmt.Sprintf("%d cookies were set before consent was given (%d third-party)", len(result.PreConsentCookies), thirdParty))
+		}
+	}
+
+	if !found {
+		if opts.Consent != ConsentNone {
+			result.Issues = append(result.Issues, "No consent banner detected, consent action was not applied")
+		}
+		return result
+	}
+
+	if opts.Consent == ConsentNone {
+		result.Issues = append(result.Issues, fmt.Sprintf("Consent banner (%s) was left open, layout and content metrics include the banner", platform.name))
+		return result
+	}
+
+	selector := platform.accept
+	if opts.Consent == ConsentReject {
+		selector = platform.reject
+	}
+
+	button := page.Locator(selector).First()
+	if visible, _ := button.IsVisible(); !visible {
+		result.Issues = append(result.Issues, fmt.Sprintf("Could not find the %s button for %s", opts.Consent, platform.name))
+		return result
+	}
+
+	if err := button.Click(playwright.LocatorClickOptions{Timeout: playwright.Float(5000)}); err != nil {
+		result.Issues = append(result.Issues, fmt.Sprintf("Failed to %s consent on %s: %v", opts.Consent, platform.name, err))
+		return result
+	}
+	result.Applied = true
+
+	// Wait for the banner to go away and for any scripts unlocked by consent to settle
+	page.Locator(platform.banner).First().WaitFor(playwright.LocatorWaitForOptions{
+		State:   playwright.WaitForSelectorStateHidden,
+		Timeout: playwright.Float(5000),
+	})
+	page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+		State:   playwright.LoadStateNetworkidle,
+		Timeout: playwright.Float(10000),
+	})
+
+	result.BannerVisible, _ = page.Locator(platform.banner).First().IsVisible()
+	if result.BannerVisible {
+		result.Issues = append(result.Issues, fmt.Sprintf("Consent banner (%s) is still visible after trying to %s", platform.name, opts.Consent))
+	}
+
+	return result
+}
+
+// detectConsentPlatform returns the first consent platform whose banner is visible
+func detectConsentPlatform(page playwright.Page) (consentPlatform, bool) {
+	for _, p := range consentPlatforms {
+		if visible, _ := page.Locator(p.banner).First().IsVisible(); visible {
+			return p, true
+		}
+	}
+
+	// Fall back to any visible cookie banner that has a recognisable accept button
+	banners, _ := page.Locator(genericConsentPlatform.banner).Count()
+	if banners > 0 {
+		if visible, _ := page.Locator(genericConsentPlatform.accept).First().IsVisible(); visible {
+			return genericConsentPlatform, true
+		}
+	}
+
+	return consentPlatform{}, false
+}
+
+// collectConsentCookies lists the cookies currently stored in the page's browser context
+func collectConsentCookies(page playwright.Page, targetURL string) []ConsentCookie {
+	cookies, err := page.Context().Cookies()
+	if err != nil {
+		return nil
+	}
+
+	parsedURL, _ := url.Parse(targetURL)
+	host := ""
+	if parsedURL != nil {
+		host = parsedURL.Hostname()
+	}
+
+	result := []ConsentCookie{}
+	for _, cookie := range cookies {
+		domain := strings.TrimPrefix(cookie.Domain, ".")
+		result = append(result, ConsentCookie{
+			Name:       cookie.Name,
+			Domain:     cookie.Domain,
+			ThirdParty: host != "" && host != domain && !strings.HasSuffix(host, "."+domain),
+			Session:    cookie.Expires <= 0,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,477 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// breadcrumbSelector locates visible breadcrumb navigation
+const breadcrumbSelector = "[itemtype*='BreadcrumbList'], nav[aria-label*='readcrumb'], .breadcrumb"
+
+// Consistency checks recorded in ConsistencyCheck.Check
+const (
+	ConsistencyBreadcrumb   = "breadcrumb"
+	ConsistencyPrice        = "price"
+	ConsistencyAvailability = "availability"
+	ConsistencyHeadline     = "headline"
+	ConsistencyOrganization = "organization name"
+)
+
+// ConsistencyCheck compares a structured data value with what the page visibly shows
+type ConsistencyCheck struct {
+	Check      string `json:"check"`
+	Item       string `json:"item"`
+	Structured string `json:"structured"`
+	Visible    string `json:"visible"`
+	Consistent bool   `json:"consistent"`
+}
+
+// visibleContent is what a visitor sees for the values structured data describes
+type visibleContent struct {
+	Title          string              `json:"title"`
+	H1             []string            `json:"h1"`
+	Breadcrumbs    []visibleBreadcrumb `json:"breadcrumbs"`
+	BreadcrumbText string              `json:"breadcrumbText"`
+	Prices         []string            `json:"prices"`
+	Availability   []string            `json:"availability"`
+}
+
+type visibleBreadcrumb struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// visibleContentScript collects the visible title, headings, breadcrumb links, prices and stock status.
+// Values come from rendered text only, so microdata attributes are never compared with themselves.
+const visibleContentScript = `(breadcrumbSelector) => {
+	const visible = (el) => {
+		const style = window.getComputedStyle(el);
+		const rect = el.getBoundingClientRect();
+		return style.display !== 'none' && style.visibility !== 'hidden' && rect.width > 0 && rect.height > 0;
+	};
+	const text = (el) => (el.innerText || el.textContent || '').trim().replace(/\s+/g, ' ');
+
+	const crumbs = Array.from(document.querySelectorAll(breadcrumbSelector)).filter(visible);
+	const breadcrumbs = [];
+	crumbs.forEach(container => {
+		container.querySelectorAll('a[href]').forEach(a => breadcrumbs.push({ text: text(a), url: a.href }));
+	});
+
+	const prices = [];
+	document.querySelectorAll('[itemprop="price"], [data-price], [class*="price" i], [id*="price" i]').forEach(el => {
+		if (prices.length >= 30 || !visible(el)) return;
+		// Only rendered text counts; content and data attributes are markup, not what shoppers see
+		const value = text(el);
+		if (value && value.length <= 60 && /\d/.test(value)) prices.push(value);
+	});
+
+	const availability = [];
+	document.querySelectorAll('[itemprop="availability"], [class*="stock" i], [class*="availability" i]').forEach(el => {
+		if (availability.length >= 10 || !visible(el)) return;
+		const value = text(el);
+		if (value && value.length <= 100) availability.push(value);
+	});
+
+	return {
+		title: (document.title || '').trim(),
+		h1: Array.from(document.querySelectorAll('h1')).filter(visible).map(text).filter(Boolean),
+		breadcrumbs: breadcrumbs,
+		breadcrumbText: crumbs.map(text).join(' '),
+		prices: prices,
+		availability: availability
+	};
+}`
+
+// availabilityStates maps schema.org ItemAvailability values and visible wording to a stock state.
+// Phrases match as substrings, so negations such as "not available" and "indisponible" sit with
+// out of stock, which is checked before the in stock phrases they contain.
+var availabilityStates = []struct {
+	state   string
+	phrases []string
+}{
+	{"out of stock", []string{"outofstock", "soldout", "discontinued", "out of stock", "sold out", "unavailable", "not available", "no longer available", "not in stock",
+		"esgotado", "indisponível", "não disponível", "sem estoque", "agotado", "no disponible", "sin stock", "ausverkauft", "nicht lieferbar", "nicht mehr lieferbar", "nicht verfügbar", "nicht auf lager",
+		"rupture de stock", "épuisé", "indisponible", "non disponible", "non disponibile", "esaurito", "niet leverbaar", "uitverkocht"}},
+	{"pre-order", []string{"preorder", "presale", "pre-order", "pre order", "backorder", "coming soon"}},
+	{"in stock", []string{"instock", "instoreonly", "onlineonly", "limitedavailability", "in stock", "available", "em estoque", "disponible", "auf lager", "lieferbar"}},
+}
+
+var priceNumberPattern = regexp.MustCompile(`\d[\d.,\s]*`)
+
+// checkContentConsistency cross-checks structured data values against the rendered page
+func (d *structuredData) checkContentConsistency(page playwright.Page) ([]ConsistencyCheck, error) {
+	var content visibleContent
+	result, err := page.Evaluate(visibleContentScript, breadcrumbSelector)
+	if err != nil {
+		return nil, fmt.Errorf("could not read visible content: %v", err)
+	}
+	if err := decodeInto(result, &content); err != nil {
+		return nil, fmt.Errorf("could not read visible content: %v", err)
+	}
+
+	checks := []ConsistencyCheck{}
+	seen := map[string]bool{}
+	add := func(check ConsistencyCheck) {
+		key := check.Check + "\x00" + check.Structured
+		if !seen[key] {
+			seen[key] = true
+			checks = append(checks, check)
+		}
+	}
+
+	for _, entity := range d.entities {
+		switch {
+		case entityMatchesTypes(entity, []string{"BreadcrumbList"}):
+			add(d.checkBreadcrumb(entity, content))
+		case entityMatchesTypes(entity, []string{"Product"}):
+			if check, ok := d.checkPrice(entity, content); ok {
+				add(check)
+			}
+			if check, ok := d.checkAvailability(entity, content); ok {
+				add(check)
+			}
+		case entityMatchesTypes(entity, []string{"Article"}):
+			for _, headline := range d.stringValues(entity.props, "headline") {
+				add(ConsistencyCheck{
+					Check:      ConsistencyHeadline,
+					Item:       entity.Path,
+					Structured: headline,
+					Visible:    strings.Join(content.H1, " / "),
+					Consistent: anyTextMatches(headline, content.H1),
+				})
+			}
+		case entityMatchesTypes(entity, []string{"Organization"}) && !entity.nested:
+			for _, name := range d.stringValues(entity.props, "name") {
+				add(ConsistencyCheck{
+					Check:      ConsistencyOrganization,
+					Item:       entity.Path,
+					Structured: name,
+					Visible:    content.Title,
+					Consistent: normalizeText(name) != "" && strings.Contains(normalizeText(content.Title), normalizeText(name)),
+				})
+			}
+		}
+	}
+
+	return checks, nil
+}
+
+// checkBreadcrumb verifies every BreadcrumbList item appears in the visible breadcrumb navigation
+func (d *structuredData) checkBreadcrumb(entity *SchemaEntity, content visibleContent) ConsistencyCheck {
+	type crumb struct {
+		position float64
+		name     string
+		url      string
+	}
+	crumbs := []crumb{}
+	for _, item := range d.itemValues(entity.props, "itemListElement") {
+		c := crumb{position: float64(len(crumbs) + 1)}
+		if positions := d.stringValues(item, "position"); len(positions) > 0 {
+			if p, err := strconv.ParseFloat(positions[0], 64); err == nil {
+				c.position = p
+			}
+		}
+		if names := d.stringValues(item, "name"); len(names) > 0 {
+			c.name = names[0]
+		} else if names := d.stringValues(item, "item.name"); len(names) > 0 {
+			c.name = names[0]
+		}
+		if urls := d.stringValues(item, "item"); len(urls) > 0 {
+			c.url = urls[0]
+		} else if urls := d.stringValues(item, "item.@id"); len(urls) > 0 {
+			c.url = urls[0]
+		} else if urls := d.stringValues(item, "item.url"); len(urls) > 0 {
+			c.url = urls[0]
+		}
+		crumbs = append(crumbs, c)
+	}
+	sort.SliceStable(crumbs, func(i, j int) bool { return crumbs[i].position < crumbs[j].position })
+
+	structured := []string{}
+	missing := []string{}
+	for _, c := range crumbs {
+		structured = append(structured, c.name)
+		if !content.hasBreadcrumb(c.name, c.url) {
+			missing = append(missing, c.name)
+		}
+	}
+
+	visible := []string{}
+	for _, link := range content.Breadcrumbs {
+		visible = append(visible, link.Text)
+	}
+
+	check := ConsistencyCheck{
+		Check:      ConsistencyBreadcrumb,
+		Item:       entity.Path,
+		Structured: strings.Join(structured, " > "),
+		Visible:    strings.Join(visible, " > "),
+		Consistent: len(missing) == 0 && content.BreadcrumbText != "",
+	}
+	if check.Visible == "" {
+		check.Visible = content.BreadcrumbText
+	}
+	return check
+}
+
+// hasBreadcrumb reports whether a breadcrumb item is shown, matched by link URL, link text or the unlinked current page
+func (c visibleContent) hasBreadcrumb(name, link string) bool {
+	for _, visible := range c.Breadcrumbs {
+		if link != "" && sameURLPath(link, visible.URL) {
+			return true
+		}
+		if name != "" && normalizeText(name) == normalizeText(visible.Text) {
+			return true
+		}
+	}
+	return name != "" && strings.Contains(normalizeText(c.BreadcrumbText), normalizeText(name))
+}
+
+// checkPrice verifies a Product's structured price is shown on the page
+func (d *structuredData) checkPrice(entity *SchemaEntity, content visibleContent) (ConsistencyCheck, bool) {
+	prices := d.stringValues(entity.props, "offers.price")
+	prices = append(prices, d.stringValues(entity.props, "offers.lowPrice")...)
+	if len(prices) == 0 {
+		return ConsistencyCheck{}, false
+	}
+
+	check := ConsistencyCheck{
+		Check:      ConsistencyPrice,
+		Item:       entity.Path,
+		Structured: strings.Join(prices, ", "),
+		Visible:    strings.Join(content.Prices, ", "),
+	}
+	for _, price := range prices {
+		want, ok := parsePrice(price)
+		if !ok {
+			continue
+		}
+		for _, visible := range content.Prices {
+			if got, ok := parsePrice(visible); ok && got-want < 0.005 && want-got < 0.005 {
+				check.Consistent = true
+			}
+		}
+	}
+	return check, true
+}
+
+// checkAvailability verifies a Product's structured availability matches the stock status shown on the page
+func (d *structuredData) checkAvailability(entity *SchemaEntity, content visibleContent) (ConsistencyCheck, bool) {
+	values := d.stringValues(entity.props, "offers.availability")
+	if len(values) == 0 {
+		return ConsistencyCheck{}, false
+	}
+	structured := availabilityState(values[0])
+
+	visibleStates := []string{}
+	for _, text := range content.Availability {
+		if state := availabilityState(text); state != "" {
+			visibleStates = append(visibleStates, state)
+		}
+	}
+	// Nothing on the page states stock status, so there is nothing to contradict
+	if structured == "" || len(visibleStates) == 0 {
+		return ConsistencyCheck{}, false
+	}
+
+	check := ConsistencyCheck{
+		Check:      ConsistencyAvailability,
+		Item:       entity.Path,
+		Structured: structured,
+		Visible:    strings.Join(uniqueStrings(visibleStates), ", "),
+	}
+	for _, state := range visibleStates {
+		if state == structured {
+			check.Consistent = true
+		}
+	}
+	return check, true
+}
+
+// availabilityState maps an ItemAvailability URL or visible stock text to a stock state
+func availabilityState(value string) string {
+	value = strings.ToLower(value)
+	if i := strings.LastIndex(value, "/"); i >= 0 && strings.Contains(value, "schema.org") {
+		value = value[i+1:]
+	}
+	for _, candidate := range availabilityStates {
+		for _, phrase := range candidate.phrases {
+			if strings.Contains(value, phrase) {
+				return candidate.state
+			}
+		}
+	}
+	return ""
+}
+
+// parsePrice reads a price such as "$1,299.00", "1.299,00 €", "1.299 €" or "19.9" into a number
+func parsePrice(text string) (float64, bool) {
+	number := strings.Join(strings.Fields(priceNumberPattern.FindString(text)), "")
+	number = strings.TrimRight(number, ".,")
+	if number == "" {
+		return 0, false
+	}
+
+	lastComma := strings.LastIndex(number, ",")
+	lastDot := strings.LastIndex(number, ".")
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		// Whichever separator comes last is the decimal separator
+		if lastComma > lastDot {
+			number = strings.ReplaceAll(number, ".", "")
+			number = strings.Replace(number, ",", ".", 1)
+		} else {
+			number = strings.ReplaceAll(number, ",", "")
+		}
+	case lastComma >= 0:
+		// A single comma followed by one or two digits is a decimal comma
+		if strings.Count(number, ",") == 1 && len(number)-lastComma-1 <= 2 {
+			number = strings.Replace(number, ",", ".", 1)
+		} else {
+			number = strings.ReplaceAll(number, ",", "")
+		}
+	case strings.Count(number, ".") > 1:
+		number = strings.ReplaceAll(number, ".", "")
+	case lastDot >= 0 && len(number)-lastDot-1 == 3 && !strings.HasPrefix(number, "0."):
+		// A single dot followed by three digits, as in "1.299", groups thousands
+		number = strings.Replace(number, ".", "", 1)
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	return value, err == nil
+}
+
+// stringValues returns the text and number values at a property path, following nested items and arrays
+func (d *structuredData) stringValues(props map[string]interface{}, path string) []string {
+	values := []string{}
+	for _, value := range d.pathValues(props, strings.Split(path, "."), 0) {
+		switch v := value.(type) {
+		case string:
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	return values
+}
+
+// itemValues returns the nested items at a property path
+func (d *structuredData) itemValues(props map[string]interface{}, path string) []map[string]interface{} {
+	items := []map[string]interface{}{}
+	for _, value := range d.pathValues(props, strings.Split(path, "."), 0) {
+		if item, ok := value.(map[string]interface{}); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// pathValues collects the values at a property path, flattening arrays and resolving @id references
+func (d *structuredData) pathValues(props map[string]interface{}, path []string, depth int) []interface{} {
+	if depth > 10 || len(path) == 0 {
+		return nil
+	}
+	var values []interface{}
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]interface{}:
+			v = d.resolve(v)
+			if len(path) == 1 {
+				values = append(values, v)
+			} else {
+				values = append(values, d.pathValues(v, path[1:], depth+1)...)
+			}
+		default:
+			if len(path) == 1 {
+				values = append(values, v)
+			}
+		}
+	}
+	collect(props[path[0]])
+	return values
+}
+
+// anyTextMatches reports whether text closely matches one of the candidates
+func anyTextMatches(text string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if textsMatch(text, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// textsMatch treats two texts as matching when one contains the other or most of their words overlap
+func textsMatch(a, b string) bool {
+	a, b = normalizeText(a), normalizeText(b)
+	if a == "" || b == "" {
+		return false
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return true
+	}
+	return contentSimilarity(a, b) >= 0.5 || wordOverlap(a, b) >= 0.8
+}
+
+// wordOverlap returns the share of the shorter text's words that also appear in the longer one
+func wordOverlap(a, b string) float64 {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+	if len(wordsA) == 0 {
+		return 0
+	}
+	set := map[string]bool{}
+	for _, word := range wordsB {
+		set[word] = true
+	}
+	shared := 0
+	for _, word := range wordsA {
+		if set[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA))
+}
+
+// sameURLPath compares two possibly relative URLs by host (when both have one) and path
+func sameURLPath(a, b string) bool {
+	ua, errA := url.Parse(strings.TrimSpace(a))
+	ub, errB := url.Parse(strings.TrimSpace(b))
+	if errA != nil || errB != nil {
+		return false
+	}
+	if ua.Host != "" && ub.Host != "" && !strings.EqualFold(strings.TrimPrefix(ua.Host, "www."), strings.TrimPrefix(ub.Host, "www.")) {
+		return false
+	}
+	pathA, pathB := strings.TrimSuffix(ua.Path, "/"), strings.TrimSuffix(ub.Path, "/")
+	return pathA == pathB && (ua.Host != "" || ua.Path != "")
+}
+
+// uniqueStrings removes duplicates while keeping order
+func uniqueStrings(items []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			unique = append(unique, item)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,101 @@
+package main
+
+import "testing"
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+		ok   bool
+	}{
+		{"$1,299.00", 1299, true},
+		{"1.299,00 €", 1299, true},
+		{"19.9", 19.9, true},
+		{"19,99 €", 19.99, true},
+		{"1,299", 1299, true},
+		{"1 299,50 kr", 1299.5, true},
+		{"1.234.567", 1234567, true},
+		{"1.299", 1299, true},
+		{"1.299 €", 1299, true},
+		{"19.99", 19.99, true},
+		{"0.125", 0.125, true},
+		{"Price: 49.", 49, true},
+		{"free", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parsePrice(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parsePrice(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAvailabilityState(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"https://schema.org/InStock", "in stock"},
+		{"http://schema.org/OutOfStock", "out of stock"},
+		{"https://schema.org/PreOrder", "pre-order"},
+		{"https://schema.org/LimitedAvailability", "in stock"},
+		{"Only 3 left in stock", "in stock"},
+		{"Sold out", "out of stock"},
+		{"Currently unavailable", "out of stock"},
+		{"Esgotado", "out of stock"},
+		{"Auf Lager", "in stock"},
+		{"Coming soon", "pre-order"},
+		{"Not available", "out of stock"},
+		{"Not in stock", "out of stock"},
+		{"Nicht lieferbar", "out of stock"},
+		{"Nicht verfügbar", "out of stock"},
+		{"Indisponible", "out of stock"},
+		{"Produit non disponible", "out of stock"},
+		{"No disponible", "out of stock"},
+		{"Não disponível", "out of stock"},
+		{"Disponible", "in stock"},
+		{"Sofort lieferbar", "in stock"},
+		{"Add to cart", ""},
+	}
+	for _, tt := range tests {
+		if got := availabilityState(tt.value); got != tt.want {
+			t.Errorf("availabilityState(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestTextsMatch(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Running Shoes", "running shoes", true},
+		{"Running Shoes | Acme", "Running Shoes", true},
+		{"The best running shoes for trail runners", "Best running shoes for trail runners in 2024", true},
+		{"Running Shoes", "Leather Boots", false},
+		{"", "Running Shoes", false},
+	}
+	for _, tt := range tests {
+		if got := textsMatch(tt.a, tt.b); got != tt.want {
+			t.Errorf("textsMatch(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSameURLPath(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://example.com/shoes/", "https://www.example.com/shoes", true},
+		{"/shoes", "https://example.com/shoes/", true},
+		{"https://example.com/shoes", "https://example.org/shoes", false},
+		{"https://example.com/shoes", "https://example.com/boots", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := sameURLPath(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameURLPath(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// minCoverageSavings is the unused transfer size from which a script or stylesheet is reported
+const minCoverageSavings = 20 * 1024
+
+// CoverageReport measures how much of the page's JavaScript and CSS is unused during the load
+type CoverageReport struct {
+	Scripts          []CoverageEntry `json:"scripts"`     // Ranked by estimated savings
+	Stylesheets      []CoverageEntry `json:"stylesheets"` // Ranked by estimated savings
+	TotalBytes       int64           `json:"total_bytes"`
+	UnusedBytes      int64           `json:"unused_bytes"`
+	FirstPartyUnused int64           `json:"first_party_unused_bytes"`
+	ThirdPartyUnused int64           `json:"third_party_unused_bytes"`
+	EstimatedSavings int64           `json:"estimated_savings_bytes"` // Unused bytes as transferred over the network
+	Issues           []string        `json:"issues"`
+}
+
+// CoverageEntry is the coverage of one script or stylesheet. Inline code is grouped per document.
+type CoverageEntry struct {
+	URL              string  `json:"url"`
+	Inline           bool    `json:"inline"`
+	FirstParty       bool    `json:"first_party"`
+	TotalBytes       int64   `json:"total_bytes"`
+	UnusedBytes      int64   `json:"unused_bytes"`
+	UnusedPercent    float64 `json:"unused_percent"`
+	TransferBytes    int64   `json:"transfer_bytes"`
+	EstimatedSavings int64   `json:"estimated_savings_bytes"`
+}
+
+// coverageRecorder collects Chromium's precise JavaScript coverage and CSS rule usage over CDP.
+// It must be started before navigating so top-level code and every stylesheet are tracked.
+type coverageRecorder struct {
+	session     playwright.CDPSession
+	mu          sync.Mutex
+	stylesheets map[string]cssStyleSheetHeader
+}
+
+// cssStyleSheetHeader is the part of CDP's CSS.CSSStyleSheetHeader the report needs
+type cssStyleSheetHeader struct {
+	StyleSheetID string  `json:"styleSheetId"`
+	SourceURL    string  `json:"sourceURL"`
+	IsInline     bool    `json:"isInline"`
+	Length       float64 `json:"length"`
+}
+
+// coverageRange is a range of a script or stylesheet with how often it ran or whether it matched
+type coverageRange struct {
+	StartOffset int  `json:"startOffset"`
+	EndOffset   int  `json:"endOffset"`
+	Count       int  `json:"count"`
+	Used        bool `json:"used"`
+}
+
+// startCoverage turns on JavaScript and CSS coverage for the page, or returns an error when the
+// browser doesn't support it
+func startCoverage(page playwright.Page) (*coverageRecorder, error) {
+	session, err := page.Context().NewCDPSession(page)
+	if err != nil {
+		return nil, err
+	}
+	recorder := &coverageRecorder{session: session, stylesheets: map[string]cssStyleSheetHeader{}}
+	session.On("CSS.styleSheetAdded", func(params map[string]interface{}) {
+		var event struct {
+			Header cssStyleSheetHeader `json:"header"`
+		}
+		if err := decodeInto(params, &event); err != nil {
+			return
+		}
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.stylesheets[event.Header.StyleSheetID] = event.Header
+	})
+
+	for _, command := range []struct {
+		method string
+		params map[string]interface{}
+	}{
+		{"Profiler.enable", nil},
+		{"Profiler.startPreciseCoverage", map[string]interface{}{"callCount": false, "detailed": true}},
+		{"DOM.enable", nil},
+		{"CSS.enable", nil},
+		{"CSS.startRuleUsageTracking", nil},
+	} {
+		if _, err := session.Send(command.method, command.params); err != nil {
+			session.Detach()
+			return nil, fmt.Errorf("%s: %v", command.method, err)
+		}
+	}
+	return recorder, nil
+}
+
+// resourceSizesScript reads the transferred and decoded size of each resource, to turn unused
+// decoded bytes into bytes saved on the network
+const resourceSizesScript = `() => performance.getEntriesByType('resource').map(r => ({
+	url: r.name,
+	transferSize: r.transferSize || 0,
+	decodedSize: r.decodedBodySize || 0
+}))`
+
+// analyzeCoverage stops coverage and reports the unused bytes of every script and stylesheet,
+// split between first- and third-party origins
+func analyzeCoverage(page playwright.Page, recorder *coverageRecorder) (*CoverageReport, error) {
+	defer recorder.session.Detach()
+
+	jsResult, err := recorder.session.Send("Profiler.takePreciseCoverage", nil)
+	if err != nil {
+		return nil, err
+	}
+	var js struct {
+		Result []struct {
+			URL       string `json:"url"`
+			Functions []struct {
+				Ranges []coverageRange `json:"ranges"`
+			} `json:"functions"`
+		} `json:"result"`
+	}
+	if err := decodeInto(jsResult, &js); err != nil {
+		return nil, err
+	}
+
+	cssResult, err := recorder.session.Send("CSS.stopRuleUsageTracking", nil)
+	if err != nil {
+		return nil, err
+	}
+	var css struct {
+		RuleUsage []struct {
+			StyleSheetID string `json:"styleSheetId"`
+			coverageRange
+		} `json:"ruleUsage"`
+	}
+	if err := decodeInto(cssResult, &css); err != nil {
+		return nil, err
+	}
+
+	var sizes []struct {
+		URL          string  `json:"url"`
+		TransferSize float64 `json:"transferSize"`
+		DecodedSize  float64 `json:"decodedSize"`
+	}
+	evaluateInto(page, resourceSizesScript, &sizes)
+	compression := map[string]float64{}
+	transfer := map[string]int64{}
+	for _, size := range sizes {
+		if size.DecodedSize > 0 && size.TransferSize > 0 {
+			compression[size.URL] = math.Min(1, size.TransferSize/size.DecodedSize)
+			transfer[size.URL] = int64(size.TransferSize)
+		}
+	}
+
+	pageURL := strings.SplitN(page.URL(), "#", 2)[0]
+	report := &CoverageReport{
+		Scripts:     []CoverageEntry{},
+		Stylesheets: []CoverageEntry{},
+		Issues:      []string{},
+	}
+
+	// Scripts: a byte is unused when the innermost range containing it never ran
+	scripts := map[string]*CoverageEntry{}
+	order := []string{}
+	for _, script := range js.Result {
+		if script.URL == "" || strings.HasPrefix(script.URL, "chrome-extension:") {
+			continue
+		}
+		ranges := []coverageRange{}
+		for _, function := range script.Functions {
+			ranges = append(ranges, function.Ranges...)
+		}
+		total, unused := unusedScriptLength(ranges)
+		if total == 0 {
+			continue
+		}
+		entry, ok := scripts[script.URL]
+		if !ok {
+			entry = &CoverageEntry{URL: script.URL, Inline: sameURL(script.URL, pageURL), FirstParty: isFirstParty(script.URL, pageURL)}
+			scripts[script.URL] = entry
+			order = append(order, script.URL)
+		}
+		entry.TotalBytes += int64(total)
+		entry.UnusedBytes += int64(unused)
+	}
+	for _, u := range order {
+		report.Scripts = append(report.Scripts, *scripts[u])
+	}
+
+	// Stylesheets: everything outside the rules that matched is unused
+	// CSS.styleSheetAdded keeps firing until the session is detached, so read a copy
+	recorder.mu.Lock()
+	headers := maps.Clone(recorder.stylesheets)
+	recorder.mu.Unlock()
+	usedRanges := map[string][]coverageRange{}
+	for _, usage := range css.RuleUsage {
+		if usage.Used {
+			usedRanges[usage.StyleSheetID] = append(usedRanges[usage.StyleSheetID], usage.coverageRange)
+		}
+	}
+	stylesheets := map[string]*CoverageEntry{}
+	order = []string{}
+	for id, header := range headers {
+		if header.Length <= 0 || header.SourceURL == "" {
+			continue
+		}
+		used := mergedLength(usedRanges[id])
+		key := header.SourceURL
+		entry, ok := stylesheets[key]
+		if !ok {
+			entry = &CoverageEntry{URL: header.SourceURL, Inline: header.IsInline, FirstParty: isFirstParty(header.SourceURL, pageURL)}
+			stylesheets[key] = entry
+			order = append(order, key)
+		}
+		entry.TotalBytes += int64(header.Length)
+		entry.UnusedBytes += int64(math.Max(0, header.Length-float64(used)))
+	}
+	sort.Strings(order)
+	for _, key := range order {
+		report.Stylesheets = append(report.Stylesheets, *stylesheets[key])
+	}
+
+	for _, entries := range [][]CoverageEntry{report.Scripts, report.Stylesheets} {
+		for i := range entries {
+			entry := &entries[i]
+			entry.UnusedPercent = math.Round(float64(entry.UnusedBytes)/float64(entry.TotalBytes)*1000) / 10
+			// Inline code travels with the document and external code is usually compressed
+			ratio, ok := compression[entry.URL]
+			if !ok || entry.Inline {
+				ratio = 1
+			}
+			entry.TransferBytes = transfer[entry.URL]
+			entry.EstimatedSavings = int64(float64(entry.UnusedBytes) * ratio)
+
+			report.TotalBytes += entry.TotalBytes
+			report.UnusedBytes += entry.UnusedBytes
+			report.EstimatedSavings += entry.EstimatedSavings
+			if entry.FirstParty {
+				report.FirstPartyUnused += entry.UnusedBytes
+			} else {
+				report.ThirdPartyUnused += entry.UnusedBytes
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].EstimatedSavings > entries[j].EstimatedSavings })
+	}
+
+	for _, kind := range []struct {
+		name    string
+		entries []CoverageEntry
+	}{
+		{"JavaScript", report.Scripts},
+		{"CSS", report.Stylesheets},
+	} {
+		savings, count := int64(0), 0
+		for _, entry := range kind.entries {
+			if entry.EstimatedSavings >= minCoverageSavings {
+				savings += entry.EstimatedSavings
+				count++
+			}
+		}
+		if count > 0 {
+			report.Issues = append(report.Issues, fmt.Sprintf("Reduce unused %s: %d file(s) could save about %s", kind.name, count, formatBytes(savings)))
+		}
+	}
+
+	return report, nil
+}
+
+// unusedScriptLength returns a script's length and how much of it never ran. V8's block coverage
+// nests ranges, so sorting outer before inner lets each inner range override its parent.
+func unusedScriptLength(ranges []coverageRange) (int, int) {
+	total := 0
+	for _, r := range ranges {
+		total = max(total, r.EndOffset)
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].StartOffset != ranges[j].StartOffset {
+			return ranges[i].StartOffset < ranges[j].StartOffset
+		}
+		return ranges[i].EndOffset > ranges[j].EndOffset
+	})
+	used := make([]bool, total)
+	for _, r := range ranges {
+		for i := max(0, r.StartOffset); i < r.EndOffset && i < total; i++ {
+			used[i] = r.Count > 0
+		}
+	}
+	unused := 0
+	for _, u := range used {
+		if !u {
+			unused++
+		}
+	}
+	return total, unused
+}
+
+// mergedLength is the length covered by a set of possibly overlapping ranges
+func mergedLength(ranges []coverageRange) int {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].StartOffset < ranges[j].StartOffset })
+	length, end := 0, -1
+	for _, r := range ranges {
+		start := max(r.StartOffset, end)
+		if r.EndOffset > start {
+			length += r.EndOffset - start
+			end = r.EndOffset
+		}
+	}
+	return length
+}
+
+// isFirstParty reports whether a resource is served from the page's host or one of its subdomains
+func isFirstParty(resourceURL, pageURL string) bool {
+	resource, errResource := url.Parse(resourceURL)
+	page, errPage := url.Parse(pageURL)
+	if errResource != nil || errPage != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(resource.Hostname()), "www.")
+	base := strings.TrimPrefix(strings.ToLower(page.Hostname()), "www.")
+	return host == base || strings.HasSuffix(host, "."+base)
+}
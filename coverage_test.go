@@ -0,0 +1,84 @@
+package main
+
+import "testing"
+
+func TestUnusedScriptLength(t *testing.T) {
+	tests := []struct {
+		name                  string
+		ranges                []coverageRange
+		wantTotal, wantUnused int
+	}{
+		{
+			name:      "fully used",
+			ranges:    []coverageRange{{StartOffset: 0, EndOffset: 100, Count: 1}},
+			wantTotal: 100,
+		},
+		{
+			name: "unused function inside a used script",
+			ranges: []coverageRange{
+				{StartOffset: 0, EndOffset: 100, Count: 1},
+				{StartOffset: 20, EndOffset: 50, Count: 0},
+			},
+			wantTotal:  100,
+			wantUnused: 30,
+		},
+		{
+			name: "used block inside an unused function",
+			ranges: []coverageRange{
+				{StartOffset: 20, EndOffset: 50, Count: 0},
+				{StartOffset: 0, EndOffset: 100, Count: 1},
+				{StartOffset: 30, EndOffset: 40, Count: 1},
+			},
+			wantTotal:  100,
+			wantUnused: 20,
+		},
+		{
+			name:   "no ranges",
+			ranges: []coverageRange{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, unused := unusedScriptLength(tt.ranges)
+			if total != tt.wantTotal || unused != tt.wantUnused {
+				t.Errorf("unusedScriptLength() = %d, %d, want %d, %d", total, unused, tt.wantTotal, tt.wantUnused)
+			}
+		})
+	}
+}
+
+func TestMergedLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []coverageRange
+		want   int
+	}{
+		{"empty", nil, 0},
+		{"disjoint", []coverageRange{{StartOffset: 0, EndOffset: 10}, {StartOffset: 20, EndOffset: 25}}, 15},
+		{"overlapping", []coverageRange{{StartOffset: 5, EndOffset: 15}, {StartOffset: 0, EndOffset: 10}}, 15},
+		{"nested", []coverageRange{{StartOffset: 0, EndOffset: 30}, {StartOffset: 10, EndOffset: 20}}, 30},
+	}
+	for _, tt := range tests {
+		if got := mergedLength(tt.ranges); got != tt.want {
+			t.Errorf("mergedLength(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsFirstParty(t *testing.T) {
+	tests := []struct {
+		resource string
+		want     bool
+	}{
+		{"https://www.example.com/app.js", true},
+		{"https://example.com/app.js", true},
+		{"https://cdn.example.com/app.js", true},
+		{"https://www.google-analytics.com/analytics.js", false},
+		{"https://notexample.com/app.js", false},
+	}
+	for _, tt := range tests {
+		if got := isFirstParty(tt.resource, "https://www.example.com/page"); got != tt.want {
+			t.Errorf("isFirstParty(%q) = %v, want %v", tt.resource, got, tt.want)
+		}
+	}
+}
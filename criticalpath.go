@@ -0,0 +1,489 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxWaterfallEntries caps how many requests are listed in the waterfall
+const maxWaterfallEntries = 100
+
+// maxChainDepth is the critical request chain depth from which the chain is reported as an issue
+const maxChainDepth = 3
+
+// CriticalPathReport explains what delays the first render: render-blocking resources, the chains
+// of critical requests, and the resource hints that would shorten them
+type CriticalPathReport struct {
+	FCP                  float64                  `json:"fcp_ms"`
+	RenderBlocking       []RenderBlockingResource `json:"render_blocking"`
+	RenderBlockingEnd    float64                  `json:"render_blocking_end_ms"` // When the last render-blocking resource finished
+	ChainDepth           int                      `json:"chain_depth"`            // Requests in the longest critical chain, after the document
+	LongestChain         []WaterfallEntry         `json:"longest_chain"`
+	LongestChainDuration float64                  `json:"longest_chain_ms"`
+	LCPResource          string                   `json:"lcp_resource,omitempty"`
+	LCPPreloaded         bool                     `json:"lcp_preloaded"`
+	MissingPreconnects   []string                 `json:"missing_preconnects"` // Third-party origins on the critical path
+	UnusedPreloads       []UnusedPreload          `json:"unused_preloads"`
+	Waterfall            []WaterfallEntry         `json:"waterfall"`
+	Issues               []string                 `json:"issues"`
+}
+
+// RenderBlockingResource is a stylesheet or synchronous script the browser waits for before painting
+type RenderBlockingResource struct {
+	URL    string  `json:"url"`
+	Type   string  `json:"type"` // "stylesheet" or "script"
+	Bytes  int64   `json:"bytes"`
+	Start  float64 `json:"start_ms"`
+	End    float64 `json:"end_ms"`
+	Reason string  `json:"reason"`
+}
+
+// WaterfallEntry is one request of the page load, timed from the start of the navigation
+type WaterfallEntry struct {
+	URL          string  `json:"url"`
+	ResourceType string  `json:"resource_type"`
+	Status       int     `json:"status"`
+	Failed       bool    `json:"failed,omitempty"`
+	Priority     string  `json:"priority,omitempty"`  // Chrome's initial priority, e.g. "VeryHigh"
+	Initiator    string  `json:"initiator,omitempty"` // URL of the document, stylesheet or script that requested it
+	Start        float64 `json:"start_ms"`
+	End          float64 `json:"end_ms"`
+	Bytes        int64   `json:"bytes"`
+	Critical     bool    `json:"critical"`
+	Untimed      bool    `json:"untimed,omitempty"` // No response timing, so Start and End are unknown
+}
+
+// UnusedPreload is a <link rel="preload"> the page doesn't benefit from
+type UnusedPreload struct {
+	URL    string `json:"url"`
+	As     string `json:"as"`
+	Reason string `json:"reason"`
+}
+
+// networkRecorder captures the page's requests from Playwright events, enriched with the initiator
+// and priority Chrome reports over CDP. It must be started before navigating.
+type networkRecorder struct {
+	mu         sync.Mutex
+	requests   []playwright.Request
+	failed     map[playwright.Request]bool
+	statuses   map[playwright.Request]int
+	initiators map[string]string
+	priorities map[string]string
+}
+
+// startNetworkRecorder listens to the page's requests. Initiators are best effort: without a CDP
+// session every request is attributed to the document.
+func startNetworkRecorder(page playwright.Page) *networkRecorder {
+	recorder := &networkRecorder{
+		failed:     map[playwright.Request]bool{},
+		statuses:   map[playwright.Request]int{},
+		initiators: map[string]string{},
+		priorities: map[string]string{},
+	}
+	page.OnRequest(func(request playwright.Request) {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.requests = append(recorder.requests, request)
+	})
+	page.OnResponse(func(response playwright.Response) {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.statuses[response.Request()] = response.Status()
+	})
+	page.OnRequestFailed(func(request playwright.Request) {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.failed[request] = true
+	})
+
+	session, err := page.Context().NewCDPSession(page)
+	if err != nil {
+		return recorder
+	}
+	session.On("Network.requestWillBeSent", func(params map[string]interface{}) {
+		request, _ := params["request"].(map[string]interface{})
+		requestURL, _ := request["url"].(string)
+		if requestURL == "" {
+			return
+		}
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		if _, seen := recorder.priorities[requestURL]; seen {
+			return
+		}
+		recorder.priorities[requestURL], _ = request["initialPriority"].(string)
+		if initiator, ok := params["initiator"].(map[string]interface{}); ok {
+			recorder.initiators[requestURL] = initiatorURL(initiator)
+		}
+	})
+	if _, err := session.Send("Network.enable", nil); err != nil {
+		session.Detach()
+	}
+	return recorder
+}
+
+// initiatorURL reads the URL of the resource that caused a request from a CDP initiator
+func initiatorURL(initiator map[string]interface{}) string {
+	if u, _ := initiator["url"].(string); u != "" {
+		return u
+	}
+	// Script initiators carry the requesting script in their stack
+	for stack, _ := initiator["stack"].(map[string]interface{}); stack != nil; stack, _ = stack["parent"].(map[string]interface{}) {
+		frames, _ := stack["callFrames"].([]interface{})
+		for _, frame := range frames {
+			if f, ok := frame.(map[string]interface{}); ok {
+				if u, _ := f["url"].(string); u != "" {
+					return u
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// criticalPathScript reads render-blocking candidates, resource hints, the LCP resource, paint
+// timing and per-resource transfer sizes from the DOM and the Performance API
+const criticalPathScript = `async () => {
+	const paint = performance.getEntriesByName('first-contentful-paint')[0];
+	// LCP entries are only exposed to observers
+	const lcp = await new Promise(resolve => {
+		let last = null;
+		try {
+			const observer = new PerformanceObserver(list => { last = list.getEntries().pop() || last; });
+			observer.observe({ type: 'largest-contentful-paint', buffered: true });
+			setTimeout(() => { last = observer.takeRecords().pop() || last; observer.disconnect(); resolve(last); }, 50);
+		} catch (e) {
+			resolve(null);
+		}
+	});
+	const head = document.head || document.createElement('head');
+	const matches = (media) => !media || window.matchMedia(media).matches;
+
+	const blocking = [];
+	head.querySelectorAll('link[rel~="stylesheet" i][href]').forEach(l => {
+		if (!l.disabled && matches(l.media)) blocking.push({ url: l.href, type: 'stylesheet' });
+	});
+	head.querySelectorAll('script[src]').forEach(s => {
+		if (!s.async && !s.defer && s.type !== 'module') blocking.push({ url: s.src, type: 'script' });
+	});
+
+	const used = new Set();
+	document.querySelectorAll('img, source, script[src], link[rel~="stylesheet" i], video, audio, iframe').forEach(el => {
+		[el.currentSrc, el.src, el.href, el.poster].filter(Boolean).forEach(u => used.add(u));
+		(el.srcset || '').split(',').map(c => c.trim().split(/\s+/)[0]).filter(Boolean)
+			.forEach(u => { try { used.add(new URL(u, document.baseURI).href); } catch (e) {} });
+	});
+	document.querySelectorAll('*').forEach(el => {
+		const bg = window.getComputedStyle(el).backgroundImage;
+		if (bg && bg !== 'none') (bg.match(/url\(["']?([^"')]+)["']?\)/g) || [])
+			.forEach(m => used.add(m.replace(/^url\(["']?|["']?\)$/g, '')));
+	});
+
+	return {
+		fcp: paint ? paint.startTime : 0,
+		lcp: lcp ? {
+			url: lcp.url || '',
+			fetchPriority: lcp.element ? (lcp.element.getAttribute('fetchpriority') || '') : '',
+			lazy: lcp.element ? lcp.element.getAttribute('loading') === 'lazy' : false
+		} : null,
+		blocking: blocking,
+		preloads: Array.from(document.querySelectorAll('link[rel~="preload" i][href]')).map(l => ({ url: l.href, as: (l.getAttribute('as') || '').toLowerCase() })),
+		preconnects: Array.from(document.querySelectorAll('link[rel~="preconnect" i][href], link[rel~="dns-prefetch" i][href]')).map(l => { try { return new URL(l.href).origin; } catch (e) { return ''; } }),
+		resources: performance.getEntriesByType('resource').map(r => ({
+			url: r.name,
+			bytes: r.transferSize || r.encodedBodySize || 0,
+			blocking: r.renderBlockingStatus || ''
+		})),
+		used: Array.from(used)
+	};
+}`
+
+// criticalPathData is what criticalPathScript returns
+type criticalPathData struct {
+	FCP float64 `json:"fcp"`
+	LCP *struct {
+		URL           string `json:"url"`
+		FetchPriority string `json:"fetchPriority"`
+		Lazy          bool   `json:"lazy"`
+	} `json:"lcp"`
+	Blocking []struct {
+		URL  string `json:"url"`
+		Type string `json:"type"`
+	} `json:"blocking"`
+	Preloads []struct {
+		URL string `json:"url"`
+		As  string `json:"as"`
+	} `json:"preloads"`
+	Preconnects []string `json:"preconnects"`
+	Resources   []struct {
+		URL      string `json:"url"`
+		Bytes    int64  `json:"bytes"`
+		Blocking string `json:"blocking"` // renderBlockingStatus, where the browser reports it
+	} `json:"resources"`
+	Used []string `json:"used"`
+}
+
+// analyzeCriticalPath builds the request waterfall and reports render-blocking resources, the
+// longest critical request chain, and missing or wasted resource hints
+func analyzeCriticalPath(page playwright.Page, recorder *networkRecorder) *CriticalPathReport {
+	var data criticalPathData
+	if err := evaluateInto(page, criticalPathScript, &data); err != nil {
+		return nil
+	}
+
+	report := &CriticalPathReport{
+		FCP:                math.Round(data.FCP),
+		RenderBlocking:     []RenderBlockingResource{},
+		LongestChain:       []WaterfallEntry{},
+		MissingPreconnects: []string{},
+		UnusedPreloads:     []UnusedPreload{},
+		Waterfall:          []WaterfallEntry{},
+		Issues:             []string{},
+	}
+
+	pageURL := strings.SplitN(page.URL(), "#", 2)[0]
+	bytesByURL := map[string]int64{}
+	blockingByURL := map[string]bool{}
+	for _, resource := range data.Resources {
+		bytesByURL[resource.URL] = resource.Bytes
+		if resource.Blocking == "blocking" {
+			blockingByURL[resource.URL] = true
+		}
+	}
+
+	// Waterfall, timed from the start of the document request
+	// The listeners keep writing while the page makes late requests, so read copies
+	recorder.mu.Lock()
+	requests := append([]playwright.Request{}, recorder.requests...)
+	failed := maps.Clone(recorder.failed)
+	statuses := maps.Clone(recorder.statuses)
+	initiators := maps.Clone(recorder.initiators)
+	priorities := maps.Clone(recorder.priorities)
+	recorder.mu.Unlock()
+
+	entries := []WaterfallEntry{}
+	requestCounts := map[string]int{}
+	navigationStart := 0.0
+	for _, request := range requests {
+		timing := request.Timing()
+		if request.IsNavigationRequest() && request.Frame() == page.MainFrame() && navigationStart == 0 {
+			navigationStart = timing.StartTime
+		}
+		entry := WaterfallEntry{
+			URL:          request.URL(),
+			ResourceType: request.ResourceType(),
+			Status:       statuses[request],
+			Failed:       failed[request],
+			Priority:     priorities[request.URL()],
+			Initiator:    initiators[request.URL()],
+			Start:        timing.StartTime,
+			Bytes:        bytesByURL[request.URL()],
+		}
+		// Requests without a response (failed, aborted or in flight) have no start time to place them by
+		entry.Untimed = timing.StartTime <= 0
+		entry.End = entry.Start
+		if timing.ResponseEnd > 0 {
+			entry.End = timing.StartTime + timing.ResponseEnd
+		}
+		requestCounts[entry.URL]++
+		entries = append(entries, entry)
+	}
+	for i := range entries {
+		if entries[i].Untimed {
+			entries[i].Start, entries[i].End = 0, 0
+			continue
+		}
+		entries[i].Start = math.Round(entries[i].Start - navigationStart)
+		entries[i].End = math.Round(entries[i].End - navigationStart)
+	}
+	// Untimed requests go last and stay out of the timings and chains
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Untimed != entries[j].Untimed {
+			return !entries[i].Untimed
+		}
+		return entries[i].Start < entries[j].Start
+	})
+	entryByURL := map[string]*WaterfallEntry{}
+	for i := range entries {
+		if entries[i].Untimed {
+			continue
+		}
+		if _, ok := entryByURL[entries[i].URL]; !ok {
+			entryByURL[entries[i].URL] = &entries[i]
+		}
+	}
+
+	// Render-blocking stylesheets and synchronous head scripts
+	listedBlocking := map[string]bool{}
+	for _, candidate := range data.Blocking {
+		if listedBlocking[candidate.URL] {
+			continue
+		}
+		listedBlocking[candidate.URL] = true
+		resource := RenderBlockingResource{URL: candidate.URL, Type: candidate.Type, Bytes: bytesByURL[candidate.URL]}
+		if candidate.Type == "stylesheet" {
+			resource.Reason = "Stylesheet in <head> without a non-matching media query"
+		} else {
+			resource.Reason = "Script in <head> without async or defer"
+		}
+		if entry, ok := entryByURL[candidate.URL]; ok {
+			resource.Start, resource.End = entry.Start, entry.End
+		}
+		report.RenderBlocking = append(report.RenderBlocking, resource)
+		blockingByURL[candidate.URL] = true
+	}
+	// The browser also flags resources it blocked rendering on, e.g. stylesheets inserted in <body>
+	for _, resource := range data.Resources {
+		if resource.Blocking != "blocking" || listedBlocking[resource.URL] {
+			continue
+		}
+		blocking := RenderBlockingResource{URL: resource.URL, Type: "stylesheet", Bytes: resource.Bytes, Reason: "Reported as render-blocking by the browser"}
+		if entry, ok := entryByURL[resource.URL]; ok {
+			blocking.Type = entry.ResourceType
+			blocking.Start, blocking.End = entry.Start, entry.End
+		}
+		report.RenderBlocking = append(report.RenderBlocking, blocking)
+	}
+	for _, resource := range report.RenderBlocking {
+		report.RenderBlockingEnd = math.Max(report.RenderBlockingEnd, resource.End)
+	}
+	if len(report.RenderBlocking) > 0 {
+		total := int64(0)
+		for _, resource := range report.RenderBlocking {
+			total += resource.Bytes
+		}
+		report.Issues = append(report.Issues, fmt.Sprintf("%d render-blocking resource(s) (%s) delay the first paint until %.0fms", len(report.RenderBlocking), formatBytes(total), report.RenderBlockingEnd))
+	}
+
+	// Critical requests: the document, render-blocking resources, fonts and high-priority
+	// requests, linked to what requested them
+	for i := range entries {
+		entry := &entries[i]
+		switch {
+		case entry.ResourceType == "document" && entry.URL == pageURL, blockingByURL[entry.URL], entry.ResourceType == "font":
+			entry.Critical = true
+		case entry.ResourceType == "stylesheet" || entry.ResourceType == "script":
+			entry.Critical = entry.Priority == "VeryHigh" || entry.Priority == "High"
+		}
+		if entry.Critical && entry.Initiator == "" && entry.URL != pageURL {
+			entry.Initiator = pageURL
+		}
+	}
+	for i := range entries {
+		if entries[i].Untimed {
+			continue
+		}
+		chain := criticalChain(&entries[i], entryByURL)
+		if len(chain) == 0 {
+			continue
+		}
+		duration := chain[len(chain)-1].End - chain[0].Start
+		if len(chain)-1 > report.ChainDepth || (len(chain)-1 == report.ChainDepth && duration > report.LongestChainDuration) {
+			report.ChainDepth = len(chain) - 1
+			report.LongestChain = chain
+			report.LongestChainDuration = duration
+		}
+	}
+	if report.ChainDepth >= maxChainDepth {
+		report.Issues = append(report.Issues, fmt.Sprintf("Critical request chain is %d requests deep and takes %.0fms; flatten it or preload the last requests", report.ChainDepth, report.LongestChainDuration))
+	}
+
+	// Resource hints for the LCP resource and third-party origins on the critical path
+	preloaded := map[string]string{}
+	for _, preload := range data.Preloads {
+		preloaded[preload.URL] = preload.As
+	}
+	if data.LCP != nil && data.LCP.URL != "" && !strings.HasPrefix(data.LCP.URL, "data:") {
+		report.LCPResource = data.LCP.URL
+		_, report.LCPPreloaded = preloaded[data.LCP.URL]
+		if data.LCP.Lazy {
+			report.Issues = append(report.Issues, "The LCP image is lazy-loaded, which delays it")
+		}
+		if !report.LCPPreloaded && !strings.EqualFold(data.LCP.FetchPriority, "high") {
+			report.Issues = append(report.Issues, fmt.Sprintf(`Preload the LCP resource %s or give it fetchpriority="high"`, data.LCP.URL))
+		}
+	}
+
+	connected := map[string]bool{originOf(pageURL): true}
+	for _, origin := range data.Preconnects {
+		connected[origin] = true
+	}
+	missing := map[string]bool{}
+	for _, entry := range entries {
+		origin := originOf(entry.URL)
+		if origin == "" || connected[origin] || missing[origin] {
+			continue
+		}
+		if entry.Critical || entry.URL == report.LCPResource {
+			missing[origin] = true
+			report.MissingPreconnects = append(report.MissingPreconnects, origin)
+		}
+	}
+	if len(report.MissingPreconnects) > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("Add <link rel=\"preconnect\"> for critical origins: %s", strings.Join(report.MissingPreconnects, ", ")))
+	}
+
+	// Preloads that are never fetched, fetched twice, or not used by the page
+	used := map[string]bool{}
+	for _, u := range data.Used {
+		used[u] = true
+	}
+	for _, preload := range data.Preloads {
+		unused := UnusedPreload{URL: preload.URL, As: preload.As}
+		switch {
+		case requestCounts[preload.URL] == 0:
+			unused.Reason = "Never fetched; check the as attribute"
+		case requestCounts[preload.URL] > 1:
+			unused.Reason = "Fetched twice, so the preload was wasted; check the as and crossorigin attributes"
+		case (preload.As == "image" || preload.As == "script" || preload.As == "style") && !used[preload.URL]:
+			unused.Reason = "Not used by the page"
+		default:
+			continue
+		}
+		report.UnusedPreloads = append(report.UnusedPreloads, unused)
+	}
+	if len(report.UnusedPreloads) > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d preload(s) are unused and compete with critical requests", len(report.UnusedPreloads)))
+	}
+
+	if len(entries) > maxWaterfallEntries {
+		entries = entries[:maxWaterfallEntries]
+	}
+	report.Waterfall = entries
+	return report
+}
+
+// criticalChain walks from a critical request up its initiators to the document, returning the
+// chain in request order. It returns nil for requests off the critical path.
+func criticalChain(entry *WaterfallEntry, entryByURL map[string]*WaterfallEntry) []WaterfallEntry {
+	chain := []WaterfallEntry{}
+	seen := map[string]bool{}
+	for current := entry; current != nil && current.Critical && !seen[current.URL]; current = entryByURL[current.Initiator] {
+		seen[current.URL] = true
+		chain = append([]WaterfallEntry{*current}, chain...)
+		if current.Initiator == "" {
+			return chain
+		}
+	}
+	if len(chain) == 0 || chain[0].Initiator != "" {
+		// The chain doesn't lead back to the document
+		return nil
+	}
+	return chain
+}
+
+// originOf returns the scheme and host of a URL, or "" for non-HTTP URLs
+func originOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCriticalChain(t *testing.T) {
+	document := WaterfallEntry{URL: "https://example.com/", Critical: true, Start: 0, End: 100}
+	css := WaterfallEntry{URL: "https://example.com/style.css", Initiator: "https://example.com/", Critical: true, Start: 110, End: 200}
+	font := WaterfallEntry{URL: "https://example.com/font.woff2", Initiator: "https://example.com/style.css", Critical: true, Start: 210, End: 300}
+	image := WaterfallEntry{URL: "https://example.com/photo.jpg", Initiator: "https://example.com/", Start: 120, End: 400}
+	orphan := WaterfallEntry{URL: "https://example.com/late.js", Initiator: "https://example.com/missing.js", Critical: true}
+
+	entryByURL := map[string]*WaterfallEntry{}
+	for _, entry := range []*WaterfallEntry{&document, &css, &font, &image, &orphan} {
+		entryByURL[entry.URL] = entry
+	}
+
+	tests := []struct {
+		name  string
+		entry *WaterfallEntry
+		want  []string
+	}{
+		{"document", &document, []string{document.URL}},
+		{"font through stylesheet", &font, []string{document.URL, css.URL, font.URL}},
+		{"not critical", &image, nil},
+		{"doesn't reach the document", &orphan, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range criticalChain(tt.entry, entryByURL) {
+				got = append(got, entry.URL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("criticalChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInitiatorURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		initiator map[string]interface{}
+		want      string
+	}{
+		{"parser", map[string]interface{}{"type": "parser", "url": "https://example.com/"}, "https://example.com/"},
+		{
+			name: "script stack",
+			initiator: map[string]interface{}{"type": "script", "stack": map[string]interface{}{
+				"callFrames": []interface{}{map[string]interface{}{"url": "https://example.com/app.js"}},
+			}},
+			want: "https://example.com/app.js",
+		},
+		{
+			name: "async parent stack",
+			initiator: map[string]interface{}{"type": "script", "stack": map[string]interface{}{
+				"callFrames": []interface{}{map[string]interface{}{"url": ""}},
+				"parent": map[string]interface{}{
+					"callFrames": []interface{}{map[string]interface{}{"url": "https://example.com/loader.js"}},
+				},
+			}},
+			want: "https://example.com/loader.js",
+		},
+		{"other", map[string]interface{}{"type": "other"}, ""},
+	}
+	for _, tt := range tests {
+		if got := initiatorURL(tt.initiator); got != tt.want {
+			t.Errorf("%s: initiatorURL() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOriginOf(t *testing.T) {
+	tests := map[string]string{
+		"https://cdn.example.com/app.js?v=1": "https://cdn.example.com",
+		"http://example.com:8080/":           "http://example.com:8080",
+		"data:image/png;base64,AAAA":         "",
+		"blob:https://example.com/1234":      "",
+	}
+	for rawURL, want := range tests {
+		if got := originOf(rawURL); got != want {
+			t.Errorf("originOf(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ go 1.22.1
 require (
 	github.com/gofiber/fiber/v2 v2.52.10
 	github.com/playwright-community/playwright-go v0.5200.1
+	golang.org/x/image v0.18.0
 )
 
 require (
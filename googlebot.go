@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// googlebotSmartphoneUserAgent is the user agent string of Googlebot Smartphone
+const googlebotSmartphoneUserAgent = "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.204 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+// googlebotStorageScript clears web storage before page scripts run, Googlebot renders every page stateless
+const googlebotStorageScript = `try { window.localStorage.clear(); window.sessionStorage.clear(); } catch (e) {}`
+
+// minContentSimilarity is the main content similarity below which two renders are reported as different
+const minContentSimilarity = 0.8
+
+// GooglebotComparison compares the page rendered for a regular user with the page rendered as Googlebot
+type GooglebotComparison struct {
+	UserAgent          string             `json:"user_agent"`
+	StatusCode         int                `json:"status_code"`
+	ContentSimilarity  float64            `json:"content_similarity"` // 0-1 similarity of the main content
+	UserWordCount      int                `json:"user_word_count"`
+	GooglebotWordCount int                `json:"googlebot_word_count"`
+	Differences        []RenderDifference `json:"differences"`
+	PossibleCloaking   bool               `json:"possible_cloaking"`
+	Issues             []string           `json:"issues"`
+}
+
+// RenderDifference describes an element whose value differs between two renders
+type RenderDifference struct {
+	Element   string `json:"element"`
+	User      string `json:"user"`
+	Googlebot string `json:"googlebot"`
+}
+
+// applyGooglebotPreset configures a browser context to render like Googlebot Smartphone:
+// Googlebot user agent and viewport, service workers blocked and no permissions granted.
+// Contexts are always created fresh, so no storage survives between audits.
+func applyGooglebotPreset(contextOptions *playwright.BrowserNewContextOptions) {
+	contextOptions.UserAgent = playwright.String(googlebotSmartphoneUserAgent)
+	contextOptions.Viewport = &playwright.Size{Width: 412, Height: 732}
+	contextOptions.DeviceScaleFactor = playwright.Float(2.625)
+	contextOptions.IsMobile = playwright.Bool(true)
+	contextOptions.HasTouch = playwright.Bool(true)
+	contextOptions.ServiceWorkers = playwright.ServiceWorkerPolicyBlock
+	contextOptions.Permissions = []string{}
+}
+
+// compareWithGooglebot renders the page a second time and reports cloaking-like differences.
+// The second render uses Googlebot, or a regular browser when the audit itself ran as Googlebot.
+func (a *SEOAuditor) compareWithGooglebot(targetURL string, opts AuditOptions, audited pageSnapshot) (*GooglebotComparison, error) {
+	comparison := &GooglebotComparison{
+		UserAgent:   googlebotSmartphoneUserAgent,
+		Differences: []RenderDifference{},
+		Issues:      []string{},
+	}
+
+	otherOpts := opts
+	otherOpts.RenderAsGooglebot = !opts.RenderAsGooglebot
+	if otherOpts.RenderAsGooglebot {
+		otherOpts.UserAgent = ""
+	}
+
+	context, err := a.newBrowserContext(targetURL, otherOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer context.Close()
+
+	page, err := context.NewPage()
+	if err != nil {
+		return nil, fmt.Errorf("could not create page: %v", err)
+	}
+	defer page.Close()
+
+	response, err := page.Goto(targetURL, playwright.PageGotoOptions{
+		WaitUntil: playwright.WaitUntilStateNetworkidle,
+		Timeout:   playwright.Float(30000),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not navigate to page for comparison: %v", err)
+	}
+
+	// Dismiss the consent banner the same way as the audited render so its text isn't a difference
+	a.handleConsent(page, targetURL, opts)
+
+	other, err := takeSnapshot(page)
+	if err != nil {
+		return nil, fmt.Errorf("could not read page rendered for comparison: %v", err)
+	}
+	if response != nil {
+		other.StatusCode = response.Status()
+		other.RobotsHeader = response.Headers()["x-robots-tag"]
+	}
+
+	user, bot := audited, other
+	if opts.RenderAsGooglebot {
+		user, bot = other, audited
+	}
+	comparison.StatusCode = bot.StatusCode
+
+	compare := func(element, userValue, botValue string) {
+		if normalizeText(userValue) != normalizeText(botValue) {
+			comparison.Differences = append(comparison.Differences, RenderDifference{
+				Element:   element,
+				User:      userValue,
+				Googlebot: botValue,
+			})
+		}
+	}
+
+	compare("HTTP status", fmt.Sprint(user.StatusCode), fmt.Sprint(bot.StatusCode))
+	compare("title", user.Title, bot.Title)
+	compare("meta description", user.MetaDescription, bot.MetaDescription)
+	compare("canonical", user.Canonical, bot.Canonical)
+	compare("robots meta", user.RobotsMeta, bot.RobotsMeta)
+	compare("X-Robots-Tag header", user.RobotsHeader, bot.RobotsHeader)
+	compare("h1", strings.Join(user.H1, " | "), strings.Join(bot.H1, " | "))
+
+	comparison.UserWordCount = len(strings.Fields(user.MainText))
+	comparison.GooglebotWordCount = len(strings.Fields(bot.MainText))
+	comparison.ContentSimilarity = math.Round(contentSimilarity(user.MainText, bot.MainText)*100) / 100
+	if comparison.ContentSimilarity < minContentSimilarity {
+		comparison.Differences = append(comparison.Differences, RenderDifference{
+			Element:   "main content",
+			User:      fmt.Sprintf("%d words", comparison.UserWordCount),
+			Googlebot: fmt.Sprintf("%d words (%.0f%% similar)", comparison.GooglebotWordCount, comparison.ContentSimilarity*100),
+		})
+	}
+
+	comparison.PossibleCloaking = len(comparison.Differences) > 0
+	for _, diff := range comparison.Differences {
+		comparison.Issues = append(comparison.Issues, fmt.Sprintf("Googlebot sees a different %s than regular users", diff.Element))
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxHeadingLength is the longest text a real heading is expected to have
+const maxHeadingLength = 150
+
+// Heading is one entry of the page's heading outline, in document order
+type Heading struct {
+	Level    int      `json:"level"`
+	Text     string   `json:"text"`
+	Selector string   `json:"selector"`
+	Hidden   bool     `json:"hidden"`
+	Issues   []string `json:"issues,omitempty"`
+}
+
+// headingOutlineScript lists h1-h6 and role="heading" elements in document order
+const headingOutlineScript = `() => {
+	const selectorOf = ` + cssSelectorFunction + `;
+	const isHidden = (el) => {
+		if (el.closest('[hidden], [aria-hidden="true"]')) return true;
+		const style = window.getComputedStyle(el);
+		return style.display === 'none' || style.visibility === 'hidden' || el.getClientRects().length === 0;
+	};
+	const textOf = (el) => {
+		let text = (el.innerText || el.textContent || '').trim();
+		if (!text) {
+			// An image inside a heading names it through its alt text
+			text = Array.from(el.querySelectorAll('img[alt]')).map(img => img.getAttribute('alt').trim()).join(' ').trim();
+		}
+		return (el.getAttribute('aria-label') || text).trim().replace(/\s+/g, ' ');
+	};
+	return Array.from(document.querySelectorAll('h1, h2, h3, h4, h5, h6, [role="heading"]')).map(el => {
+		let level = /^H[1-6]$/.test(el.tagName) ? parseInt(el.tagName[1], 10) : 2;
+		const ariaLevel = parseInt(el.getAttribute('aria-level') || '', 10);
+		if (el.getAttribute('role') === 'heading' && ariaLevel >= 1) level = ariaLevel;
+		return {
+			level: level,
+			text: textOf(el),
+			selector: selectorOf(el),
+			hidden: isHidden(el),
+			presentational: ['presentation', 'none'].includes(el.getAttribute('role')),
+			interactive: !!el.closest('button, label, summary')
+		};
+	});
+}`
+
+// headingResult is one entry returned by headingOutlineScript
+type headingResult struct {
+	Heading
+	Presentational bool `json:"presentational"`
+	Interactive    bool `json:"interactive"`
+}
+
+// auditHeadingOutline extracts the heading outline and flags skipped levels, empty, hidden,
+// duplicated and styling-only headings. The hierarchy is proper when the page has an H1 and
+// the visible outline never skips a level.
+func auditHeadingOutline(page playwright.Page) ([]Heading, bool, []string) {
+	var results []headingResult
+	if err := evaluateInto(page, headingOutlineScript, &results); err != nil {
+		return []Heading{}, false, []string{fmt.Sprintf("Unable to read the heading outline: %v", err)}
+	}
+	return analyzeHeadingOutline(results)
+}
+
+// analyzeHeadingOutline validates the extracted headings in document order
+func analyzeHeadingOutline(results []headingResult) ([]Heading, bool, []string) {
+	outline := []Heading{}
+	issues := []string{}
+
+	hasH1 := false
+	skipped := false
+	previous := 0
+	seen := map[string]string{}
+	for _, result := range results {
+		heading := result.Heading
+		heading.Issues = []string{}
+		label := fmt.Sprintf("H%d", heading.Level)
+
+		switch {
+		case heading.Text == "":
+			heading.Issues = append(heading.Issues, "empty")
+			issues = append(issues, fmt.Sprintf("Empty %s heading (%s)", label, heading.Selector))
+		case heading.Hidden:
+			heading.Issues = append(heading.Issues, "hidden")
+			issues = append(issues, fmt.Sprintf("Hidden %s heading %q", label, heading.Text))
+		}
+
+		if reason := stylingOnlyReason(result); reason != "" {
+			heading.Issues = append(heading.Issues, "styling only: "+reason)
+			issues = append(issues, fmt.Sprintf("%s %q looks like styling rather than a heading (%s)", label, truncateRunes(heading.Text, 60), reason))
+		}
+
+		if heading.Text != "" && !heading.Hidden {
+			key := strings.ToLower(heading.Text)
+			if first, ok := seen[key]; ok {
+				heading.Issues = append(heading.Issues, "duplicate of "+first)
+				issues = append(issues, fmt.Sprintf("Duplicate %s heading %q", label, heading.Text))
+			} else {
+				seen[key] = label
+			}
+		}
+
+		// Hidden and empty headings don't take part in the visible outline
+		if heading.Text != "" && !heading.Hidden && !result.Presentational {
+			if heading.Level == 1 {
+				hasH1 = true
+			}
+			switch {
+			case previous == 0 && heading.Level > 2:
+				// An outline may open with an H2 in a site header, but not deeper
+				skipped = true
+				heading.Issues = append(heading.Issues, "opens the outline")
+				issues = append(issues, fmt.Sprintf("%s %q is the first heading of the page", label, heading.Text))
+			case previous > 0 && heading.Level > previous+1:
+				skipped = true
+				heading.Issues = append(heading.Issues, fmt.Sprintf("skips from H%d", previous))
+				issues = append(issues, fmt.Sprintf("%s %q skips a level after H%d", label, heading.Text, previous))
+			}
+			previous = heading.Level
+		}
+
+		outline = append(outline, heading)
+	}
+
+	return outline, hasH1 && !skipped, issues
+}
+
+// stylingOnlyReason explains why a heading seems to be used for its looks rather than structure
+func stylingOnlyReason(result headingResult) string {
+	switch {
+	case result.Text == "":
+		return ""
+	case result.Presentational:
+		return "role removes its heading semantics"
+	case result.Interactive:
+		return "inside a button or label"
+	case len([]rune(result.Text)) > maxHeadingLength:
+		return fmt.Sprintf("%d characters of text", len([]rune(result.Text)))
+	case strings.IndexFunc(result.Text, unicode.IsLetter) < 0:
+		return "no words"
+	}
+	return ""
+}
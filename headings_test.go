@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func heading(level int, text string) headingResult {
+	return headingResult{Heading: Heading{Level: level, Text: text, Selector: "h" + string(rune('0'+level))}}
+}
+
+func TestAnalyzeHeadingOutline(t *testing.T) {
+	hidden := heading(2, "Menu")
+	hidden.Hidden = true
+	presentational := heading(1, "Logo")
+	presentational.Presentational = true
+
+	tests := []struct {
+		name       string
+		results    []headingResult
+		wantProper bool
+		wantIssues []string // Heading issues in document order
+	}{
+		{
+			name:       "proper outline",
+			results:    []headingResult{heading(1, "Shoes"), heading(2, "Running"), heading(3, "Trail"), heading(2, "Walking")},
+			wantProper: true,
+			wantIssues: []string{"", "", "", ""},
+		},
+		{
+			name:       "skipped level",
+			results:    []headingResult{heading(1, "Shoes"), heading(3, "Trail")},
+			wantIssues: []string{"", "skips from H1"},
+		},
+		{
+			name:       "opens with an H3",
+			results:    []headingResult{heading(3, "Newsletter"), heading(1, "Shoes")},
+			wantIssues: []string{"opens the outline", ""},
+		},
+		{
+			name:       "no H1",
+			results:    []headingResult{heading(2, "Shoes")},
+			wantIssues: []string{""},
+		},
+		{
+			name:       "empty, hidden and duplicate headings",
+			results:    []headingResult{heading(1, "Shoes"), heading(2, ""), hidden, heading(2, "shoes")},
+			wantProper: true,
+			wantIssues: []string{"", "empty", "hidden", "duplicate of H1"},
+		},
+		{
+			name:       "presentational headings leave the outline",
+			results:    []headingResult{presentational, heading(2, "Shoes")},
+			wantIssues: []string{"styling only: role removes its heading semantics", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outline, proper, _ := analyzeHeadingOutline(tt.results)
+			if proper != tt.wantProper {
+				t.Errorf("proper = %v, want %v", proper, tt.wantProper)
+			}
+			got := []string{}
+			for _, h := range outline {
+				got = append(got, strings.Join(h.Issues, "; "))
+			}
+			if !reflect.DeepEqual(got, tt.wantIssues) {
+				t.Errorf("issues = %q, want %q", got, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestStylingOnlyReason(t *testing.T) {
+	interactive := heading(3, "Open menu")
+	interactive.Interactive = true
+
+	tests := []struct {
+		name   string
+		result headingResult
+		want   string
+	}{
+		{"regular heading", heading(2, "Running shoes"), ""},
+		{"empty", heading(2, ""), ""},
+		{"inside a button", interactive, "inside a button or label"},
+		{"paragraph styled as a heading", heading(2, strings.Repeat("word ", 40)), "200 characters of text"},
+		{"decoration", heading(2, "* * *"), "no words"},
+	}
+	for _, tt := range tests {
+		if got := stylingOnlyReason(tt.result); got != tt.want {
+			t.Errorf("%s: stylingOnlyReason() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,514 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxHreflangAlternates caps how many alternates are fetched to check their return links
+const maxHreflangAlternates = 20
+
+// hreflangCheckBudget bounds the total time spent fetching alternates to check their return links
+const hreflangCheckBudget = 60 * time.Second
+
+// maxChildSitemaps caps how many sitemaps of a sitemap index are read for hreflang annotations
+const maxChildSitemaps = 5
+
+// HreflangLink is one hreflang annotation as declared by a page
+type HreflangLink struct {
+	Hreflang string `json:"hreflang"`
+	URL      string `json:"href"`
+}
+
+// hreflangLinksExpression lists the page's <link rel="alternate" hreflang> elements
+const hreflangLinksExpression = `Array.from(document.querySelectorAll('link[rel~="alternate" i][hreflang]'))
+			.map(l => ({ hreflang: (l.getAttribute('hreflang') || '').trim(), href: l.href }))`
+
+// hreflangScript reads the hreflang annotations and canonical URL of the current page
+const hreflangScript = `() => {
+	const canonical = document.querySelector('link[rel="canonical"]');
+	return {
+		links: ` + hreflangLinksExpression + `,
+		canonical: canonical ? canonical.href : ''
+	};
+}`
+
+// hreflangPage is what hreflangScript returns
+type hreflangPage struct {
+	Links     []HreflangLink `json:"links"`
+	Canonical string         `json:"canonical"`
+}
+
+// HreflangReport validates the page's hreflang cluster and the return links of its alternates
+type HreflangReport struct {
+	Annotations      []HreflangAnnotation `json:"annotations"`
+	Codes            []string             `json:"codes"` // Language codes of the cluster, in the order of the matrix
+	HasSelfReference bool                 `json:"has_self_reference"`
+	HasXDefault      bool                 `json:"has_x_default"`
+	Alternates       []HreflangAlternate  `json:"alternates"`
+	Issues           []string             `json:"issues"`
+}
+
+// HreflangAnnotation is one hreflang annotation of the audited page and where it was found
+type HreflangAnnotation struct {
+	Hreflang string `json:"hreflang"`
+	URL      string `json:"url"`
+	Source   string `json:"source"` // "html", "header" or "sitemap"
+	Problem  string `json:"problem,omitempty"`
+}
+
+// HreflangAlternate is another page of the cluster and the annotations it declares in return
+type HreflangAlternate struct {
+	Hreflang    []string       `json:"hreflang"` // Codes the audited page uses for this URL
+	URL         string         `json:"url"`
+	Checked     bool           `json:"checked"`
+	StatusCode  int            `json:"status_code"`
+	FinalURL    string         `json:"final_url,omitempty"` // Set when the alternate redirects
+	Canonical   string         `json:"canonical,omitempty"`
+	Annotations []HreflangLink `json:"annotations"`
+	LinksBack   bool           `json:"links_back"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// auditHreflang collects hreflang annotations from the DOM, the Link header and the sitemap,
+// validates their codes and, when the network is available, fetches every alternate to check
+// that it links back. It returns nil when the page declares no hreflang at all.
+func (a *SEOAuditor) auditHreflang(page playwright.Page, target auditTarget, userAgent string) *HreflangReport {
+	var current hreflangPage
+	if err := evaluateInto(page, hreflangScript, &current); err != nil {
+		return nil
+	}
+
+	sitemaps := hreflangSitemapCache{auditor: a, opts: target.opts, targetURL: target.url}
+	annotations := []HreflangAnnotation{}
+	for _, link := range current.Links {
+		annotations = append(annotations, HreflangAnnotation{Hreflang: link.Hreflang, URL: link.URL, Source: "html"})
+	}
+	for _, link := range parseLinkHeaderHreflang(target.headers["link"], target.url) {
+		annotations = append(annotations, HreflangAnnotation{Hreflang: link.Hreflang, URL: link.URL, Source: "header"})
+	}
+	if target.networkChecks {
+		for _, link := range sitemaps.lookup(target.url) {
+			annotations = append(annotations, HreflangAnnotation{Hreflang: link.Hreflang, URL: link.URL, Source: "sitemap"})
+		}
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	report := &HreflangReport{
+		Annotations: annotations,
+		Codes:       []string{},
+		Alternates:  []HreflangAlternate{},
+		Issues:      []string{},
+	}
+
+	// Validate the codes and build the cluster: one URL per code
+	cluster := map[string]string{}
+	sources := map[string]map[string]bool{}
+	for i := range report.Annotations {
+		annotation := &report.Annotations[i]
+		if annotation.Problem = hreflangCodeProblem(annotation.Hreflang); annotation.Problem != "" {
+			report.Issues = append(report.Issues, fmt.Sprintf("Invalid hreflang %q (%s): %s", annotation.Hreflang, annotation.Source, annotation.Problem))
+		}
+		key := strings.ToLower(annotation.Hreflang)
+		if key == "" {
+			continue
+		}
+		if sources[annotation.Source] == nil {
+			sources[annotation.Source] = map[string]bool{}
+		}
+		sources[annotation.Source][key] = true
+		if existing, ok := cluster[key]; !ok {
+			cluster[key] = annotation.URL
+			report.Codes = append(report.Codes, annotation.Hreflang)
+		} else if !sameURL(existing, annotation.URL) {
+			report.Issues = append(report.Issues, fmt.Sprintf("hreflang %q points to both %s and %s", annotation.Hreflang, existing, annotation.URL))
+		}
+		if key == "x-default" {
+			report.HasXDefault = true
+		}
+	}
+
+	// Declaring the cluster in several places is allowed, but they must agree
+	if len(sources) > 1 {
+		for _, code := range report.Codes {
+			key := strings.ToLower(code)
+			for _, source := range []string{"html", "header", "sitemap"} {
+				if sources[source] != nil && !sources[source][key] {
+					report.Issues = append(report.Issues, fmt.Sprintf("hreflang %q is missing from the %s annotations", code, source))
+				}
+			}
+		}
+	}
+
+	selfURL := target.url
+	for _, code := range report.Codes {
+		if link := cluster[strings.ToLower(code)]; sameURL(link, target.url) || (current.Canonical != "" && sameURL(link, current.Canonical)) {
+			report.HasSelfReference = true
+			selfURL = link
+			break
+		}
+	}
+	if !report.HasSelfReference {
+		report.Issues = append(report.Issues, "hreflang annotations don't include the page itself")
+	}
+	if !report.HasXDefault {
+		report.Issues = append(report.Issues, "No x-default hreflang for users matching none of the languages")
+	}
+	if current.Canonical != "" && !sameURL(current.Canonical, target.url) {
+		report.Issues = append(report.Issues, fmt.Sprintf("Page is canonicalized to %s, so search engines ignore its hreflang annotations", current.Canonical))
+	}
+
+	// Every other URL of the cluster is an alternate that must link back
+	byURL := map[string]int{}
+	for _, code := range report.Codes {
+		link := cluster[strings.ToLower(code)]
+		if sameURL(link, selfURL) {
+			continue
+		}
+		if i, ok := byURL[link]; ok {
+			report.Alternates[i].Hreflang = append(report.Alternates[i].Hreflang, code)
+			continue
+		}
+		byURL[link] = len(report.Alternates)
+		report.Alternates = append(report.Alternates, HreflangAlternate{
+			Hreflang:    []string{code},
+			URL:         link,
+			Annotations: []HreflangLink{},
+		})
+	}
+
+	if !target.networkChecks {
+		return report
+	}
+	if len(report.Alternates) > maxHreflangAlternates {
+		report.Issues = append(report.Issues, fmt.Sprintf("Only the first %d of %d hreflang alternates were checked for return links", maxHreflangAlternates, len(report.Alternates)))
+	}
+	deadline := time.Now().Add(hreflangCheckBudget)
+	for i := range report.Alternates {
+		if i >= maxHreflangAlternates {
+			break
+		}
+		if time.Now().After(deadline) {
+			report.Issues = append(report.Issues, fmt.Sprintf("Stopped checking hreflang return links after %s; %d alternate(s) were not checked", hreflangCheckBudget, min(len(report.Alternates), maxHreflangAlternates)-i))
+			break
+		}
+		alternate := &report.Alternates[i]
+		a.checkHreflangAlternate(alternate, target, userAgent, &sitemaps)
+		codes := strings.Join(alternate.Hreflang, ", ")
+		switch {
+		case alternate.Error != "":
+			report.Issues = append(report.Issues, fmt.Sprintf("hreflang alternate %s (%s) could not be checked: %s", alternate.URL, codes, alternate.Error))
+			continue
+		case alternate.StatusCode != http.StatusOK:
+			report.Issues = append(report.Issues, fmt.Sprintf("hreflang alternate %s (%s) returns HTTP %d", alternate.URL, codes, alternate.StatusCode))
+		case alternate.FinalURL != "":
+			report.Issues = append(report.Issues, fmt.Sprintf("hreflang alternate %s (%s) redirects to %s", alternate.URL, codes, alternate.FinalURL))
+		}
+		if alternate.Canonical != "" && !sameURL(alternate.Canonical, alternate.URL) {
+			report.Issues = append(report.Issues, fmt.Sprintf("hreflang alternate %s (%s) is canonicalized to %s", alternate.URL, codes, alternate.Canonical))
+		}
+		for _, link := range alternate.Annotations {
+			if sameURL(link.URL, selfURL) || sameURL(link.URL, target.url) {
+				alternate.LinksBack = true
+				break
+			}
+		}
+		if !alternate.LinksBack {
+			report.Issues = append(report.Issues, fmt.Sprintf("hreflang alternate %s (%s) doesn't link back to this page", alternate.URL, codes))
+		}
+	}
+
+	return report
+}
+
+// checkHreflangAlternate fetches an alternate's server HTML and collects the hreflang annotations
+// it declares in its markup, Link header and sitemap
+func (a *SEOAuditor) checkHreflangAlternate(alternate *HreflangAlternate, target auditTarget, userAgent string, sitemaps *hreflangSitemapCache) {
+	alternate.Checked = true
+	raw, err := a.fetchRawHTML(alternate.URL, target.url, target.opts, userAgent)
+	if err != nil {
+		alternate.Error = err.Error()
+		return
+	}
+	alternate.StatusCode = raw.StatusCode
+	if !sameURL(raw.URL, alternate.URL) {
+		alternate.FinalURL = raw.URL
+	}
+
+	snapshot, err := a.snapshotRawHTML(raw)
+	if err != nil {
+		alternate.Error = err.Error()
+		return
+	}
+	alternate.Canonical = snapshot.Canonical
+	alternate.Annotations = append(alternate.Annotations, snapshot.Hreflang...)
+	alternate.Annotations = append(alternate.Annotations, parseLinkHeaderHreflang(strings.Join(raw.Header.Values("Link"), ", "), raw.URL)...)
+	alternate.Annotations = append(alternate.Annotations, sitemaps.lookup(alternate.URL)...)
+}
+
+// annotationFor returns the URL an alternate declares for a code, and whether it declares it at all
+func (alternate HreflangAlternate) annotationFor(code string) (string, bool) {
+	for _, link := range alternate.Annotations {
+		if strings.EqualFold(link.Hreflang, code) {
+			return link.URL, true
+		}
+	}
+	return "", false
+}
+
+var (
+	linkHeaderEntry = regexp.MustCompile(`<([^>]*)>([^<]*)`)
+	linkHeaderParam = regexp.MustCompile(`(?i)([a-z-]+)\s*=\s*(?:"([^"]*)"|([^\s;,]+))`)
+)
+
+// parseLinkHeaderHreflang extracts rel="alternate" hreflang entries from an HTTP Link header,
+// resolving relative URLs against baseURL
+func parseLinkHeaderHreflang(header, baseURL string) []HreflangLink {
+	links := []HreflangLink{}
+	if header == "" {
+		return links
+	}
+	base, _ := url.Parse(baseURL)
+	for _, entry := range linkHeaderEntry.FindAllStringSubmatch(header, -1) {
+		params := map[string]string{}
+		for _, param := range linkHeaderParam.FindAllStringSubmatch(entry[2], -1) {
+			params[strings.ToLower(param[1])] = param[2] + param[3]
+		}
+		if params["hreflang"] == "" || !containsString(strings.Fields(strings.ToLower(params["rel"])), "alternate") {
+			continue
+		}
+		href := strings.TrimSpace(entry[1])
+		if base != nil {
+			if resolved, err := base.Parse(href); err == nil {
+				href = resolved.String()
+			}
+		}
+		links = append(links, HreflangLink{Hreflang: params["hreflang"], URL: href})
+	}
+	return links
+}
+
+// hreflangCodeProblem explains why an hreflang value is invalid, or returns "" when it is valid.
+// Values are an ISO 639-1 language, optionally followed by a script and an ISO 3166-1 region.
+func hreflangCodeProblem(code string) string {
+	if code == "" {
+		return "empty value"
+	}
+	if strings.EqualFold(code, "x-default") {
+		return ""
+	}
+	if strings.Contains(code, "_") {
+		return "use a hyphen instead of an underscore"
+	}
+
+	parts := strings.Split(strings.ToLower(code), "-")
+	if !isoLanguages[parts[0]] {
+		if isoRegions[parts[0]] && len(parts) == 1 {
+			return fmt.Sprintf("%q is a region, a language is required first", parts[0])
+		}
+		return fmt.Sprintf("%q is not an ISO 639-1 language code", parts[0])
+	}
+	rest := parts[1:]
+	if len(rest) > 0 && len(rest[0]) == 4 {
+		// Script subtag, e.g. zh-Hant
+		rest = rest[1:]
+	}
+	switch {
+	case len(rest) == 0:
+		return ""
+	case len(rest) > 1:
+		return "too many subtags"
+	case rest[0] == "uk":
+		return "the region code for the United Kingdom is GB"
+	case !isoRegions[rest[0]]:
+		return fmt.Sprintf("%q is not an ISO 3166-1 region code", rest[0])
+	}
+	return ""
+}
+
+// hreflangSitemapCache reads each host's sitemap once and indexes its hreflang annotations by URL
+type hreflangSitemapCache struct {
+	auditor   *SEOAuditor
+	opts      AuditOptions
+	targetURL string
+	hosts     map[string]map[string][]HreflangLink
+}
+
+// lookup returns the sitemap annotations of pageURL, reading its host's /sitemap.xml on first use
+func (c *hreflangSitemapCache) lookup(pageURL string) []HreflangLink {
+	u, err := url.Parse(pageURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	if c.hosts == nil {
+		c.hosts = map[string]map[string][]HreflangLink{}
+	}
+	index, ok := c.hosts[origin]
+	if !ok {
+		index = map[string][]HreflangLink{}
+		c.auditor.readHreflangSitemap(origin+"/sitemap.xml", c.opts, c.targetURL, index, true)
+		c.hosts[origin] = index
+	}
+	for loc, links := range index {
+		if sameURL(loc, pageURL) {
+			return links
+		}
+	}
+	return nil
+}
+
+// sitemapDocument is a sitemap or sitemap index with its xhtml:link hreflang annotations
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapURL `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+type sitemapURL struct {
+	Loc   string `xml:"loc"`
+	Links []struct {
+		Rel      string `xml:"rel,attr"`
+		Hreflang string `xml:"hreflang,attr"`
+		Href     string `xml:"href,attr"`
+	} `xml:"link"`
+}
+
+// readHreflangSitemap adds the hreflang annotations of a sitemap to index, following a sitemap
+// index to its first few children on the same origin, since the audit's credentials go along
+func (a *SEOAuditor) readHreflangSitemap(sitemapURL string, opts AuditOptions, targetURL string, index map[string][]HreflangLink, followIndex bool) {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return
+	}
+	opts.applyToRequest(req, targetURL)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var body io.Reader = io.LimitReader(resp.Body, maxRawHTMLSize)
+	if strings.HasSuffix(strings.ToLower(req.URL.Path), ".gz") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return
+		}
+		defer gz.Close()
+		body = io.LimitReader(gz, maxRawHTMLSize)
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(body).Decode(&doc); err != nil {
+		return
+	}
+
+	for _, entry := range doc.URLs {
+		for _, link := range entry.Links {
+			if link.Hreflang != "" && strings.EqualFold(link.Rel, "alternate") {
+				loc := strings.TrimSpace(entry.Loc)
+				index[loc] = append(index[loc], HreflangLink{Hreflang: link.Hreflang, URL: strings.TrimSpace(link.Href)})
+			}
+		}
+	}
+	if followIndex {
+		for i, child := range doc.Sitemaps {
+			if i >= maxChildSitemaps {
+				break
+			}
+			childURL := strings.TrimSpace(child.Loc)
+			if originOf(childURL) != originOf(sitemapURL) {
+				continue
+			}
+			a.readHreflangSitemap(childURL, opts, targetURL, index, false)
+		}
+	}
+}
+
+// hreflangMatrix renders the cluster as a table: one row per page, one column per code. A cell
+// shows whether that page annotates the code with the same URL as the audited page.
+func hreflangMatrix(report *HreflangReport, pageURL string) string {
+	var sb strings.Builder
+	codes := append([]string{}, report.Codes...)
+	sort.SliceStable(codes, func(i, j int) bool {
+		// x-default goes last
+		return !strings.EqualFold(codes[i], "x-default") && strings.EqualFold(codes[j], "x-default")
+	})
+
+	sb.WriteString("| Page |")
+	for _, code := range codes {
+		sb.WriteString(fmt.Sprintf(" %s |", code))
+	}
+	sb.WriteString(" Links back |\n|------|")
+	for range codes {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("------------|\n")
+
+	expected := map[string]string{}
+	for _, annotation := range report.Annotations {
+		key := strings.ToLower(annotation.Hreflang)
+		if _, ok := expected[key]; !ok {
+			expected[key] = annotation.URL
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("| **%s** (this page) |", pageURL))
+	for range codes {
+		sb.WriteString(" ✅ |")
+	}
+	sb.WriteString(" — |\n")
+
+	for _, alternate := range report.Alternates {
+		sb.WriteString(fmt.Sprintf("| %s (%s) |", alternate.URL, strings.Join(alternate.Hreflang, ", ")))
+		for _, code := range codes {
+			link, ok := alternate.annotationFor(code)
+			switch {
+			case !alternate.Checked || alternate.Error != "":
+				sb.WriteString(" ? |")
+			case !ok:
+				sb.WriteString(" ❌ |")
+			case sameURL(link, expected[strings.ToLower(code)]):
+				sb.WriteString(" ✅ |")
+			default:
+				sb.WriteString(" ⚠️ |")
+			}
+		}
+		switch {
+		case !alternate.Checked || alternate.Error != "":
+			sb.WriteString(" ? |\n")
+		case alternate.LinksBack:
+			sb.WriteString(" ✅ |\n")
+		default:
+			sb.WriteString(" ❌ **broken** |\n")
+		}
+	}
+	sb.WriteString("\n✅ same URL as this page, ⚠️ different URL, ❌ missing, ? not checked\n\n")
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHreflangCodeProblem(t *testing.T) {
+	tests := []struct {
+		code  string
+		valid bool
+	}{
+		{"en", true},
+		{"en-US", true},
+		{"pt-br", true},
+		{"zh-Hant-TW", true},
+		{"x-default", true},
+		{"X-Default", true},
+		{"", false},
+		{"en_US", false},
+		{"en-UK", false},
+		{"us", false},
+		{"eng", false},
+		{"en-XX", false},
+		{"en-US-CA", false},
+	}
+	for _, tt := range tests {
+		problem := hreflangCodeProblem(tt.code)
+		if (problem == "") != tt.valid {
+			t.Errorf("hreflangCodeProblem(%q) = %q, want valid = %v", tt.code, problem, tt.valid)
+		}
+	}
+}
+
+func TestParseLinkHeaderHreflang(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []HreflangLink
+	}{
+		{
+			name:   "empty",
+			header: "",
+			want:   []HreflangLink{},
+		},
+		{
+			name:   "alternates",
+			header: `<https://example.com/en/>; rel="alternate"; hreflang="en", <https://example.com/de/>; rel="alternate"; hreflang="de"`,
+			want: []HreflangLink{
+				{Hreflang: "en", URL: "https://example.com/en/"},
+				{Hreflang: "de", URL: "https://example.com/de/"},
+			},
+		},
+		{
+			name:   "relative URL and unquoted values",
+			header: `</fr/>; rel=alternate; hreflang=fr`,
+			want:   []HreflangLink{{Hreflang: "fr", URL: "https://example.com/fr/"}},
+		},
+		{
+			name:   "other relations are ignored",
+			header: `<https://example.com/>; rel="canonical", <https://example.com/style.css>; rel="preload"; as="style"`,
+			want:   []HreflangLink{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLinkHeaderHreflang(tt.header, "https://example.com/page")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinkHeaderHreflang() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHreflangMatrix(t *testing.T) {
+	report := &HreflangReport{
+		Codes: []string{"x-default", "en", "de"},
+		Annotations: []HreflangAnnotation{
+			{Hreflang: "x-default", URL: "https://example.com/"},
+			{Hreflang: "en", URL: "https://example.com/en/"},
+			{Hreflang: "de", URL: "https://example.com/de/"},
+		},
+		Alternates: []HreflangAlternate{
+			{
+				Hreflang:  []string{"de"},
+				URL:       "https://example.com/de/",
+				Checked:   true,
+				LinksBack: true,
+				Annotations: []HreflangLink{
+					{Hreflang: "EN", URL: "https://example.com/en"},
+					{Hreflang: "de", URL: "https://example.com/de/"},
+					{Hreflang: "x-default", URL: "https://example.com/home"},
+				},
+			},
+			{Hreflang: []string{"fr"}, URL: "https://example.com/fr/"},
+		},
+	}
+	rows := strings.Split(hreflangMatrix(report, "https://example.com/en/"), "\n")
+
+	want := []string{
+		"| Page | en | de | x-default | Links back |",
+		"|------|---|---|---|------------|",
+		"| **https://example.com/en/** (this page) | ✅ | ✅ | ✅ | — |",
+		"| https://example.com/de/ (de) | ✅ | ✅ | ⚠️ | ✅ |",
+		"| https://example.com/fr/ (fr) | ? | ? | ? | ? |",
+	}
+	if !reflect.DeepEqual(rows[:len(want)], want) {
+		t.Errorf("hreflangMatrix() rows = %q, want %q", rows[:len(want)], want)
+	}
+}
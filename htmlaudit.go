@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Audit sources recorded in SEOAudit.Source
+const (
+	AuditSourceURL  = "url"
+	AuditSourceHTML = "html"
+)
+
+// htmlNotApplicableChecks lists the checks that need the network and are skipped for HTML audits
+var htmlNotApplicableChecks = []string{
+	"robots.txt",
+	"sitemap.xml",
+	"page load time",
+	"HTTP status code",
+	"web vitals",
+	"raw HTML comparison",
+	"Googlebot comparison",
+	"og:image loading",
+	"hreflang sitemap and return links",
+	"image byte savings",
+}
+
+// htmlNoBaseURLChecks are also skipped when no base URL says where the document is served from
+var htmlNoBaseURLChecks = []string{
+	"HTTPS",
+	"mixed content",
+}
+
+// HTMLAuditRequest represents the request body for auditing supplied HTML
+type HTMLAuditRequest struct {
+	HTML    string            `json:"html"`
+	BaseURL string            `json:"base_url"` // URL the document would be served from
+	Headers map[string]string `json:"headers"`  // Response headers the document would be served with
+	AuditOptions
+}
+
+// baseElementScript adds a <base> element so relative URLs resolve against the supplied base URL
+const baseElementScript = `(baseURL) => {
+	if (document.querySelector('base[href]')) return;
+	const base = document.createElement('base');
+	base.href = baseURL;
+	document.head.prepend(base);
+}`
+
+// AuditHTML runs the DOM-based checks against supplied HTML without any network access.
+// Checks that need the network are skipped and listed in NotApplicable.
+func (a *SEOAuditor) AuditHTML(req HTMLAuditRequest) (*SEOAudit, error) {
+	baseURL := req.BaseURL
+	notApplicable := append([]string{}, htmlNotApplicableChecks...)
+	if baseURL == "" {
+		baseURL = "about:blank"
+		notApplicable = append(notApplicable, htmlNoBaseURLChecks...)
+	}
+
+	audit := &SEOAudit{
+		URL:           baseURL,
+		Source:        AuditSourceHTML,
+		Timestamp:     time.Now(),
+		NotApplicable: notApplicable,
+	}
+
+	// Logging in or rendering twice both need the network
+	opts := req.AuditOptions
+	opts.Login = nil
+	opts.CompareWithGooglebot = false
+	if req.BaseURL == "" {
+		opts.Cookies = nil
+	}
+
+	context, err := a.newBrowserContext(baseURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer context.Close()
+
+	page, err := context.NewPage()
+	if err != nil {
+		return nil, fmt.Errorf("could not create page: %v", err)
+	}
+	defer page.Close()
+
+	// Nothing may leave the browser, subresources simply fail to load
+	err = page.Route("**/*", func(route playwright.Route) {
+		route.Abort()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not block network access: %v", err)
+	}
+
+	err = page.SetContent(req.HTML, playwright.PageSetContentOptions{
+		WaitUntil: playwright.WaitUntilStateLoad,
+		Timeout:   playwright.Float(30000),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load HTML: %v", err)
+	}
+
+	if req.BaseURL != "" {
+		if _, err := page.Evaluate(baseElementScript, req.BaseURL); err != nil {
+			return nil, fmt.Errorf("could not apply base URL: %v", err)
+		}
+	}
+
+	audit.Consent = a.handleConsent(page, baseURL, opts)
+
+	if userAgent, err := page.Evaluate("() => navigator.userAgent"); err == nil {
+		audit.UserAgent, _ = userAgent.(string)
+	}
+
+	var headers map[string]string
+	if req.Headers != nil {
+		headers = map[string]string{}
+		for name, value := range req.Headers {
+			headers[strings.ToLower(name)] = value
+		}
+	}
+
+	a.runAudits(page, audit, auditTarget{
+		url:           baseURL,
+		opts:          opts,
+		headers:       headers,
+		networkChecks: false,
+	})
+	a.finishAudit(audit)
+
+	return audit, nil
+}
+
+// runHTMLAuditCLI audits an HTML file from the command line and prints the result to stdout
+func runHTMLAuditCLI(path, baseURL string, headers headerFlags, format string) error {
+	if format != "json" && format != "markdown" {
+		return fmt.Errorf("unknown format %q (expected json or markdown)", format)
+	}
+
+	var html []byte
+	var err error
+	if path == "-" {
+		html, err = io.ReadAll(os.Stdin)
+	} else {
+		html, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("could not read HTML: %v", err)
+	}
+
+	auditor, err := NewSEOAuditor()
+	if err != nil {
+		return err
+	}
+	defer auditor.Close()
+
+	req := HTMLAuditRequest{
+		HTML:    string(html),
+		BaseURL: baseURL,
+	}
+	if len(headers) > 0 {
+		req.Headers = headers
+	}
+
+	audit, err := auditor.AuditHTML(req)
+	if err != nil {
+		return err
+	}
+
+	if format == "markdown" {
+		fmt.Print(audit.Markdown)
+		return nil
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(audit)
+}
+
+// headerFlags collects repeated -header "Name: value" command line flags
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	pairs := []string{}
+	for name, value := range h {
+		pairs = append(pairs, name+": "+value)
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func (h headerFlags) Set(value string) error {
+	name, headerValue, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("header must look like \"Name: value\"")
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeaderFlagsSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    headerFlags
+		wantErr bool
+	}{
+		{
+			name:   "single header",
+			values: []string{"X-Robots-Tag: noindex"},
+			want:   headerFlags{"X-Robots-Tag": "noindex"},
+		},
+		{
+			name:   "value containing a colon",
+			values: []string{"Link: <https://example.com/>; rel=canonical"},
+			want:   headerFlags{"Link": "<https://example.com/>; rel=canonical"},
+		},
+		{
+			name:   "repeated header keeps the last value",
+			values: []string{"Content-Language: en", "Content-Language: de"},
+			want:   headerFlags{"Content-Language": "de"},
+		},
+		{
+			name:    "missing colon",
+			values:  []string{"noindex"},
+			want:    headerFlags{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := headerFlags{}
+			var err error
+			for _, value := range tt.values {
+				if err = got.Set(value); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("headers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// maxAnalyzedImages caps how many images are downloaded and re-encoded
+const maxAnalyzedImages = 30
+
+// maxImageDownloadSize is how much of a single image is downloaded
+const maxImageDownloadSize = 20 * 1024 * 1024
+
+// maxDecodedPixels caps the dimensions of an image decoded for re-encoding, so a small file declaring
+// huge dimensions can't allocate gigabytes
+const maxDecodedPixels = 25 * 1000 * 1000
+
+// oversizedImageRatio is how much larger than needed for its displayed size an image may be
+const oversizedImageRatio = 1.5
+
+// minImageSavings is the least estimated saving worth reporting for an image
+const minImageSavings = 4 * 1024
+
+// responsiveImageWidth is the displayed width from which an image should offer srcset candidates
+const responsiveImageWidth = 300
+
+// Modern formats relative to a JPEG (or PNG with transparency) of the same pixels, at comparable
+// quality. These are the typical ratios reported by the WebP and AVIF compression studies; the
+// standard library has no WebP or AVIF encoder, so those sizes are estimated, not measured.
+const (
+	webPSizeRatio = 0.74
+	avifSizeRatio = 0.5
+)
+
+// imageEstimateMethod explains how the savings in the report were obtained
+var imageEstimateMethod = fmt.Sprintf("Resized sizes are measured by scaling each image to its displayed size and re-encoding it with Go's image libraries (JPEG quality 80, or PNG with transparency). WebP and AVIF sizes are estimates: the re-encoded size × %.2f and × %.2f, typical ratios from published compression studies.", webPSizeRatio, avifSizeRatio)
+
+// ImageOptimizationReport inspects every image of the page and estimates how many bytes it wastes
+type ImageOptimizationReport struct {
+	ImageCount       int             `json:"image_count"`
+	Analyzed         int             `json:"analyzed"`
+	TotalBytes       int64           `json:"total_bytes"`
+	PotentialSavings int64           `json:"potential_savings_bytes"`
+	EstimateMethod   string          `json:"estimate_method"` // How resized, WebP and AVIF sizes were obtained
+	Images           []ImageAnalysis `json:"images"`          // Ranked by potential savings
+	Issues           []string        `json:"issues"`
+}
+
+// ImageAnalysis is the optimization audit of one image
+type ImageAnalysis struct {
+	URL              string   `json:"url"`
+	Selector         string   `json:"selector"`
+	Format           string   `json:"format"` // "jpeg", "png", "gif", "webp", "avif", "svg" or ""
+	ContentType      string   `json:"content_type"`
+	Bytes            int64    `json:"bytes"`
+	IntrinsicWidth   int      `json:"intrinsic_width"`
+	IntrinsicHeight  int      `json:"intrinsic_height"`
+	DisplayedWidth   int      `json:"displayed_width"`
+	DisplayedHeight  int      `json:"displayed_height"`
+	AboveTheFold     bool     `json:"above_the_fold"`
+	Loading          string   `json:"loading"`
+	HasDimensions    bool     `json:"has_dimensions"` // width and height attributes reserve its space
+	HasSrcset        bool     `json:"has_srcset"`
+	HasSizes         bool     `json:"has_sizes"`
+	Oversized        bool     `json:"oversized"`
+	ResizedBytes     int64    `json:"resized_bytes,omitempty"` // Measured size once resized to its displayed size and re-encoded
+	WebPBytes        int64    `json:"webp_bytes,omitempty"`    // Estimate: ResizedBytes × webPSizeRatio, not an actual WebP encode
+	AVIFBytes        int64    `json:"avif_bytes,omitempty"`    // Estimate: ResizedBytes × avifSizeRatio, not an actual AVIF encode
+	PotentialSavings int64    `json:"potential_savings_bytes"`
+	Issues           []string `json:"issues"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// imageInventoryScript lists the page's images with their rendered box and loading attributes
+const imageInventoryScript = `() => {
+	const selectorOf = ` + cssSelectorFunction + `;
+	const foldHeight = window.innerHeight;
+	return Array.from(document.images).map(img => {
+		const rect = img.getBoundingClientRect();
+		const style = window.getComputedStyle(img);
+		const visible = rect.width > 0 && rect.height > 0 && style.display !== 'none' && style.visibility !== 'hidden';
+		const picture = img.parentElement && img.parentElement.tagName === 'PICTURE' ? img.parentElement : null;
+		const sources = picture ? Array.from(picture.querySelectorAll('source[srcset]')) : [];
+		const srcset = img.getAttribute('srcset') || sources.map(s => s.getAttribute('srcset')).join(', ');
+		return {
+			src: img.currentSrc || img.src,
+			selector: selectorOf(img),
+			naturalWidth: img.naturalWidth,
+			naturalHeight: img.naturalHeight,
+			displayedWidth: Math.round(rect.width),
+			displayedHeight: Math.round(rect.height),
+			visible: visible,
+			aboveTheFold: visible && rect.top + window.scrollY < foldHeight,
+			loading: (img.getAttribute('loading') || '').toLowerCase(),
+			hasDimensions: img.hasAttribute('width') && img.hasAttribute('height'),
+			srcset: srcset,
+			hasSizes: img.hasAttribute('sizes') || sources.some(s => s.hasAttribute('sizes'))
+		};
+	});
+}`
+
+// imageInventoryEntry is one image returned by imageInventoryScript
+type imageInventoryEntry struct {
+	Src             string `json:"src"`
+	Selector        string `json:"selector"`
+	NaturalWidth    int    `json:"naturalWidth"`
+	NaturalHeight   int    `json:"naturalHeight"`
+	DisplayedWidth  int    `json:"displayedWidth"`
+	DisplayedHeight int    `json:"displayedHeight"`
+	Visible         bool   `json:"visible"`
+	AboveTheFold    bool   `json:"aboveTheFold"`
+	Loading         string `json:"loading"`
+	HasDimensions   bool   `json:"hasDimensions"`
+	Srcset          string `json:"srcset"`
+	HasSizes        bool   `json:"hasSizes"`
+}
+
+// auditImageOptimization checks the markup of every image for layout shift, responsive and lazy
+// loading problems and, when the network is available, downloads each one to estimate the bytes
+// saved by resizing it to its displayed size and re-encoding it as WebP or AVIF.
+func (a *SEOAuditor) auditImageOptimization(page playwright.Page, target auditTarget) *ImageOptimizationReport {
+	var entries []imageInventoryEntry
+	if err := evaluateInto(page, imageInventoryScript, &entries); err != nil || len(entries) == 0 {
+		return nil
+	}
+
+	report := &ImageOptimizationReport{
+		EstimateMethod: imageEstimateMethod,
+		ImageCount:     len(entries),
+		Images:         []ImageAnalysis{},
+		Issues:         []string{},
+	}
+
+	pixelRatio := 1.0
+	if result, err := page.Evaluate("() => window.devicePixelRatio || 1"); err == nil {
+		if ratio, ok := result.(float64); ok && ratio > 0 {
+			pixelRatio = ratio
+		}
+	}
+
+	counts := map[string]int{}
+	for _, entry := range entries {
+		analysis := ImageAnalysis{
+			URL:             entry.Src,
+			Selector:        entry.Selector,
+			IntrinsicWidth:  entry.NaturalWidth,
+			IntrinsicHeight: entry.NaturalHeight,
+			DisplayedWidth:  entry.DisplayedWidth,
+			DisplayedHeight: entry.DisplayedHeight,
+			AboveTheFold:    entry.AboveTheFold,
+			Loading:         entry.Loading,
+			HasDimensions:   entry.HasDimensions,
+			HasSrcset:       entry.Srcset != "",
+			HasSizes:        entry.HasSizes,
+			Issues:          []string{},
+		}
+		analysis.Format = imageFormatFromURL(analysis.URL)
+
+		// Markup checks apply to every rendered image, whether or not it can be downloaded
+		if entry.Visible {
+			if !analysis.HasDimensions {
+				analysis.Issues = append(analysis.Issues, "Missing width and height attributes, so the layout shifts when it loads")
+				counts["dimensions"]++
+			}
+			if analysis.AboveTheFold && analysis.Loading == "lazy" {
+				analysis.Issues = append(analysis.Issues, "Lazy-loaded although it is above the fold, which delays the largest contentful paint")
+				counts["lazy"]++
+			}
+			if !analysis.AboveTheFold && analysis.Loading != "lazy" {
+				analysis.Issues = append(analysis.Issues, `Below the fold but not loading="lazy"`)
+				counts["eager"]++
+			}
+			if analysis.Format != "svg" && analysis.DisplayedWidth >= responsiveImageWidth {
+				if !analysis.HasSrcset {
+					analysis.Issues = append(analysis.Issues, "No srcset, so every screen downloads the same size")
+					counts["srcset"]++
+				} else if !analysis.HasSizes && hasWidthDescriptors(entry.Srcset) {
+					analysis.Issues = append(analysis.Issues, "srcset with width descriptors but no sizes, so the browser assumes the full viewport width")
+					counts["sizes"]++
+				}
+			}
+		}
+
+		if target.networkChecks && report.Analyzed < maxAnalyzedImages && strings.HasPrefix(analysis.URL, "http") {
+			report.Analyzed++
+			if err := a.analyzeImageBytes(&analysis, entry, pixelRatio, target); err != nil {
+				analysis.Error = err.Error()
+			}
+			report.TotalBytes += analysis.Bytes
+			report.PotentialSavings += analysis.PotentialSavings
+			if analysis.Oversized {
+				counts["oversized"]++
+			}
+		}
+		report.Images = append(report.Images, analysis)
+	}
+
+	sort.SliceStable(report.Images, func(i, j int) bool {
+		return report.Images[i].PotentialSavings > report.Images[j].PotentialSavings
+	})
+
+	if target.networkChecks && report.ImageCount > report.Analyzed {
+		report.Issues = append(report.Issues, fmt.Sprintf("Only %d of %d images were downloaded for savings estimates", report.Analyzed, report.ImageCount))
+	}
+	if report.PotentialSavings >= minImageSavings {
+		report.Issues = append(report.Issues, fmt.Sprintf("Images could be %s smaller (%s in total) by resizing and serving WebP or AVIF", formatBytes(report.PotentialSavings), formatBytes(report.TotalBytes)))
+	}
+	if counts["oversized"] > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d image(s) are much larger than their displayed size", counts["oversized"]))
+	}
+	if counts["dimensions"] > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d image(s) have no width and height attributes (layout shift risk)", counts["dimensions"]))
+	}
+	if counts["lazy"] > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d above-the-fold image(s) are lazy-loaded", counts["lazy"]))
+	}
+	if counts["eager"] > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf(`%d below-the-fold image(s) could use loading="lazy"`, counts["eager"]))
+	}
+	if counts["srcset"] > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d large image(s) have no srcset", counts["srcset"]))
+	}
+	if counts["sizes"] > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d image(s) use srcset width descriptors without sizes", counts["sizes"]))
+	}
+
+	return report
+}
+
+// analyzeImageBytes downloads an image, reads its format and size, and estimates its savings
+func (a *SEOAuditor) analyzeImageBytes(analysis *ImageAnalysis, entry imageInventoryEntry, pixelRatio float64, target auditTarget) error {
+	data, contentType, err := fetchImage(analysis.URL, target)
+	if err != nil {
+		return err
+	}
+	analysis.ContentType = contentType
+	analysis.Bytes = int64(len(data))
+	if format := imageFormatFromContentType(contentType); format != "" {
+		analysis.Format = format
+	}
+	if analysis.Format == "svg" {
+		return nil
+	}
+
+	config, _, configErr := image.DecodeConfig(bytes.NewReader(data))
+	if configErr == nil {
+		analysis.IntrinsicWidth = config.Width
+		analysis.IntrinsicHeight = config.Height
+	}
+
+	// The pixels the image needs to look sharp where it is displayed
+	neededWidth := int(float64(entry.DisplayedWidth) * pixelRatio)
+	neededHeight := int(float64(entry.DisplayedHeight) * pixelRatio)
+	if entry.Visible && neededWidth > 0 && neededHeight > 0 && analysis.IntrinsicWidth > 0 &&
+		float64(analysis.IntrinsicWidth) > float64(neededWidth)*oversizedImageRatio &&
+		float64(analysis.IntrinsicHeight) > float64(neededHeight)*oversizedImageRatio {
+		analysis.Oversized = true
+		analysis.Issues = append(analysis.Issues, fmt.Sprintf("Intrinsic size %d×%d but displayed at %d×%d", analysis.IntrinsicWidth, analysis.IntrinsicHeight, entry.DisplayedWidth, entry.DisplayedHeight))
+	} else {
+		neededWidth, neededHeight = analysis.IntrinsicWidth, analysis.IntrinsicHeight
+	}
+
+	baseline := analysis.Bytes
+	modern := analysis.Format == "webp" || analysis.Format == "avif"
+	if modern || configErr != nil || isAnimatedGIF(analysis.Format, data) {
+		// Formats we can't re-encode are only scaled by the pixels resizing would drop
+		if analysis.Oversized {
+			baseline = int64(float64(analysis.Bytes) * float64(neededWidth*neededHeight) / float64(analysis.IntrinsicWidth*analysis.IntrinsicHeight))
+			analysis.ResizedBytes = baseline
+		}
+	} else if encoded, err := reencodeImage(data, neededWidth, neededHeight); err == nil {
+		if analysis.Oversized {
+			analysis.ResizedBytes = encoded
+		}
+		if encoded < baseline {
+			baseline = encoded
+		}
+	}
+
+	best := baseline
+	if !modern {
+		analysis.WebPBytes = int64(float64(baseline) * webPSizeRatio)
+		analysis.AVIFBytes = int64(float64(baseline) * avifSizeRatio)
+		best = analysis.AVIFBytes
+	}
+	if savings := analysis.Bytes - best; savings >= minImageSavings {
+		analysis.PotentialSavings = savings
+		if !modern {
+			analysis.Issues = append(analysis.Issues, fmt.Sprintf("Served as %s; WebP would be about %s and AVIF about %s", strings.ToUpper(analysis.Format), formatBytes(analysis.WebPBytes), formatBytes(analysis.AVIFBytes)))
+		}
+	}
+	return nil
+}
+
+// fetchImage downloads an image with the audit's credentials and headers
+func fetchImage(imageURL string, target auditTarget) ([]byte, string, error) {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	target.opts.applyToRequest(req, target.url)
+	req.Header.Set("Accept", "image/avif,image/webp,image/*,*/*;q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageDownloadSize))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// reencodeImage resizes an image to width×height and encodes it the way a basic optimizer would:
+// JPEG at quality 80, or PNG when it has transparency. It returns the encoded size.
+func reencodeImage(data []byte, width, height int) (int64, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > maxDecodedPixels {
+		return 0, fmt.Errorf("%d×%d image is too large to re-encode", config.Width, config.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+
+	img := src
+	if bounds := src.Bounds(); width > 0 && height > 0 && (bounds.Dx() != width || bounds.Dy() != height) {
+		resized := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(resized, resized.Bounds(), src, bounds, draw.Src, nil)
+		img = resized
+	}
+
+	var buf bytes.Buffer
+	if hasTransparency(img) {
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		err = encoder.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80})
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(buf.Len()), nil
+}
+
+// hasTransparency reports whether any pixel of the image is not fully opaque
+func hasTransparency(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return !opaque.Opaque()
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, alpha := img.At(x, y).RGBA(); alpha != 0xffff {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isAnimatedGIF reports whether a GIF has more than one frame, which a still re-encode can't replace.
+// It walks the GIF blocks instead of decoding them, so frames of any size cost no memory.
+func isAnimatedGIF(format string, data []byte) bool {
+	if format != "gif" || len(data) < 13 || !bytes.HasPrefix(data, []byte("GIF8")) {
+		return false
+	}
+	pos := 13 // Header and logical screen descriptor
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&0x07 + 1) // Global color table
+	}
+
+	// skipSubBlocks moves past a chain of length-prefixed sub-blocks ending in an empty one
+	skipSubBlocks := func() bool {
+		for pos < len(data) {
+			size := int(data[pos])
+			pos += size + 1
+			if size == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // Extension: label, then sub-blocks
+			pos += 2
+			if !skipSubBlocks() {
+				return false
+			}
+		case 0x2C: // Image descriptor, optional local color table, LZW code size, then sub-blocks
+			if pos+10 > len(data) {
+				return false
+			}
+			if flags := data[pos+9]; flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			pos += 11
+			if !skipSubBlocks() {
+				return false
+			}
+			if frames++; frames > 1 {
+				return true
+			}
+		default: // Trailer or corrupt data
+			return false
+		}
+	}
+	return false
+}
+
+// hasWidthDescriptors reports whether a srcset lists candidates by width ("480w") rather than density
+func hasWidthDescriptors(srcset string) bool {
+	for _, candidate := range strings.Split(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 1 && strings.HasSuffix(fields[len(fields)-1], "w") {
+			return true
+		}
+	}
+	return false
+}
+
+// imageFormatFromContentType maps an image MIME type to its format name
+func imageFormatFromContentType(contentType string) string {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch mediaType {
+	case "image/jpeg", "image/jpg", "image/pjpeg":
+		return "jpeg"
+	case "image/svg+xml":
+		return "svg"
+	case "image/png", "image/gif", "image/webp", "image/avif":
+		return strings.TrimPrefix(mediaType, "image/")
+	}
+	return ""
+}
+
+// imageFormatFromURL guesses an image's format from its extension, for images that aren't downloaded
+func imageFormatFromURL(imageURL string) string {
+	if strings.HasPrefix(imageURL, "data:") {
+		return imageFormatFromContentType(strings.TrimPrefix(imageURL, "data:"))
+	}
+	path := strings.ToLower(strings.SplitN(strings.SplitN(imageURL, "?", 2)[0], "#", 2)[0])
+	switch {
+	case strings.HasSuffix(path, ".jpg"), strings.HasSuffix(path, ".jpeg"):
+		return "jpeg"
+	case strings.HasSuffix(path, ".png"):
+		return "png"
+	case strings.HasSuffix(path, ".gif"):
+		return "gif"
+	case strings.HasSuffix(path, ".webp"):
+		return "webp"
+	case strings.HasSuffix(path, ".avif"):
+		return "avif"
+	case strings.HasSuffix(path, ".svg"):
+		return "svg"
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+// encodeGIF builds a GIF with the given number of 4×4 frames
+func encodeGIF(t *testing.T, frames int) []byte {
+	t.Helper()
+	animation := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		animation.Image = append(animation.Image, image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{color.Black, color.White}))
+		animation.Delay = append(animation.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, animation); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// encodePNG builds a width×height PNG filled with one color
+func encodePNG(t *testing.T, width, height int, fill color.Color) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestIsAnimatedGIF(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   []byte
+		want   bool
+	}{
+		{"animated", "gif", encodeGIF(t, 3), true},
+		{"single frame", "gif", encodeGIF(t, 1), false},
+		{"not a GIF", "png", encodeGIF(t, 3), false},
+		{"corrupt", "gif", []byte("GIF89a"), false},
+		{"huge frames aren't decoded", "gif", hugeGIF(2), true},
+		{"single huge frame", "gif", hugeGIF(1), false},
+	}
+	for _, tt := range tests {
+		if got := isAnimatedGIF(tt.format, tt.data); got != tt.want {
+			t.Errorf("%s: isAnimatedGIF() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// hugeGIF builds a GIF whose frames declare 65535×65535 pixels but carry no pixel data
+func hugeGIF(frames int) []byte {
+	data := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+	for i := 0; i < frames; i++ {
+		data = append(data, "\x2c\x00\x00\x00\x00\xff\xff\xff\xff\x00\x08\x00"...)
+	}
+	return append(data, 0x3b)
+}
+
+func TestReencodeImage(t *testing.T) {
+	opaque := encodePNG(t, 64, 64, color.NRGBA{200, 40, 40, 255})
+	transparent := encodePNG(t, 64, 64, color.NRGBA{200, 40, 40, 128})
+
+	if _, err := reencodeImage(opaque, 32, 32); err != nil {
+		t.Errorf("reencodeImage(opaque) error = %v", err)
+	}
+	if _, err := reencodeImage(transparent, 0, 0); err != nil {
+		t.Errorf("reencodeImage(transparent) error = %v", err)
+	}
+	if _, err := reencodeImage([]byte("not an image"), 10, 10); err == nil {
+		t.Errorf("reencodeImage(garbage) succeeded")
+	}
+}
+
+func TestHasTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	if !hasTransparency(img) {
+		t.Errorf("hasTransparency(empty NRGBA) = false")
+	}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			img.Set(x, y, color.NRGBA{0, 0, 0, 255})
+		}
+	}
+	if hasTransparency(img) {
+		t.Errorf("hasTransparency(opaque NRGBA) = true")
+	}
+}
+
+func TestHasWidthDescriptors(t *testing.T) {
+	tests := []struct {
+		srcset string
+		want   bool
+	}{
+		{"small.jpg 480w, large.jpg 1080w", true},
+		{"photo.jpg 1x, photo@2x.jpg 2x", false},
+		{"photo.jpg", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := hasWidthDescriptors(tt.srcset); got != tt.want {
+			t.Errorf("hasWidthDescriptors(%q) = %v, want %v", tt.srcset, got, tt.want)
+		}
+	}
+}
+
+func TestImageFormatFromContentType(t *testing.T) {
+	tests := map[string]string{
+		"image/jpeg":               "jpeg",
+		"image/pjpeg":              "jpeg",
+		"IMAGE/PNG; charset=utf-8": "png",
+		"image/webp":               "webp",
+		"image/avif":               "avif",
+		"image/svg+xml":            "svg",
+		"application/octet-stream": "",
+	}
+	for contentType, want := range tests {
+		if got := imageFormatFromContentType(contentType); got != want {
+			t.Errorf("imageFormatFromContentType(%q) = %q, want %q", contentType, got, want)
+		}
+	}
+}
+
+func TestImageFormatFromURL(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/photo.JPG":           "jpeg",
+		"https://example.com/photo.webp?w=400":    "webp",
+		"https://example.com/icon.svg#sprite":     "svg",
+		"data:image/png;base64,iVBORw0KGgo=":      "png",
+		"https://example.com/image?format=avif":   "",
+		"https://example.com/gallery.avif/thumbs": "",
+	}
+	for imageURL, want := range tests {
+		if got := imageFormatFromURL(imageURL); got != want {
+			t.Errorf("imageFormatFromURL(%q) = %q, want %q", imageURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"regexp"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxTabStops caps how many times the page is tabbed through
+const maxTabStops = 200
+
+// maxFocusScreenshots caps how many elements without a focus indicator are captured
+const maxFocusScreenshots = 10
+
+// focusScreenshotPadding is the margin kept around an element in its focus screenshot, in pixels
+const focusScreenshotPadding = 8
+
+// KeyboardReport is the page as a keyboard user experiences it: the order Tab visits its
+// elements, whether focus can be seen and escaped, and what can't be reached at all
+type KeyboardReport struct {
+	FocusOrder        []FocusStop              `json:"focus_order"`
+	Complete          bool                     `json:"complete"` // Tabbing went through the whole page and back to the start
+	Trap              *FocusTrap               `json:"trap,omitempty"`
+	SkipLink          SkipLinkCheck            `json:"skip_link"`
+	MissingFocusStyle int                      `json:"missing_focus_style"`
+	Unreachable       []AccessibilityViolation `json:"unreachable"`
+	Issues            []string                 `json:"issues"`
+}
+
+// FocusStop is one element reached with Tab
+type FocusStop struct {
+	Order          int    `json:"order"`
+	Selector       string `json:"selector"`
+	Tag            string `json:"tag"`
+	Name           string `json:"name"`
+	FocusIndicator bool   `json:"focus_indicator"`      // Its appearance changes when focused
+	Screenshot     string `json:"screenshot,omitempty"` // PNG data URI of the focused element, when it has no indicator
+}
+
+// FocusTrap describes where keyboard focus got stuck
+type FocusTrap struct {
+	Selectors []string `json:"selectors"` // The elements focus cycles between
+	Message   string   `json:"message"`
+}
+
+// SkipLinkCheck reports whether the first tab stop skips to the main content, and whether it works
+type SkipLinkCheck struct {
+	Present bool   `json:"present"`
+	Text    string `json:"text,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Works   bool   `json:"works"`
+	Problem string `json:"problem,omitempty"`
+}
+
+// focusStyleFunction is a JavaScript function that summarizes the styles a focus indicator changes
+const focusStyleFunction = `(el) => {
+	const s = window.getComputedStyle(el);
+	const outline = s.outlineStyle === 'none' || parseFloat(s.outlineWidth) === 0 ? 'none' : s.outlineStyle + ' ' + s.outlineWidth + ' ' + s.outlineColor;
+	return [outline, s.boxShadow, s.borderColor, s.borderWidth, s.backgroundColor, s.color, s.textDecorationLine].join('|');
+}`
+
+// focusableSelector matches elements that are in the tab order unless disabled or tabindex="-1"
+const focusableSelector = `a[href], area[href], button, input:not([type="hidden"]), select, textarea, iframe, summary, [tabindex], [contenteditable="true"]`
+
+// keyboardBaselineScript blurs the page and records the unfocused style of every focusable
+// element, and lists the elements that look interactive but can't receive focus
+const keyboardBaselineScript = `() => {
+	const selectorOf = ` + cssSelectorFunction + `;
+	const styleOf = ` + focusStyleFunction + `;
+	const isRendered = (el) => {
+		const style = window.getComputedStyle(el);
+		return style.display !== 'none' && style.visibility !== 'hidden' && el.getClientRects().length > 0;
+	};
+	if (document.activeElement) document.activeElement.blur();
+	window.scrollTo(0, 0);
+
+	const focusable = Array.from(document.querySelectorAll('` + focusableSelector + `'))
+		.filter(el => !el.disabled && el.getAttribute('tabindex') !== '-1' && !el.closest('[inert]') && isRendered(el));
+	const styles = {};
+	focusable.forEach(el => { styles[selectorOf(el)] = styleOf(el); });
+
+	const widgetRoles = ['button', 'link', 'checkbox', 'radio', 'switch', 'tab', 'menuitem', 'option', 'slider', 'combobox', 'textbox'];
+	const pseudoControls = Array.from(document.querySelectorAll('[role], [onclick]'))
+		.filter(el => !el.matches('` + focusableSelector + `') && isRendered(el))
+		.filter(el => el.hasAttribute('onclick') || widgetRoles.includes((el.getAttribute('role') || '').toLowerCase()))
+		.map(el => ({
+			selector: selectorOf(el),
+			snippet: el.outerHTML.replace(/\s+/g, ' ').slice(0, 120),
+			role: el.getAttribute('role') || el.tagName.toLowerCase()
+		}));
+
+	return { focusable: Object.keys(styles), styles: styles, pseudoControls: pseudoControls };
+}`
+
+// keyboardBaseline is what keyboardBaselineScript returns
+type keyboardBaseline struct {
+	Focusable      []string          `json:"focusable"`
+	Styles         map[string]string `json:"styles"`
+	PseudoControls []struct {
+		Selector string `json:"selector"`
+		Snippet  string `json:"snippet"`
+		Role     string `json:"role"`
+	} `json:"pseudoControls"`
+}
+
+// activeElementScript describes the element that currently has focus. Focus inside a web component
+// leaves document.activeElement on the shadow host, so it follows shadowRoot.activeElement down;
+// the selector joins each level with a descendant combinator, which Playwright pierces.
+const activeElementScript = `() => {
+	let el = document.activeElement;
+	if (!el || el === document.body || el === document.documentElement) return { selector: '' };
+	const path = [el];
+	while (el.shadowRoot && el.shadowRoot.activeElement) {
+		el = el.shadowRoot.activeElement;
+		path.push(el);
+	}
+	const selectorOf = ` + cssSelectorFunction + `;
+	const nameOf = ` + accessibleNameFunction + `;
+	const styleOf = ` + focusStyleFunction + `;
+	const rect = el.getBoundingClientRect();
+	const href = el.getAttribute('href') || '';
+	return {
+		selector: path.map(selectorOf).join(' '),
+		tag: el.tagName.toLowerCase(),
+		name: nameOf(el).slice(0, 80),
+		style: styleOf(el),
+		href: href,
+		x: rect.x, y: rect.y, width: rect.width, height: rect.height
+	};
+}`
+
+// activeElement is what activeElementScript returns
+type activeElement struct {
+	Selector string  `json:"selector"`
+	Tag      string  `json:"tag"`
+	Name     string  `json:"name"`
+	Style    string  `json:"style"`
+	Href     string  `json:"href"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+}
+
+// skipLinkTargetScript checks that the focus is now at or after the skip link's target
+const skipLinkTargetScript = `(id) => {
+	const target = document.getElementById(id) || document.getElementsByName(id)[0];
+	if (!target) return 'missing';
+	const active = document.activeElement;
+	if (!active || active === document.body) return 'no-focus';
+	if (target === active || target.contains(active)) return 'ok';
+	return target.compareDocumentPosition(active) & Node.DOCUMENT_POSITION_FOLLOWING ? 'ok' : 'before';
+}`
+
+var skipLinkPattern = regexp.MustCompile(`(?i)skip|jump|main content|zum inhalt|saltar|aller au contenu|vai al contenuto|naar inhoud`)
+
+// auditKeyboardNavigation tabs through the page with the keyboard, recording the focus order,
+// focus traps and elements without a visible focus indicator, then checks the skip link and
+// lists interactive elements the keyboard never reaches. It moves focus and may scroll the page,
+// so it runs after the other checks.
+func auditKeyboardNavigation(page playwright.Page) KeyboardReport {
+	report := KeyboardReport{
+		FocusOrder:  []FocusStop{},
+		Unreachable: []AccessibilityViolation{},
+		Issues:      []string{},
+	}
+
+	var baseline keyboardBaseline
+	if err := evaluateInto(page, keyboardBaselineScript, &baseline); err != nil {
+		report.Issues = append(report.Issues, fmt.Sprintf("Unable to prepare keyboard navigation: %v", err))
+		return report
+	}
+
+	visited := map[string]int{}
+	var first activeElement
+	screenshots := 0
+	previous := ""
+	for step := 0; step < maxTabStops; step++ {
+		if err := page.Keyboard().Press("Tab"); err != nil {
+			report.Issues = append(report.Issues, fmt.Sprintf("Unable to press Tab: %v", err))
+			break
+		}
+		var active activeElement
+		if err := evaluateInto(page, activeElementScript, &active); err != nil {
+			break
+		}
+
+		// Focus left the document after the last element
+		if active.Selector == "" {
+			report.Complete = len(report.FocusOrder) > 0
+			break
+		}
+		// Focus moves through an iframe's own elements while the iframe stays the active element
+		if active.Selector == previous && active.Tag == "iframe" {
+			continue
+		}
+		if active.Selector == previous {
+			report.Trap = &FocusTrap{
+				Selectors: []string{active.Selector},
+				Message:   fmt.Sprintf("Focus is stuck on %s and Tab can't move past it", active.Selector),
+			}
+			break
+		}
+		if order, seen := visited[active.Selector]; seen {
+			if order == 1 {
+				report.Complete = true
+			} else {
+				cycle := []string{}
+				for _, stop := range report.FocusOrder[order-1:] {
+					cycle = append(cycle, stop.Selector)
+				}
+				report.Trap = &FocusTrap{
+					Selectors: cycle,
+					Message:   fmt.Sprintf("Focus cycles between %d elements starting at %s and never reaches the rest of the page", len(cycle), active.Selector),
+				}
+			}
+			break
+		}
+
+		stop := FocusStop{
+			Order:          len(report.FocusOrder) + 1,
+			Selector:       active.Selector,
+			Tag:            active.Tag,
+			Name:           active.Name,
+			FocusIndicator: true,
+		}
+		if unfocused, ok := baseline.Styles[active.Selector]; ok && unfocused == active.Style {
+			stop.FocusIndicator = false
+			report.MissingFocusStyle++
+			if screenshots < maxFocusScreenshots {
+				if shot, err := focusScreenshot(page, active); err == nil {
+					stop.Screenshot = shot
+					screenshots++
+				}
+			}
+		}
+		if stop.Order == 1 {
+			first = active
+		}
+		visited[active.Selector] = stop.Order
+		report.FocusOrder = append(report.FocusOrder, stop)
+		previous = active.Selector
+	}
+
+	if report.Trap != nil {
+		report.Issues = append(report.Issues, "Keyboard trap: "+report.Trap.Message)
+	} else if !report.Complete && len(report.FocusOrder) >= maxTabStops {
+		report.Issues = append(report.Issues, fmt.Sprintf("Stopped after %d tab stops before reaching the end of the page", maxTabStops))
+	}
+	if report.MissingFocusStyle > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d element(s) show no visible focus indicator", report.MissingFocusStyle))
+	}
+
+	report.SkipLink = checkSkipLink(page, first)
+	if report.SkipLink.Present && !report.SkipLink.Works {
+		report.Issues = append(report.Issues, "Skip link doesn't work: "+report.SkipLink.Problem)
+	} else if !report.SkipLink.Present && len(report.FocusOrder) > 10 {
+		report.Issues = append(report.Issues, "No skip link to bypass the navigation")
+	}
+
+	// Elements that look interactive but have no place in the tab order
+	for _, control := range baseline.PseudoControls {
+		report.Unreachable = append(report.Unreachable, AccessibilityViolation{
+			Rule:     RuleKeyboard,
+			Selector: control.Selector,
+			Snippet:  control.Snippet,
+			Message:  fmt.Sprintf("Interactive %s can't be focused with the keyboard", control.Role),
+		})
+	}
+	// Focusable elements are only known to be skipped when tabbing went all the way round
+	if report.Complete {
+		for _, selector := range baseline.Focusable {
+			if _, ok := visited[selector]; !ok {
+				report.Unreachable = append(report.Unreachable, AccessibilityViolation{
+					Rule:     RuleKeyboard,
+					Selector: selector,
+					Message:  "Focusable element is never reached with Tab",
+				})
+			}
+		}
+	}
+	if len(report.Unreachable) > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d interactive element(s) can't be reached with the keyboard", len(report.Unreachable)))
+	}
+
+	return report
+}
+
+// problems counts the keyboard problems for scoring; a trap counts as much as a broken page
+func (r KeyboardReport) problems() int {
+	count := r.MissingFocusStyle + len(r.Unreachable)
+	if r.Trap != nil {
+		count += 3
+	}
+	if r.SkipLink.Present && !r.SkipLink.Works {
+		count++
+	}
+	return count
+}
+
+// checkSkipLink treats the first tab stop as a skip link when it jumps to an anchor and says so,
+// then activates it and checks that the next Tab lands in the target
+func checkSkipLink(page playwright.Page, first activeElement) SkipLinkCheck {
+	check := SkipLinkCheck{}
+	if !isSkipLink(first) {
+		return check
+	}
+	check.Present = true
+	check.Text = first.Name
+	check.Target = first.Href
+
+	link := page.Locator(first.Selector).First()
+	if err := link.Focus(); err != nil {
+		check.Problem = fmt.Sprintf("could not focus it: %v", err)
+		return check
+	}
+	if err := page.Keyboard().Press("Enter"); err != nil {
+		check.Problem = fmt.Sprintf("could not activate it: %v", err)
+		return check
+	}
+	if err := page.Keyboard().Press("Tab"); err != nil {
+		check.Problem = fmt.Sprintf("could not tab after it: %v", err)
+		return check
+	}
+
+	result, err := page.Evaluate(skipLinkTargetScript, first.Href[1:])
+	if err != nil {
+		check.Problem = err.Error()
+		return check
+	}
+	switch result {
+	case "ok":
+		check.Works = true
+	case "missing":
+		check.Problem = fmt.Sprintf("its target %s doesn't exist", first.Href)
+	default:
+		check.Problem = fmt.Sprintf("focus doesn't move to %s", first.Href)
+	}
+	return check
+}
+
+// isSkipLink reports whether a tab stop is an in-page link whose text says it skips ahead
+func isSkipLink(stop activeElement) bool {
+	return stop.Tag == "a" && len(stop.Href) > 1 && stop.Href[0] == '#' && skipLinkPattern.MatchString(stop.Name)
+}
+
+// focusScreenshot captures the focused element with a small margin so the missing indicator shows
+func focusScreenshot(page playwright.Page, active activeElement) (string, error) {
+	if active.Width <= 0 || active.Height <= 0 {
+		return "", fmt.Errorf("element has no size")
+	}
+	x := math.Max(0, active.X-focusScreenshotPadding)
+	y := math.Max(0, active.Y-focusScreenshotPadding)
+	png, err := page.Screenshot(playwright.PageScreenshotOptions{
+		Type: playwright.ScreenshotTypePng,
+		Clip: &playwright.Rect{
+			X:      x,
+			Y:      y,
+			Width:  active.X + active.Width + focusScreenshotPadding - x,
+			Height: active.Y + active.Height + focusScreenshotPadding - y,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
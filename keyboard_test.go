@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestIsSkipLink(t *testing.T) {
+	tests := []struct {
+		name string
+		stop activeElement
+		want bool
+	}{
+		{"skip link", activeElement{Tag: "a", Href: "#main", Name: "Skip to main content"}, true},
+		{"localized", activeElement{Tag: "a", Href: "#inhalt", Name: "Zum Inhalt springen"}, true},
+		{"regular link", activeElement{Tag: "a", Href: "#main", Name: "Home"}, false},
+		{"other page", activeElement{Tag: "a", Href: "/skip", Name: "Skip"}, false},
+		{"bare hash", activeElement{Tag: "a", Href: "#", Name: "Skip"}, false},
+		{"button", activeElement{Tag: "button", Name: "Skip intro"}, false},
+	}
+	for _, tt := range tests {
+		if got := isSkipLink(tt.stop); got != tt.want {
+			t.Errorf("%s: isSkipLink() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKeyboardReportProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		report KeyboardReport
+		want   int
+	}{
+		{"no problems", KeyboardReport{SkipLink: SkipLinkCheck{Present: true, Works: true}}, 0},
+		{"missing focus styles", KeyboardReport{MissingFocusStyle: 2}, 2},
+		{"unreachable controls", KeyboardReport{Unreachable: []AccessibilityViolation{{}, {}}}, 2},
+		{"trap", KeyboardReport{Trap: &FocusTrap{}}, 3},
+		{"broken skip link", KeyboardReport{SkipLink: SkipLinkCheck{Present: true}}, 1},
+		{"no skip link", KeyboardReport{}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.report.problems(); got != tt.want {
+			t.Errorf("%s: problems() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxKeywords limits how many target keywords one audit analyzes
+const maxKeywords = 20
+
+// Keyword density bounds, as a percentage of the main content words
+const (
+	minKeywordDensity = 0.5
+	maxKeywordDensity = 3.0
+)
+
+// KeywordAnalysis reports where a target keyword appears on the page
+type KeywordAnalysis struct {
+	Keyword         string       `json:"keyword"`
+	Variants        []string     `json:"variants"` // Stemmed forms found on the page, e.g. "running shoe" for "run shoes"
+	Title           KeywordMatch `json:"title"`
+	MetaDescription KeywordMatch `json:"meta_description"`
+	H1              KeywordMatch `json:"h1"`
+	FirstParagraph  KeywordMatch `json:"first_paragraph"`
+	URLSlug         bool         `json:"url_slug"`
+	Headings        int          `json:"headings"`   // H2-H6 headings containing the keyword
+	ImageAlts       int          `json:"image_alts"` // Image alt texts containing the keyword
+	Anchors         int          `json:"anchors"`    // Link anchor texts containing the keyword
+	Occurrences     int          `json:"occurrences"`
+	Density         float64      `json:"density"` // Percentage of main content words taken by the keyword
+}
+
+// KeywordMatch describes whether and where a keyword appears in a piece of text
+type KeywordMatch struct {
+	Found    bool `json:"found"`
+	Exact    bool `json:"exact"`    // The exact phrase rather than a stemmed variant
+	Position int  `json:"position"` // 1-based word position of the first match, 0 when absent
+}
+
+// keywordSources holds the page text keywords are looked up in
+type keywordSources struct {
+	Title           string   `json:"title"`
+	MetaDescription string   `json:"metaDescription"`
+	H1              []string `json:"h1"`
+	Headings        []string `json:"headings"`
+	Alts            []string `json:"alts"`
+	Anchors         []string `json:"anchors"`
+}
+
+// keywordSourcesScript collects the title, meta description, headings, image alts and anchor texts
+const keywordSourcesScript = `() => {
+	const text = (el) => (el.innerText || el.textContent || '').trim().replace(/\s+/g, ' ');
+	const description = document.querySelector('meta[name="description" i]');
+	return {
+		title: (document.title || '').trim(),
+		metaDescription: description ? (description.getAttribute('content') || '').trim() : '',
+		h1: Array.from(document.querySelectorAll('h1')).map(text).filter(Boolean),
+		headings: Array.from(document.querySelectorAll('h2, h3, h4, h5, h6')).map(text).filter(Boolean),
+		alts: Array.from(document.querySelectorAll('img[alt]')).map(img => img.getAttribute('alt').trim()).filter(Boolean),
+		anchors: Array.from(document.querySelectorAll('a[href]')).map(text).filter(Boolean)
+	};
+}`
+
+// stemSuffixes are the inflectional endings stripped by lightStem, longest first
+var stemSuffixes = map[string][]string{
+	"es": {"amente", "mente", "ciones", "ción", "idades", "idad", "ando", "iendo", "ados", "idos", "adas", "idas", "ado", "ido", "ada", "ida", "es", "os", "as", "s", "o", "a", "e"},
+	"pt": {"amente", "mente", "ções", "ção", "idades", "idade", "ando", "endo", "indo", "ados", "idos", "adas", "idas", "ado", "ido", "ada", "ida", "ões", "ães", "es", "os", "as", "s", "o", "a", "e"},
+	"fr": {"ements", "ement", "ations", "ation", "euses", "euse", "eux", "ées", "ée", "és", "er", "es", "e", "s", "x"},
+	"de": {"ungen", "ung", "heiten", "heit", "keiten", "keit", "ern", "en", "er", "es", "em", "e", "n", "s"},
+	"it": {"amente", "mente", "zioni", "zione", "ando", "endo", "ati", "iti", "ate", "ite", "ato", "ito", "ata", "ita", "i", "e", "o", "a"},
+	"nl": {"heden", "heid", "ingen", "ing", "en", "er", "e", "s"},
+}
+
+// diacriticFolds maps accented Latin letters to their base letter so "cafe" matches "café"
+var diacriticFolds = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ß", "ss", "œ", "oe", "æ", "ae",
+)
+
+// analyzeKeywords reports the presence, position and density of each target keyword
+func analyzeKeywords(page playwright.Page, targetURL string, keywords []string, content MainContent, lang string) ([]KeywordAnalysis, error) {
+	var sources keywordSources
+	if err := evaluateInto(page, keywordSourcesScript, &sources); err != nil {
+		return nil, fmt.Errorf("could not read keyword sources: %v", err)
+	}
+
+	slug := ""
+	if u, err := url.Parse(targetURL); err == nil {
+		slug = strings.NewReplacer("/", " ", "-", " ", "_", " ", ".", " ", "+", " ").Replace(u.Path)
+		if unescaped, err := url.PathUnescape(slug); err == nil {
+			slug = unescaped
+		}
+	}
+
+	firstParagraph := ""
+	if len(content.paragraphs) > 0 {
+		firstParagraph = content.paragraphs[0]
+	}
+
+	contentTokens := keywordTokens(content.Text, lang)
+
+	analyses := []KeywordAnalysis{}
+	for _, keyword := range normalizeKeywords(keywords) {
+		phrase := keywordTokens(keyword, lang)
+		if len(phrase) == 0 {
+			continue
+		}
+
+		analysis := KeywordAnalysis{
+			Keyword:         keyword,
+			Variants:        []string{},
+			Title:           matchKeyword(sources.Title, phrase, lang),
+			MetaDescription: matchKeyword(sources.MetaDescription, phrase, lang),
+			FirstParagraph:  matchKeyword(firstParagraph, phrase, lang),
+			URLSlug:         matchKeyword(slug, phrase, lang).Found,
+			Headings:        countMatching(sources.Headings, phrase, lang),
+			ImageAlts:       countMatching(sources.Alts, phrase, lang),
+			Anchors:         countMatching(sources.Anchors, phrase, lang),
+		}
+		for _, h1 := range sources.H1 {
+			if match := matchKeyword(h1, phrase, lang); match.Found {
+				analysis.H1 = match
+				break
+			}
+		}
+
+		seenVariants := map[string]bool{}
+		for _, start := range findPhrase(contentTokens, phrase) {
+			analysis.Occurrences++
+			variant := joinTokenWords(contentTokens[start : start+len(phrase)])
+			if variant != joinTokenWords(phrase) && !seenVariants[variant] {
+				seenVariants[variant] = true
+				analysis.Variants = append(analysis.Variants, variant)
+			}
+		}
+		if len(contentTokens) > 0 {
+			analysis.Density = math.Round(float64(analysis.Occurrences*len(phrase))/float64(len(contentTokens))*1000) / 10
+		}
+
+		analyses = append(analyses, analysis)
+	}
+	return analyses, nil
+}
+
+// issues lists the places a keyword is missing from and density problems
+func (k KeywordAnalysis) issues() []string {
+	issues := []string{}
+	missing := []string{}
+	if !k.Title.Found {
+		missing = append(missing, "title")
+	}
+	if !k.MetaDescription.Found {
+		missing = append(missing, "meta description")
+	}
+	if !k.H1.Found {
+		missing = append(missing, "H1")
+	}
+	if !k.FirstParagraph.Found {
+		missing = append(missing, "first paragraph")
+	}
+	if !k.URLSlug {
+		missing = append(missing, "URL")
+	}
+	if len(missing) > 0 {
+		issues = append(issues, fmt.Sprintf("Keyword %q is missing from the %s", k.Keyword, strings.Join(missing, ", ")))
+	}
+
+	if k.Occurrences == 0 {
+		issues = append(issues, fmt.Sprintf("Keyword %q does not appear in the main content", k.Keyword))
+	} else if k.Density < minKeywordDensity {
+		issues = append(issues, fmt.Sprintf("Keyword %q density is low (%.1f%%)", k.Keyword, k.Density))
+	} else if k.Density > maxKeywordDensity {
+		issues = append(issues, fmt.Sprintf("Keyword %q density is high (%.1f%%), which may look like keyword stuffing", k.Keyword, k.Density))
+	}
+	return issues
+}
+
+// keywordToken is a word of the page with the stem it is matched by
+type keywordToken struct {
+	word string
+	stem string
+}
+
+// keywordTokens splits text into lowercase, diacritic-folded words with their stems
+func keywordTokens(text, lang string) []keywordToken {
+	tokens := []keywordToken{}
+	for _, word := range splitWords(strings.ToLower(text), lang) {
+		for _, part := range strings.FieldsFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			folded := diacriticFolds.Replace(part)
+			tokens = append(tokens, keywordToken{word: part, stem: lightStem(folded, lang)})
+		}
+	}
+	return tokens
+}
+
+// matchKeyword finds the first occurrence of a keyword phrase in text, preferring an exact match
+func matchKeyword(text string, phrase []keywordToken, lang string) KeywordMatch {
+	tokens := keywordTokens(text, lang)
+	starts := findPhrase(tokens, phrase)
+	if len(starts) == 0 {
+		return KeywordMatch{}
+	}
+
+	match := KeywordMatch{Found: true, Position: starts[0] + 1}
+	for _, start := range starts {
+		if joinTokenWords(tokens[start:start+len(phrase)]) == joinTokenWords(phrase) {
+			match.Exact = true
+			match.Position = start + 1
+			break
+		}
+	}
+	return match
+}
+
+// findPhrase returns the start index of every occurrence of the phrase, compared by stem
+func findPhrase(tokens, phrase []keywordToken) []int {
+	starts := []int{}
+	for i := 0; i+len(phrase) <= len(tokens); i++ {
+		matched := true
+		for j := range phrase {
+			if tokens[i+j].stem != phrase[j].stem {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+func countMatching(texts []string, phrase []keywordToken, lang string) int {
+	count := 0
+	for _, text := range texts {
+		if matchKeyword(text, phrase, lang).Found {
+			count++
+		}
+	}
+	return count
+}
+
+func joinTokenWords(tokens []keywordToken) string {
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = token.word
+	}
+	return strings.Join(words, " ")
+}
+
+// lightStem strips a common inflectional ending so singular, plural and verb forms match
+func lightStem(word, lang string) string {
+	suffixes, ok := stemSuffixes[lang]
+	if !ok {
+		return stemEnglish(word)
+	}
+	for _, suffix := range suffixes {
+		suffix = diacriticFolds.Replace(suffix)
+		if strings.HasSuffix(word, suffix) && len([]rune(word))-len([]rune(suffix)) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// stemEnglish reduces plurals and common verb and adverb endings, e.g. "running shoes" to "run sho"
+func stemEnglish(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+
+	switch {
+	case hasAnySuffix(word, "ies", "ied") && len(word) > 4:
+		word = word[:len(word)-3] + "y"
+	case hasAnySuffix(word, "sses", "ches", "shes", "xes", "zes"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !hasAnySuffix(word, "ss", "us", "is"):
+		word = strings.TrimSuffix(word, "s")
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ness", "ment", "ed", "ly", "er", "est"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			word = strings.TrimSuffix(word, suffix)
+			// "running" and "stopped" double the final consonant
+			if n := len(word); word[n-1] == word[n-2] && !strings.ContainsRune("aeiouls", rune(word[n-1])) {
+				word = word[:n-1]
+			}
+			break
+		}
+	}
+
+	// "use", "used" and "using" share a stem once the final "e" is gone
+	if len(word) > 3 {
+		word = strings.TrimSuffix(word, "e")
+	}
+	return word
+}
+
+// normalizeKeywords trims, dedupes and drops empty keywords
+func normalizeKeywords(keywords []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, keyword := range keywords {
+		keyword = strings.Join(strings.Fields(keyword), " ")
+		if keyword == "" || seen[strings.ToLower(keyword)] {
+			continue
+		}
+		seen[strings.ToLower(keyword)] = true
+		normalized = append(normalized, keyword)
+	}
+	return normalized
+}
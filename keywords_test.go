@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStemEnglish(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"shoes", "sho"},
+		{"shoe", "sho"},
+		{"running", "run"},
+		{"runs", "run"},
+		{"stopped", "stop"},
+		{"cities", "city"},
+		{"boxes", "box"},
+		{"making", "mak"},
+		{"make", "mak"},
+		{"glass", "glass"},
+		{"status", "status"},
+		{"cat", "cat"},
+	}
+	for _, tt := range tests {
+		if got := stemEnglish(tt.word); got != tt.want {
+			t.Errorf("stemEnglish(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestLightStem(t *testing.T) {
+	tests := []struct {
+		word, lang, want string
+	}{
+		{"zapatos", "es", "zapat"},
+		{"zapato", "es", "zapat"},
+		{"cancoes", "pt", "can"},
+		{"cancao", "pt", "can"},
+		{"wohnungen", "de", "wohn"},
+		{"wohnung", "de", "wohn"},
+		{"sol", "es", "sol"},
+		{"shoes", "sv", "sho"}, // Languages without suffix rules fall back to English
+	}
+	for _, tt := range tests {
+		if got := lightStem(tt.word, tt.lang); got != tt.want {
+			t.Errorf("lightStem(%q, %q) = %q, want %q", tt.word, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestMatchKeyword(t *testing.T) {
+	phrase := keywordTokens("running shoes", "en")
+	tests := []struct {
+		text string
+		want KeywordMatch
+	}{
+		{"Best Running Shoes for 2024", KeywordMatch{Found: true, Exact: true, Position: 2}},
+		{"Shoes for running: a running shoe guide", KeywordMatch{Found: true, Position: 5}},
+		{"Shoes for running", KeywordMatch{}},
+		{"", KeywordMatch{}},
+	}
+	for _, tt := range tests {
+		if got := matchKeyword(tt.text, phrase, "en"); got != tt.want {
+			t.Errorf("matchKeyword(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeKeywords(t *testing.T) {
+	got := normalizeKeywords([]string{" running  shoes ", "Running Shoes", "", "trail"})
+	want := []string{"running shoes", "trail"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeKeywords() = %q, want %q", got, want)
+	}
+}
+
+func TestKeywordAnalysisIssues(t *testing.T) {
+	found := KeywordMatch{Found: true, Exact: true, Position: 1}
+	tests := []struct {
+		name     string
+		analysis KeywordAnalysis
+		want     []string
+	}{
+		{
+			name:     "everywhere at a good density",
+			analysis: KeywordAnalysis{Keyword: "shoes", Title: found, MetaDescription: found, H1: found, FirstParagraph: found, URLSlug: true, Occurrences: 5, Density: 1.2},
+			want:     []string{},
+		},
+		{
+			name:     "missing and stuffed",
+			analysis: KeywordAnalysis{Keyword: "shoes", Title: found, H1: found, Occurrences: 40, Density: 4.5},
+			want: []string{
+				`Keyword "shoes" is missing from the meta description, first paragraph, URL`,
+				`Keyword "shoes" density is high (4.5%), which may look like keyword stuffing`,
+			},
+		},
+		{
+			name:     "absent from the content",
+			analysis: KeywordAnalysis{Keyword: "shoes", Title: found, MetaDescription: found, H1: found, FirstParagraph: found, URLSlug: true},
+			want:     []string{`Keyword "shoes" does not appear in the main content`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.analysis.issues(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
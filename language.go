@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// minLanguageWords is the least amount of text language detection is attempted on
+const minLanguageWords = 20
+
+// minLanguageConfidence is the confidence below which the detected language is not trusted
+const minLanguageConfidence = 0.25
+
+// LanguageReport compares the detected content language with the declared one
+type LanguageReport struct {
+	Detected        string  `json:"detected"`   // ISO 639-1 code, empty when undetermined
+	Confidence      float64 `json:"confidence"` // Margin over the runner-up language, 0-1
+	HTMLLang        string  `json:"html_lang"`
+	OGLocale        string  `json:"og_locale"`
+	MatchesHTMLLang bool    `json:"matches_html_lang"`
+	MatchesOGLocale bool    `json:"matches_og_locale"`
+	AnalyzedAs      string  `json:"analyzed_as"` // Language used for readability and word segmentation
+}
+
+// languageNames are used in issue messages
+var languageNames = map[string]string{
+	"en": "English", "es": "Spanish", "pt": "Portuguese", "fr": "French", "de": "German",
+	"it": "Italian", "nl": "Dutch", "zh": "Chinese", "ja": "Japanese", "ko": "Korean",
+	"ru": "Russian", "ar": "Arabic", "he": "Hebrew", "el": "Greek", "th": "Thai", "hi": "Hindi",
+}
+
+// languageStopwords are the most frequent words of each language detected from Latin-script text
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "in", "is", "that", "it", "for", "was", "on", "are", "with", "as", "be", "this", "by", "at", "from", "have", "or", "not", "but", "you", "they", "which", "we", "an", "their", "has", "were", "been", "will", "would", "there", "what", "about", "can", "if", "more", "when", "your", "our", "all", "also", "how", "these", "than"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "del", "se", "las", "por", "un", "para", "con", "no", "una", "su", "al", "es", "lo", "como", "más", "pero", "sus", "le", "ya", "o", "este", "sí", "porque", "esta", "entre", "cuando", "muy", "sin", "sobre", "también", "me", "hasta", "hay", "donde", "quien", "desde", "todo", "nos", "durante", "todos", "uno", "les", "ni", "contra", "otros", "ese", "eso", "ante", "ellos", "esto", "antes", "algunos", "qué", "unos", "yo", "otro", "otras", "otra", "él", "tanto", "esa", "estos", "mucho", "nada", "muchos", "cual", "poco", "ella", "estar", "estas", "son", "puede", "tiene"},
+	"pt": {"de", "a", "o", "que", "e", "do", "da", "em", "um", "para", "é", "com", "não", "uma", "os", "no", "se", "na", "por", "mais", "as", "dos", "como", "mas", "foi", "ao", "ele", "das", "tem", "à", "seu", "sua", "ou", "ser", "quando", "muito", "há", "nos", "já", "está", "eu", "também", "só", "pelo", "pela", "até", "isso", "ela", "entre", "era", "depois", "sem", "mesmo", "aos", "ter", "seus", "quem", "nas", "me", "esse", "eles", "estão", "você", "tinha", "foram", "essa", "num", "nem", "suas", "meu", "às", "minha", "têm", "numa", "pelos", "elas", "havia", "seja", "qual", "será", "nós", "são", "pode", "então"},
+	"fr": {"le", "de", "un", "à", "être", "et", "en", "avoir", "que", "pour", "dans", "ce", "il", "qui", "ne", "sur", "se", "pas", "plus", "pouvoir", "par", "je", "avec", "tout", "faire", "son", "mettre", "autre", "on", "mais", "nous", "comme", "ou", "si", "leur", "y", "dire", "elle", "devoir", "avant", "deux", "même", "prendre", "aussi", "celui", "donner", "bien", "où", "fois", "vous", "la", "les", "des", "du", "est", "une", "au", "aux", "sont", "cette", "ces", "ont", "été", "très", "sa", "ses", "nos", "vos", "votre", "notre", "ils", "elles", "dont", "sans", "sous", "entre", "peut"},
+	"de": {"der", "die", "und", "in", "den", "von", "zu", "das", "mit", "sich", "des", "auf", "für", "ist", "im", "dem", "nicht", "ein", "eine", "als", "auch", "es", "an", "werden", "aus", "er", "hat", "dass", "sie", "nach", "wird", "bei", "einer", "um", "am", "sind", "noch", "wie", "einem", "über", "einen", "so", "zum", "war", "haben", "nur", "oder", "aber", "vor", "zur", "bis", "mehr", "durch", "man", "sein", "wurde", "sei", "kann", "wir", "ich", "ihr", "ihre", "wenn", "können", "sehr", "unsere", "unser", "diese", "dieser", "keine"},
+	"it": {"di", "e", "il", "la", "che", "è", "per", "un", "in", "non", "una", "sono", "del", "con", "si", "da", "le", "della", "al", "lo", "i", "gli", "dei", "ma", "come", "anche", "più", "alla", "questo", "nel", "ha", "se", "delle", "nella", "ci", "suo", "sua", "dal", "questa", "quando", "molto", "tutto", "essere", "cosa", "mi", "ho", "era", "tra", "dopo", "loro", "perché", "sul", "degli", "stato", "fare", "hanno", "sempre", "dove", "già", "ancora", "nei", "quello"},
+	"nl": {"de", "en", "van", "het", "een", "in", "is", "dat", "op", "te", "zijn", "met", "voor", "niet", "aan", "er", "die", "ook", "om", "als", "maar", "bij", "dan", "nog", "wat", "door", "over", "uit", "naar", "kan", "worden", "wordt", "ik", "je", "wij", "we", "hij", "zij", "ze", "hun", "onze", "deze", "dit", "meer", "wel", "geen", "heeft", "hebben", "werd", "zo", "tot", "al", "of", "u", "uw"},
+}
+
+// scriptLanguages detects languages written in their own script by the share of letters in it
+var scriptLanguages = []struct {
+	code  string
+	table *unicode.RangeTable
+}{
+	{"ja", unicode.Hiragana},
+	{"ja", unicode.Katakana},
+	{"ko", unicode.Hangul},
+	{"zh", unicode.Han},
+	{"ru", unicode.Cyrillic},
+	{"ar", unicode.Arabic},
+	{"he", unicode.Hebrew},
+	{"el", unicode.Greek},
+	{"th", unicode.Thai},
+	{"hi", unicode.Devanagari},
+}
+
+// languageMetaScript reads the declared language of the page
+const languageMetaScript = `() => {
+	const og = document.querySelector('meta[property="og:locale"]');
+	return {
+		htmlLang: (document.documentElement.getAttribute('lang') || '').trim(),
+		ogLocale: og ? (og.getAttribute('content') || '').trim() : ''
+	};
+}`
+
+// auditLanguage detects the language of the content and checks it against html[lang] and og:locale
+func auditLanguage(page playwright.Page, text string) (LanguageReport, []string) {
+	report := LanguageReport{}
+	issues := []string{}
+
+	var declared struct {
+		HTMLLang string `json:"htmlLang"`
+		OGLocale string `json:"ogLocale"`
+	}
+	evaluateInto(page, languageMetaScript, &declared)
+	report.HTMLLang = declared.HTMLLang
+	report.OGLocale = declared.OGLocale
+
+	report.Detected, report.Confidence = detectLanguage(text)
+	report.Confidence = round2(report.Confidence)
+
+	if report.HTMLLang != "" && strings.Contains(report.HTMLLang, "_") {
+		issues = append(issues, fmt.Sprintf("html lang %q uses an underscore, BCP 47 tags use a hyphen (e.g. %q)", report.HTMLLang, strings.ReplaceAll(report.HTMLLang, "_", "-")))
+	}
+
+	if report.Detected != "" {
+		report.MatchesHTMLLang = primaryLanguage(report.HTMLLang) == report.Detected
+		report.MatchesOGLocale = primaryLanguage(report.OGLocale) == report.Detected
+		if report.HTMLLang != "" && !report.MatchesHTMLLang {
+			issues = append(issues, fmt.Sprintf("Content appears to be %s but html lang is %q", languageName(report.Detected), report.HTMLLang))
+		}
+		if report.OGLocale != "" && !report.MatchesOGLocale {
+			issues = append(issues, fmt.Sprintf("Content appears to be %s but og:locale is %q", languageName(report.Detected), report.OGLocale))
+		}
+	}
+
+	// Analyze in the detected language, falling back to the declared one
+	switch {
+	case report.Detected != "":
+		report.AnalyzedAs = report.Detected
+	case primaryLanguage(report.HTMLLang) != "":
+		report.AnalyzedAs = primaryLanguage(report.HTMLLang)
+	default:
+		report.AnalyzedAs = "en"
+	}
+
+	return report, issues
+}
+
+// detectLanguage identifies the language of a text offline, from its script or its stopwords.
+// It returns an empty code when there is too little text or no language clearly dominates.
+func detectLanguage(text string) (string, float64) {
+	// Languages with their own script are identified by character share
+	letters := 0
+	scriptCounts := map[string]int{}
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, script := range scriptLanguages {
+			if unicode.Is(script.table, r) {
+				scriptCounts[script.code]++
+				break
+			}
+		}
+	}
+	if letters < minLanguageWords {
+		return "", 0
+	}
+	// Japanese mixes kanji with kana, so any meaningful amount of kana means Japanese
+	if scriptCounts["ja"] > 0 && float64(scriptCounts["ja"]+scriptCounts["zh"])/float64(letters) > 0.5 && float64(scriptCounts["ja"])/float64(letters) > 0.1 {
+		return "ja", float64(scriptCounts["ja"]+scriptCounts["zh"]) / float64(letters)
+	}
+	for _, script := range scriptLanguages {
+		if share := float64(scriptCounts[script.code]) / float64(letters); share > 0.5 {
+			return script.code, share
+		}
+	}
+
+	// Latin-script languages are told apart by their most frequent words
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) < minLanguageWords {
+		return "", 0
+	}
+
+	hits := map[string]int{}
+	for _, word := range words {
+		for code, stopwords := range languageStopwordSets {
+			if stopwords[word] {
+				hits[code]++
+			}
+		}
+	}
+	if len(hits) == 0 {
+		return "", 0
+	}
+
+	codes := make([]string, 0, len(hits))
+	for code := range hits {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if hits[codes[i]] != hits[codes[j]] {
+			return hits[codes[i]] > hits[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+
+	// Confidence is the margin over the runner-up, since related languages share many stopwords
+	best := codes[0]
+	confidence := 1.0
+	if len(codes) > 1 {
+		confidence = 1 - float64(hits[codes[1]])/float64(hits[best])
+	}
+	// Stopwords cover a large share of any real text, so few hits means an unsupported language
+	if confidence < minLanguageConfidence || float64(hits[best])/float64(len(words)) < 0.15 {
+		return "", confidence
+	}
+	return best, confidence
+}
+
+// languageStopwordSets indexes languageStopwords for lookups
+var languageStopwordSets = func() map[string]map[string]bool {
+	sets := map[string]map[string]bool{}
+	for code, words := range languageStopwords {
+		sets[code] = map[string]bool{}
+		for _, word := range words {
+			sets[code][word] = true
+		}
+	}
+	return sets
+}()
+
+// primaryLanguage returns the lowercase primary subtag of a language tag or locale, e.g. "pt" for "pt_BR"
+func primaryLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+func languageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// round2 rounds to two decimal places
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english",
+			text: "The quick brown fox jumps over the lazy dog and then it runs into the forest where it is safe from all of the hunters that are looking for it in the fields.",
+			want: "en",
+		},
+		{
+			name: "portuguese",
+			text: "O nosso produto é o melhor do mercado e não há nada que se compare com a qualidade que oferecemos para os nossos clientes em todo o Brasil e em Portugal.",
+			want: "pt",
+		},
+		{
+			name: "spanish",
+			text: "El perro corre por el parque y los niños juegan con la pelota mientras sus padres hablan de las cosas que pasan en la ciudad durante el fin de semana.",
+			want: "es",
+		},
+		{
+			name: "german",
+			text: "Der Hund läuft durch den Park und die Kinder spielen mit dem Ball, während ihre Eltern über die Dinge sprechen, die in der Stadt am Wochenende passieren.",
+			want: "de",
+		},
+		{
+			name: "russian by script",
+			text: "Быстрая коричневая лиса прыгает через ленивую собаку и убегает в лес, где она будет в безопасности от всех охотников.",
+			want: "ru",
+		},
+		{
+			name: "too short",
+			text: "Hello world",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := detectLanguage(tt.text); got != tt.want {
+				t.Errorf("detectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	tests := map[string]string{
+		"pt_BR":      "pt",
+		"en-US":      "en",
+		" DE ":       "de",
+		"zh-Hant-TW": "zh",
+		"":           "",
+	}
+	for tag, want := range tests {
+		if got := primaryLanguage(tag); got != want {
+			t.Errorf("primaryLanguage(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
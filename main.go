@@ -3,6 +3,7 @@ package main
 import (
 	"crypto/tls"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"math"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"os"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -19,20 +21,29 @@ import (
 
 // SEOAudit represents the complete audit result
 type SEOAudit struct {
-	URL             string              `json:"url"`
-	Timestamp       time.Time           `json:"timestamp"`
-	TechnicalSEO    TechnicalSEOScore   `json:"technical_seo"`
-	OnPageSEO       OnPageSEOScore      `json:"on_page_seo"`
-	ContentQuality  ContentQualityScore `json:"content_quality"`
-	LinkStructure   LinkStructureScore  `json:"link_structure"`
-	SchemaMarkup    SchemaMarkupScore   `json:"schema_markup"`
-	Security        SecurityScore       `json:"security"`
-	UserExperience  UserExperienceScore `json:"user_experience"`
-	WebVitals       WebVitalsScore      `json:"web_vitals"`
-	OverallScore    float64             `json:"overall_score"`
-	Grade           string              `json:"grade"`
-	Recommendations []string            `json:"recommendations"`
-	Markdown        string              `json:"markdown"`
+	URL             string                   `json:"url"`
+	Source          string                   `json:"source"`         // "url" or "html"
+	NotApplicable   []string                 `json:"not_applicable"` // Checks skipped for this kind of audit
+	Timestamp       time.Time                `json:"timestamp"`
+	TechnicalSEO    TechnicalSEOScore        `json:"technical_seo"`
+	OnPageSEO       OnPageSEOScore           `json:"on_page_seo"`
+	ContentQuality  ContentQualityScore      `json:"content_quality"`
+	LinkStructure   LinkStructureScore       `json:"link_structure"`
+	SchemaMarkup    SchemaMarkupScore        `json:"schema_markup"`
+	Security        SecurityScore            `json:"security"`
+	UserExperience  UserExperienceScore      `json:"user_experience"`
+	Accessibility   AccessibilityScore       `json:"accessibility"`
+	WebVitals       WebVitalsScore           `json:"web_vitals"`
+	Consent         ConsentResult            `json:"consent"`
+	UserAgent       string                   `json:"user_agent"`
+	Googlebot       *GooglebotComparison     `json:"googlebot_comparison,omitempty"`
+	RawHTML         *RawHTMLComparison       `json:"raw_html_comparison,omitempty"`
+	Hreflang        *HreflangReport          `json:"hreflang,omitempty"`
+	Images          *ImageOptimizationReport `json:"image_optimization,omitempty"`
+	OverallScore    float64                  `json:"overall_score"`
+	Grade           string                   `json:"grade"`
+	Recommendations []string                 `json:"recommendations"`
+	Markdown        string                   `json:"markdown"`
 }
 
 // TechnicalSEOScore holds technical SEO metrics
@@ -53,35 +64,44 @@ type TechnicalSEOScore struct {
 
 // OnPageSEOScore holds on-page SEO metrics
 type OnPageSEOScore struct {
-	Score                  float64  `json:"score"`
-	MaxScore               float64  `json:"max_score"`
-	HasTitle               bool     `json:"has_title"`
-	TitleLength            int      `json:"title_length"`
-	HasMetaDescription     bool     `json:"has_meta_description"`
-	MetaDescriptionLength  int      `json:"meta_description_length"`
-	HasH1                  bool     `json:"has_h1"`
-	H1Count                int      `json:"h1_count"`
-	H2Count                int      `json:"h2_count"`
-	HasOGTags              bool     `json:"has_og_tags"`
-	HasTwitterCard         bool     `json:"has_twitter_card"`
-	HasCanonical           bool     `json:"has_canonical"`
-	KeywordInTitle         bool     `json:"keyword_in_title"`
-	ProperHeadingHierarchy bool     `json:"proper_heading_hierarchy"`
-	Issues                 []string `json:"issues"`
+	Score                  float64           `json:"score"`
+	MaxScore               float64           `json:"max_score"`
+	HasTitle               bool              `json:"has_title"`
+	TitleLength            int               `json:"title_length"`
+	HasMetaDescription     bool              `json:"has_meta_description"`
+	MetaDescriptionLength  int               `json:"meta_description_length"`
+	SERPSnippet            SERPSnippet       `json:"serp_snippet"`
+	HasH1                  bool              `json:"has_h1"`
+	H1Count                int               `json:"h1_count"`
+	H2Count                int               `json:"h2_count"`
+	HasOGTags              bool              `json:"has_og_tags"`
+	HasTwitterCard         bool              `json:"has_twitter_card"`
+	HasCanonical           bool              `json:"has_canonical"`
+	SocialCard             SocialCardReport  `json:"social_card"`
+	KeywordInTitle         bool              `json:"keyword_in_title"`
+	ProperHeadingHierarchy bool              `json:"proper_heading_hierarchy"`
+	HeadingOutline         []Heading         `json:"heading_outline"`
+	Keywords               []KeywordAnalysis `json:"keywords"`
+	Issues                 []string          `json:"issues"`
 }
 
 // ContentQualityScore holds content quality metrics
 type ContentQualityScore struct {
-	Score            float64  `json:"score"`
-	MaxScore         float64  `json:"max_score"`
-	WordCount        int      `json:"word_count"`
-	ParagraphCount   int      `json:"paragraph_count"`
-	ImageCount       int      `json:"image_count"`
-	ImagesWithAlt    int      `json:"images_with_alt"`
-	InternalLinks    int      `json:"internal_links"`
-	ExternalLinks    int      `json:"external_links"`
-	ReadabilityScore float64  `json:"readability_score"`
-	Issues           []string `json:"issues"`
+	Score            float64           `json:"score"`
+	MaxScore         float64           `json:"max_score"`
+	WordCount        int               `json:"word_count"`
+	ParagraphCount   int               `json:"paragraph_count"`
+	ImageCount       int               `json:"image_count"`
+	ImagesWithAlt    int               `json:"images_with_alt"`
+	InternalLinks    int               `json:"internal_links"`
+	ExternalLinks    int               `json:"external_links"`
+	ReadabilityScore float64           `json:"readability_score"`
+	Readability      ReadabilityReport `json:"readability"`
+	MainContent      MainContent       `json:"main_content"`
+	Language         LanguageReport    `json:"language"`
+	Topics           TopicReport       `json:"topics"`
+	AltText          AltTextReport     `json:"alt_text"`
+	Issues           []string          `json:"issues"`
 }
 
 // LinkStructureScore holds link structure metrics
@@ -98,13 +118,19 @@ type LinkStructureScore struct {
 
 // SchemaMarkupScore holds schema markup metrics
 type SchemaMarkupScore struct {
-	Score           float64  `json:"score"`
-	MaxScore        float64  `json:"max_score"`
-	HasSchema       bool     `json:"has_schema"`
-	SchemaTypes     []string `json:"schema_types"`
-	HasOrganization bool     `json:"has_organization"`
-	HasBreadcrumb   bool     `json:"has_breadcrumb"`
-	Issues          []string `json:"issues"`
+	Score           float64                 `json:"score"`
+	MaxScore        float64                 `json:"max_score"`
+	HasSchema       bool                    `json:"has_schema"`
+	SchemaTypes     []string                `json:"schema_types"`
+	HasOrganization bool                    `json:"has_organization"`
+	HasBreadcrumb   bool                    `json:"has_breadcrumb"`
+	Syntaxes        []string                `json:"syntaxes"`
+	Entities        []SchemaEntity          `json:"entities"`
+	SyntaxErrors    []SchemaSyntaxError     `json:"syntax_errors"`
+	RichResults     []RichResultEligibility `json:"rich_results"`
+	Consistency     []ConsistencyCheck      `json:"consistency_checks"`
+	Suggestions     []SchemaSuggestion      `json:"suggestions"`
+	Issues          []string                `json:"issues"`
 }
 
 // SecurityScore holds security metrics
@@ -150,6 +176,8 @@ type WebVitalsScore struct {
 	DOMComplete      float64                `json:"dom_complete_ms"`       // DOM complete (ms)
 	TransferSize     int64                  `json:"transfer_size_bytes"`   // Total transfer size
 	ResourceCount    int                    `json:"resource_count"`        // Number of resources loaded
+	CriticalPath     *CriticalPathReport    `json:"critical_path,omitempty"`
+	Coverage         *CoverageReport        `json:"coverage,omitempty"` // Unused JavaScript and CSS
 	Issues           []string               `json:"issues"`
 }
 
@@ -188,24 +216,38 @@ func (a *SEOAuditor) Close() error {
 }
 
 // AuditWebsite performs a complete SEO audit
-func (a *SEOAuditor) AuditWebsite(targetURL string) (*SEOAudit, error) {
+func (a *SEOAuditor) AuditWebsite(targetURL string, opts AuditOptions) (*SEOAudit, error) {
 	audit := &SEOAudit{
-		URL:       targetURL,
-		Timestamp: time.Now(),
+		URL:           targetURL,
+		Source:        AuditSourceURL,
+		Timestamp:     time.Now(),
+		NotApplicable: []string{},
+	}
+
+	// Create an isolated context carrying credentials, headers, cookies and login session
+	context, err := a.newBrowserContext(targetURL, opts)
+	if err != nil {
+		return nil, err
 	}
+	defer context.Close()
 
 	// Create a new page
-	page, err := a.browser.NewPage()
+	page, err := context.NewPage()
 	if err != nil {
 		return nil, fmt.Errorf("could not create page: %v", err)
 	}
 	defer page.Close()
 
+	// Record the request waterfall from the first request on
+	network := startNetworkRecorder(page)
+	// Coverage has to be on before the first script runs; it stays off in browsers without CDP
+	coverage, _ := startCoverage(page)
+
 	// Measure page load time
 	startTime := time.Now()
 
 	// Navigate to the page
-	_, err = page.Goto(targetURL, playwright.PageGotoOptions{
+	response, err := page.Goto(targetURL, playwright.PageGotoOptions{
 		WaitUntil: playwright.WaitUntilStateNetworkidle,
 		Timeout:   playwright.Float(30000),
 	})
@@ -215,27 +257,121 @@ func (a *SEOAuditor) AuditWebsite(targetURL string) (*SEOAudit, error) {
 
 	loadTime := time.Since(startTime).Milliseconds()
 
+	// Deal with the cookie consent banner before measuring the page
+	audit.Consent = a.handleConsent(page, targetURL, opts)
+
+	if userAgent, err := page.Evaluate("() => navigator.userAgent"); err == nil {
+		audit.UserAgent, _ = userAgent.(string)
+	}
+
+	// Snapshot the SEO-critical elements before the checks start interacting with the page
+	snapshot, _ := takeSnapshot(page)
+	var headers map[string]string
+	if response != nil {
+		headers = response.Headers()
+		snapshot.StatusCode = response.Status()
+		snapshot.RobotsHeader = headers["x-robots-tag"]
+	}
+
 	// Run all audits
-	audit.TechnicalSEO = a.auditTechnicalSEO(page, targetURL, float64(loadTime))
-	audit.OnPageSEO = a.auditOnPageSEO(page)
-	audit.ContentQuality = a.auditContentQuality(page, targetURL)
-	audit.LinkStructure = a.auditLinkStructure(page, targetURL)
+	a.runAudits(page, audit, auditTarget{
+		url:           targetURL,
+		opts:          opts,
+		loadTime:      float64(loadTime),
+		headers:       headers,
+		networkChecks: true,
+		network:       network,
+		coverage:      coverage,
+	})
+
+	rawComparison, err := a.compareRawHTML(targetURL, opts, audit.UserAgent, snapshot)
+	if err != nil {
+		rawComparison = &RawHTMLComparison{
+			Elements:     []ElementComparison{},
+			RenderedOnly: []string{},
+			Issues:       []string{fmt.Sprintf("Raw HTML comparison failed: %v", err)},
+		}
+	}
+	audit.RawHTML = rawComparison
+
+	if opts.CompareWithGooglebot {
+		comparison, err := a.compareWithGooglebot(targetURL, opts, snapshot)
+		if err != nil {
+			comparison = &GooglebotComparison{
+				UserAgent:   googlebotSmartphoneUserAgent,
+				Differences: []RenderDifference{},
+				Issues:      []string{fmt.Sprintf("Googlebot comparison failed: %v", err)},
+			}
+		}
+		audit.Googlebot = comparison
+	}
+
+	a.finishAudit(audit)
+
+	return audit, nil
+}
+
+// auditTarget describes the audited page and how it was loaded
+type auditTarget struct {
+	url           string
+	opts          AuditOptions
+	loadTime      float64
+	headers       map[string]string // Response headers with lower-case names, nil when unknown
+	networkChecks bool              // False when the page was supplied as HTML and nothing can be fetched
+	network       *networkRecorder  // Requests made while loading the page, nil when they weren't recorded
+	coverage      *coverageRecorder // JavaScript and CSS coverage since navigation, nil when not recorded
+}
+
+// runAudits runs the DOM-based checks shared by URL and HTML audits
+func (a *SEOAuditor) runAudits(page playwright.Page, audit *SEOAudit, target auditTarget) {
+	audit.TechnicalSEO = a.auditTechnicalSEO(page, target.url, target.loadTime, target.opts, target.networkChecks)
+	// Content quality runs first because keyword analysis needs the main content
+	audit.ContentQuality = a.auditContentQuality(page, target.url)
+	audit.OnPageSEO = a.auditOnPageSEO(page, target, audit.ContentQuality)
+	audit.LinkStructure = a.auditLinkStructure(page, target.url)
 	audit.SchemaMarkup = a.auditSchemaMarkup(page)
-	audit.Security = a.auditSecurity(targetURL, page)
+	audit.Security = a.auditSecurity(target.url, page, target.headers)
 	audit.UserExperience = a.auditUserExperience(page)
-	audit.WebVitals = a.auditWebVitals(page)
+	audit.Hreflang = a.auditHreflang(page, target, audit.UserAgent)
+	audit.Images = a.auditImageOptimization(page, target)
+	if target.networkChecks {
+		audit.WebVitals = a.auditWebVitals(page)
+		if target.network != nil {
+			if criticalPath := analyzeCriticalPath(page, target.network); criticalPath != nil {
+				audit.WebVitals.CriticalPath = criticalPath
+				audit.WebVitals.Issues = append(audit.WebVitals.Issues, criticalPath.Issues...)
+			}
+		}
+		if target.coverage != nil {
+			if coverage, err := analyzeCoverage(page, target.coverage); err == nil {
+				audit.WebVitals.Coverage = coverage
+				audit.WebVitals.Issues = append(audit.WebVitals.Issues, coverage.Issues...)
+			}
+		}
+	} else {
+		audit.WebVitals = WebVitalsScore{Issues: []string{}}
+	}
+	// Accessibility tabs through the page, so it runs once nothing else reads the page
+	audit.Accessibility = a.auditAccessibility(page)
+}
 
-	// Calculate overall score
+// finishAudit calculates the overall score and builds the recommendations and report
+func (a *SEOAuditor) finishAudit(audit *SEOAudit) {
 	audit.OverallScore = a.calculateOverallScore(audit)
 	audit.Grade = a.calculateGrade(audit.OverallScore)
 	audit.Recommendations = a.generateRecommendations(audit)
 	audit.Markdown = a.generateMarkdown(audit)
+}
 
-	return audit, nil
+// hasWebScheme reports whether a URL is served over HTTP or HTTPS. HTML audits without a base
+// URL use about:blank, where HTTPS and mixed content checks don't apply.
+func hasWebScheme(rawURL string) bool {
+	parsedURL, err := url.Parse(rawURL)
+	return err == nil && (parsedURL.Scheme == "http" || parsedURL.Scheme == "https")
 }
 
 // auditTechnicalSEO performs technical SEO checks
-func (a *SEOAuditor) auditTechnicalSEO(page playwright.Page, targetURL string, loadTime float64) TechnicalSEOScore {
+func (a *SEOAuditor) auditTechnicalSEO(page playwright.Page, targetURL string, loadTime float64, opts AuditOptions, networkChecks bool) TechnicalSEOScore {
 	score := TechnicalSEOScore{
 		MaxScore: 100,
 		LoadTime: loadTime,
@@ -249,8 +385,10 @@ func (a *SEOAuditor) auditTechnicalSEO(page playwright.Page, targetURL string, l
 	score.IsHTTPS = parsedURL.Scheme == "https"
 	if score.IsHTTPS {
 		score.Score += 15
-	} else {
+	} else if hasWebScheme(targetURL) {
 		score.Issues = append(score.Issues, "Site is not using HTTPS")
+	} else {
+		score.MaxScore -= 15
 	}
 
 	// Check viewport meta tag
@@ -263,34 +401,39 @@ func (a *SEOAuditor) auditTechnicalSEO(page playwright.Page, targetURL string, l
 		score.Issues = append(score.Issues, "Missing viewport meta tag")
 	}
 
-	// Check robots.txt
-	score.HasRobotsTxt = a.checkURLExists(baseURL + "/robots.txt")
-	if score.HasRobotsTxt {
-		score.Score += 10
-	} else {
-		score.Issues = append(score.Issues, "robots.txt not found")
-	}
+	if networkChecks {
+		// Check robots.txt
+		score.HasRobotsTxt = a.checkURLExists(baseURL+"/robots.txt", targetURL, opts)
+		if score.HasRobotsTxt {
+			score.Score += 10
+		} else {
+			score.Issues = append(score.Issues, "robots.txt not found")
+		}
 
-	// Check sitemap
-	score.HasSitemap = a.checkURLExists(baseURL + "/sitemap.xml")
-	if score.HasSitemap {
-		score.Score += 10
-	} else {
-		score.Issues = append(score.Issues, "sitemap.xml not found")
-	}
+		// Check sitemap
+		score.HasSitemap = a.checkURLExists(baseURL+"/sitemap.xml", targetURL, opts)
+		if score.HasSitemap {
+			score.Score += 10
+		} else {
+			score.Issues = append(score.Issues, "sitemap.xml not found")
+		}
 
-	// Check page load time
-	if loadTime < 2000 {
-		score.Score += 20
-	} else if loadTime < 3000 {
-		score.Score += 15
-		score.Issues = append(score.Issues, "Page load time is moderate (2-3 seconds)")
-	} else if loadTime < 5000 {
-		score.Score += 10
-		score.Issues = append(score.Issues, "Page load time is slow (3-5 seconds)")
+		// Check page load time
+		if loadTime < 2000 {
+			score.Score += 20
+		} else if loadTime < 3000 {
+			score.Score += 15
+			score.Issues = append(score.Issues, "Page load time is moderate (2-3 seconds)")
+		} else if loadTime < 5000 {
+			score.Score += 10
+			score.Issues = append(score.Issues, "Page load time is slow (3-5 seconds)")
+		} else {
+			score.Score += 5
+			score.Issues = append(score.Issues, fmt.Sprintf("Page load time is very slow (%.2f seconds)", float64(loadTime)/1000))
+		}
 	} else {
-		score.Score += 5
-		score.Issues = append(score.Issues, fmt.Sprintf("Page load time is very slow (%.2f seconds)", float64(loadTime)/1000))
+		// robots.txt, sitemap and load time can't be measured without the network
+		score.MaxScore -= 40
 	}
 
 	// Estimate page size
@@ -324,54 +467,70 @@ func (a *SEOAuditor) auditTechnicalSEO(page playwright.Page, targetURL string, l
 	}
 
 	// Check HTTP status (if we got here, it's likely 200)
-	score.HTTPStatusCode = 200
-	score.Score += 5
+	if networkChecks {
+		score.HTTPStatusCode = 200
+		score.Score += 5
+	} else {
+		score.MaxScore -= 5
+	}
 
 	return score
 }
 
 // auditOnPageSEO performs on-page SEO checks
-func (a *SEOAuditor) auditOnPageSEO(page playwright.Page) OnPageSEOScore {
+func (a *SEOAuditor) auditOnPageSEO(page playwright.Page, target auditTarget, content ContentQualityScore) OnPageSEOScore {
 	score := OnPageSEOScore{
 		MaxScore: 100,
+		Keywords: []KeywordAnalysis{},
 		Issues:   []string{},
 	}
 
 	// Check title tag
 	title, err := page.Title()
 	score.HasTitle = err == nil && title != ""
-	score.TitleLength = len(title)
+	score.TitleLength = utf8.RuneCountInString(title)
+
+	metaDesc, _ := page.Locator("meta[name='description']").GetAttribute("content")
+	score.HasMetaDescription = metaDesc != ""
+	score.MetaDescriptionLength = utf8.RuneCountInString(metaDesc)
+
+	// Lengths are judged in pixels as search results render them, or in characters when measuring fails
+	snippet, snippetErr := simulateSERPSnippet(page, target.url, title, metaDesc)
+	score.SERPSnippet = snippet
+	measured := snippetErr == nil
 
 	if score.HasTitle {
 		score.Score += 15
-		if score.TitleLength >= 50 && score.TitleLength <= 60 {
-			score.Score += 10
-		} else if score.TitleLength < 50 {
-			score.Issues = append(score.Issues, "Title tag is too short (< 50 characters)")
+		if measured && snippet.Desktop.TitleTruncated {
+			score.Issues = append(score.Issues, fmt.Sprintf("Title will be truncated in search results (%.0fpx, limit %dpx)", snippet.TitleWidth, serpTitleLimit))
 			score.Score += 5
-		} else if score.TitleLength > 60 {
+		} else if !measured && score.TitleLength > 60 {
 			score.Issues = append(score.Issues, "Title tag is too long (> 60 characters)")
 			score.Score += 5
+		} else if score.TitleLength < minTitleLength {
+			score.Issues = append(score.Issues, fmt.Sprintf("Title tag is too short (< %d characters)", minTitleLength))
+			score.Score += 5
+		} else {
+			score.Score += 10
 		}
 	} else {
 		score.Issues = append(score.Issues, "Missing title tag")
 	}
 
 	// Check meta description
-	metaDesc, _ := page.Locator("meta[name='description']").GetAttribute("content")
-	score.HasMetaDescription = metaDesc != ""
-	score.MetaDescriptionLength = len(metaDesc)
-
 	if score.HasMetaDescription {
 		score.Score += 15
-		if score.MetaDescriptionLength >= 150 && score.MetaDescriptionLength <= 160 {
-			score.Score += 10
-		} else if score.MetaDescriptionLength < 150 {
-			score.Issues = append(score.Issues, "Meta description is too short (< 150 characters)")
+		if measured && snippet.Desktop.DescriptionTruncated {
+			score.Issues = append(score.Issues, fmt.Sprintf("Meta description will be truncated in desktop search results (%.0fpx, limit %dpx)", snippet.DescriptionWidth, serpDesktopDescription))
 			score.Score += 5
-		} else if score.MetaDescriptionLength > 160 {
+		} else if !measured && score.MetaDescriptionLength > 160 {
 			score.Issues = append(score.Issues, "Meta description is too long (> 160 characters)")
 			score.Score += 5
+		} else if score.MetaDescriptionLength < minMetaDescriptionLength {
+			score.Issues = append(score.Issues, fmt.Sprintf("Meta description is too short (< %d characters)", minMetaDescriptionLength))
+			score.Score += 5
+		} else {
+			score.Score += 10
 		}
 	} else {
 		score.Issues = append(score.Issues, "Missing meta description")
@@ -401,12 +560,15 @@ func (a *SEOAuditor) auditOnPageSEO(page playwright.Page) OnPageSEOScore {
 	}
 
 	// Check heading hierarchy
-	score.ProperHeadingHierarchy = a.checkHeadingHierarchy(page)
+	outline, properHierarchy, headingIssues := auditHeadingOutline(page)
+	score.HeadingOutline = outline
+	score.ProperHeadingHierarchy = properHierarchy
 	if score.ProperHeadingHierarchy {
 		score.Score += 10
 	} else {
 		score.Issues = append(score.Issues, "Improper heading hierarchy")
 	}
+	score.Issues = append(score.Issues, headingIssues...)
 
 	// Check Open Graph tags
 	ogTitle, _ := page.Locator("meta[property='og:title']").Count()
@@ -430,6 +592,11 @@ func (a *SEOAuditor) auditOnPageSEO(page playwright.Page) OnPageSEOScore {
 		score.Issues = append(score.Issues, "Missing Twitter Card tags")
 	}
 
+	// Validate the values pages are shared with, not just the presence of the tags
+	socialCard, socialIssues := a.auditSocialCard(page, target)
+	score.SocialCard = socialCard
+	score.Issues = append(score.Issues, socialIssues...)
+
 	// Check canonical tag
 	canonical, _ := page.Locator("link[rel='canonical']").Count()
 	score.HasCanonical = canonical > 0
@@ -440,10 +607,20 @@ func (a *SEOAuditor) auditOnPageSEO(page playwright.Page) OnPageSEOScore {
 		score.Issues = append(score.Issues, "Missing canonical tag")
 	}
 
-	// Check if keyword in title (basic check)
-	if score.HasTitle && len(title) > 0 {
-		score.KeywordInTitle = true
-		score.Score += 5
+	// Check target keywords, which can only be scored when the request names some
+	if len(normalizeKeywords(target.opts.Keywords)) == 0 {
+		score.MaxScore -= 5
+	} else if analyses, err := analyzeKeywords(page, target.url, target.opts.Keywords, content.MainContent, content.Language.AnalyzedAs); err == nil {
+		score.Keywords = analyses
+		for _, analysis := range analyses {
+			if analysis.Title.Found {
+				score.KeywordInTitle = true
+			}
+			score.Issues = append(score.Issues, analysis.issues()...)
+		}
+		if score.KeywordInTitle {
+			score.Score += 5
+		}
 	}
 
 	return score
@@ -456,12 +633,17 @@ func (a *SEOAuditor) auditContentQuality(page playwright.Page, targetURL string)
 		Issues:   []string{},
 	}
 
-	// Get page content
+	// Separate the main content from navigation, footers and banners
 	bodyText, _ := page.Locator("body").InnerText()
+	score.MainContent = extractMainContent(page, bodyText)
+
+	// Detect the content language so words are segmented the way the language is written
+	language, languageIssues := auditLanguage(page, score.MainContent.Text)
+	score.Language = language
 
 	// Count words
-	words := strings.Fields(bodyText)
-	score.WordCount = len(words)
+	score.WordCount = len(splitWords(score.MainContent.Text, language.AnalyzedAs))
+	score.MainContent.WordCount = score.WordCount
 
 	if score.WordCount >= 1000 {
 		score.Score += 25
@@ -476,47 +658,82 @@ func (a *SEOAuditor) auditContentQuality(page playwright.Page, targetURL string)
 		score.Issues = append(score.Issues, fmt.Sprintf("Content is too thin (%d words)", score.WordCount))
 	}
 
+	// Boilerplate-heavy pages hide thin content behind big menus and footers
+	if score.MainContent.BoilerplateShare > 0.6 {
+		score.Issues = append(score.Issues, fmt.Sprintf("Boilerplate makes up %.0f%% of the page text", score.MainContent.BoilerplateShare*100))
+	}
+	// Markup, scripts and styles make single-digit ratios normal, so only near-empty pages are flagged
+	if score.MainContent.TextToHTMLRatio < minTextToHTMLRatio {
+		score.Issues = append(score.Issues, fmt.Sprintf("Low text-to-HTML ratio (%.1f%%)", score.MainContent.TextToHTMLRatio))
+	}
+
 	// Count paragraphs
-	pCount, _ := page.Locator("p").Count()
+	pCount, _ := page.Locator(score.MainContent.Selector + " p").Count()
 	score.ParagraphCount = pCount
 	if pCount >= 5 {
 		score.Score += 10
 	}
 
-	// Count images and alt attributes
-	imageCount, _ := page.Locator("img").Count()
-	score.ImageCount = imageCount
+	// Classify alt text in one DOM pass; decorative images correctly marked alt="" count as described
+	altText, err := auditAltText(page, score.MainContent.Selector, language.AnalyzedAs)
+	if err != nil {
+		score.Issues = append(score.Issues, fmt.Sprintf("Unable to analyze image alt text: %v", err))
+	}
+	score.AltText = altText
 
-	imagesWithAlt := 0
-	if imageCount > 0 {
-		for i := 0; i < imageCount; i++ {
-			alt, _ := page.Locator("img").Nth(i).GetAttribute("alt")
-			if alt != "" {
-				imagesWithAlt++
-			}
+	// The score only counts main content images; the report still lists every image
+	for _, img := range altText.Images {
+		if !img.InMainContent {
+			continue
 		}
-		score.ImagesWithAlt = imagesWithAlt
+		score.ImageCount++
+		if img.acceptable() {
+			score.ImagesWithAlt++
+		}
+	}
+
+	if score.ImageCount > 0 {
 
-		altPercentage := float64(imagesWithAlt) / float64(imageCount) * 100
+		altPercentage := float64(score.ImagesWithAlt) / float64(score.ImageCount) * 100
 		if altPercentage == 100 {
 			score.Score += 20
 		} else if altPercentage >= 75 {
 			score.Score += 15
-			score.Issues = append(score.Issues, fmt.Sprintf("%.0f%% of images have alt text", altPercentage))
+			score.Issues = append(score.Issues, fmt.Sprintf("%.0f%% of images have good alt text", altPercentage))
 		} else if altPercentage >= 50 {
 			score.Score += 10
-			score.Issues = append(score.Issues, fmt.Sprintf("Only %.0f%% of images have alt text", altPercentage))
+			score.Issues = append(score.Issues, fmt.Sprintf("Only %.0f%% of images have good alt text", altPercentage))
 		} else {
 			score.Score += 5
-			score.Issues = append(score.Issues, fmt.Sprintf("Most images missing alt text (%.0f%%)", altPercentage))
+			score.Issues = append(score.Issues, fmt.Sprintf("Most images lack good alt text (%.0f%%)", altPercentage))
+		}
+
+		for _, status := range []struct {
+			name    string
+			message string
+		}{
+			{AltStatusMissing, "%d image(s) have no alt attribute"},
+			{AltStatusEmpty, "%d content image(s) have an empty alt"},
+			{AltStatusFilename, "%d image(s) use a file name as alt text"},
+			{AltStatusDuplicate, "%d image(s) share alt text with different images"},
+			{AltStatusKeywordStuffed, "%d image(s) have keyword-stuffed alt text"},
+			{AltStatusTooLong, "%d image(s) have alt text over %d characters"},
+		} {
+			if count := altText.Counts[status.name]; count > 0 {
+				if status.name == AltStatusTooLong {
+					score.Issues = append(score.Issues, fmt.Sprintf(status.message, count, maxAltTextLength))
+				} else {
+					score.Issues = append(score.Issues, fmt.Sprintf(status.message, count))
+				}
+			}
 		}
 	} else {
 		score.Score += 10 // No images is okay
 	}
 
-	// Count internal and external links
+	// Count internal and external links of the main content
 	parsedURL, _ := url.Parse(targetURL)
-	links, _ := page.Locator("a[href]").All()
+	links, _ := page.Locator(score.MainContent.Selector + " a[href]").All()
 
 	for _, link := range links {
 		href, _ := link.GetAttribute("href")
@@ -551,23 +768,28 @@ func (a *SEOAuditor) auditContentQuality(page playwright.Page, targetURL string)
 		score.Issues = append(score.Issues, "No external links to authoritative sources")
 	}
 
-	// Calculate basic readability score (Flesch Reading Ease approximation)
-	if score.WordCount > 0 {
-		sentences := strings.Count(bodyText, ".") + strings.Count(bodyText, "!") + strings.Count(bodyText, "?")
-		if sentences == 0 {
-			sentences = 1
-		}
-		syllables := float64(score.WordCount) * 1.5 // Rough approximation
-		score.ReadabilityScore = 206.835 - 1.015*(float64(score.WordCount)/float64(sentences)) - 84.6*(float64(syllables)/float64(score.WordCount))
+	// Measure readability of the main content with the formula for its language
+	score.Issues = append(score.Issues, languageIssues...)
+	score.Readability = analyzeReadability(score.MainContent.paragraphs, language.AnalyzedAs)
+	if score.Readability.Words > 0 {
+		score.ReadabilityScore = score.Readability.ReadingEase
 
-		if score.ReadabilityScore >= 60 {
+		if score.Readability.Formula == "" {
+			// No readability formula exists for this language, so it isn't held against the page
+			score.Score += 10
+		} else if score.Readability.readsComfortably() {
 			score.Score += 10
 		} else {
-			score.Issues = append(score.Issues, "Content may be difficult to read")
+			score.Issues = append(score.Issues, fmt.Sprintf("Content may be difficult to read (%s %.1f)", score.Readability.Formula, score.Readability.ReadingEase))
 			score.Score += 5
 		}
 	}
 
+	// Check that the title and H1 are about what the content is about
+	topics, topicIssues := extractTopics(page, score.MainContent, language.AnalyzedAs)
+	score.Topics = topics
+	score.Issues = append(score.Issues, topicIssues...)
+
 	return score
 }
 
@@ -654,7 +876,7 @@ func (a *SEOAuditor) auditLinkStructure(page playwright.Page, targetURL string)
 	}
 
 	// Check for breadcrumbs
-	breadcrumbs, _ := page.Locator("[itemtype*='BreadcrumbList'], nav[aria-label*='readcrumb'], .breadcrumb").Count()
+	breadcrumbs, _ := page.Locator(breadcrumbSelector).Count()
 	score.HasBreadcrumbs = breadcrumbs > 0
 
 	if score.HasBreadcrumbs {
@@ -674,43 +896,88 @@ func (a *SEOAuditor) auditLinkStructure(page playwright.Page, targetURL string)
 // auditSchemaMarkup performs schema markup checks
 func (a *SEOAuditor) auditSchemaMarkup(page playwright.Page) SchemaMarkupScore {
 	score := SchemaMarkupScore{
-		MaxScore:    100,
-		Issues:      []string{},
-		SchemaTypes: []string{},
+		MaxScore:     100,
+		Issues:       []string{},
+		SchemaTypes:  []string{},
+		Syntaxes:     []string{},
+		Entities:     []SchemaEntity{},
+		SyntaxErrors: []SchemaSyntaxError{},
+		RichResults:  []RichResultEligibility{},
+		Consistency:  []ConsistencyCheck{},
+		Suggestions:  []SchemaSuggestion{},
+	}
+
+	// Extract JSON-LD, microdata and RDFa into one entity model and validate it against schema.org rules
+	data := newStructuredData()
+	jsonLdBlocks := extractJSONLD(page, data)
+	microdataItems := extractMicrodata(page, data)
+	rdfaItems := extractRDFa(page, data)
+	data.validate()
+
+	for _, syntaxErr := range data.errors {
+		score.SyntaxErrors = append(score.SyntaxErrors, syntaxErr)
+		score.Issues = append(score.Issues, syntaxErr.String())
+	}
+
+	seenTypes := map[string]bool{}
+	seenSyntaxes := map[string]bool{}
+	invalidEntities := 0
+	for _, entity := range data.entities {
+		score.Entities = append(score.Entities, *entity)
+		if !seenSyntaxes[entity.Syntax] {
+			seenSyntaxes[entity.Syntax] = true
+			score.Syntaxes = append(score.Syntaxes, entity.Syntax)
+		}
+		for _, t := range entity.Types {
+			if !seenTypes[t] {
+				seenTypes[t] = true
+				score.SchemaTypes = append(score.SchemaTypes, t)
+			}
+			if schemaTypeIs(t, "Organization") {
+				score.HasOrganization = true
+			}
+			if t == "BreadcrumbList" {
+				score.HasBreadcrumb = true
+			}
+		}
+		if !entity.Valid {
+			invalidEntities++
+			score.Issues = append(score.Issues, fmt.Sprintf("%s is missing required properties: %s", entity.Path, strings.Join(entity.MissingRequired, ", ")))
+		}
 	}
 
-	// Check for JSON-LD schema
-	jsonLdScripts, _ := page.Locator("script[type='application/ld+json']").All()
-	score.HasSchema = len(jsonLdScripts) > 0
-
-	if score.HasSchema {
-		score.Score += 30
+	for _, id := range data.unresolvedReferences() {
+		score.Issues = append(score.Issues, fmt.Sprintf("Structured data references %s but no item with that @id exists on the page", id))
+	}
 
-		// Parse each JSON-LD script
-		for _, script := range jsonLdScripts {
-			content, _ := script.InnerText()
+	score.RichResults = data.richResults()
+	for _, result := range score.RichResults {
+		if !result.Eligible {
+			score.Issues = append(score.Issues, fmt.Sprintf("%s is not eligible for %s rich results, missing: %s", result.Item, result.Feature, strings.Join(result.MissingRequired, ", ")))
+		}
+	}
 
-			// Check for common schema types
-			if strings.Contains(content, "\"@type\"") {
-				if strings.Contains(content, "Organization") {
-					score.HasOrganization = true
-					score.SchemaTypes = append(score.SchemaTypes, "Organization")
-				}
-				if strings.Contains(content, "BreadcrumbList") {
-					score.HasBreadcrumb = true
-					score.SchemaTypes = append(score.SchemaTypes, "BreadcrumbList")
-				}
-				if strings.Contains(content, "Article") {
-					score.SchemaTypes = append(score.SchemaTypes, "Article")
-				}
-				if strings.Contains(content, "Product") {
-					score.SchemaTypes = append(score.SchemaTypes, "Product")
-				}
-				if strings.Contains(content, "LocalBusiness") {
-					score.SchemaTypes = append(score.SchemaTypes, "LocalBusiness")
-				}
-			}
+	// Structured data that contradicts the visible page is a manual action risk
+	inconsistencies := 0
+	if checks, err := data.checkContentConsistency(page); err == nil {
+		score.Consistency = checks
+	}
+	for _, check := range score.Consistency {
+		if check.Consistent {
+			continue
+		}
+		inconsistencies++
+		if check.Visible == "" {
+			score.Issues = append(score.Issues, fmt.Sprintf("%s %s %q is not shown on the page", check.Item, check.Check, check.Structured))
+		} else {
+			score.Issues = append(score.Issues, fmt.Sprintf("%s %s %q does not match the visible content %q", check.Item, check.Check, check.Structured, check.Visible))
 		}
+	}
+
+	score.HasSchema = len(data.entities) > 0
+
+	if score.HasSchema {
+		score.Score += 30
 
 		// Score based on schema types
 		schemaTypeCount := len(score.SchemaTypes)
@@ -736,23 +1003,29 @@ func (a *SEOAuditor) auditSchemaMarkup(page playwright.Page) SchemaMarkupScore {
 		} else {
 			score.Issues = append(score.Issues, "Missing BreadcrumbList schema")
 		}
+
+		// Penalty for invalid or inconsistent entities and blocks that don't parse
+		score.Score = math.Max(0, score.Score-float64(5*(invalidEntities+inconsistencies))-float64(10*len(data.errors)))
+
+		if !seenSyntaxes[SyntaxJSONLD] {
+			score.Issues = append(score.Issues, "Using microdata or RDFa instead of JSON-LD (JSON-LD is preferred)")
+		}
+	} else if jsonLdBlocks+microdataItems+rdfaItems > 0 {
+		score.Issues = append(score.Issues, "Structured data found but no valid typed items could be extracted")
 	} else {
 		score.Issues = append(score.Issues, "No structured data (schema markup) found")
 	}
 
-	// Also check for microdata
-	itemscope, _ := page.Locator("[itemscope]").Count()
-	if itemscope > 0 && !score.HasSchema {
-		score.HasSchema = true
-		score.Score += 20
-		score.Issues = append(score.Issues, "Using microdata instead of JSON-LD (JSON-LD is preferred)")
+	// Propose ready-to-paste JSON-LD for whatever is missing
+	if suggestions, err := suggestSchemaMarkup(page, score); err == nil {
+		score.Suggestions = suggestions
 	}
 
 	return score
 }
 
 // auditSecurity performs security checks
-func (a *SEOAuditor) auditSecurity(targetURL string, page playwright.Page) SecurityScore {
+func (a *SEOAuditor) auditSecurity(targetURL string, page playwright.Page, headers map[string]string) SecurityScore {
 	score := SecurityScore{
 		MaxScore: 100,
 		Issues:   []string{},
@@ -766,12 +1039,15 @@ func (a *SEOAuditor) auditSecurity(targetURL string, page playwright.Page) Secur
 
 	if score.IsHTTPS {
 		score.Score += 40
-	} else {
+	} else if hasWebScheme(targetURL) {
 		score.Issues = append(score.Issues, "Site is not using HTTPS")
 	}
 
 	// Check for mixed content
-	if score.IsHTTPS {
+	if !hasWebScheme(targetURL) {
+		// HTTPS and mixed content can't be judged without knowing where the page is served from
+		score.MaxScore -= 70
+	} else if score.IsHTTPS {
 		// Check for HTTP resources
 		httpImages, _ := page.Locator("img[src^='http://']").Count()
 		httpScripts, _ := page.Locator("script[src^='http://']").Count()
@@ -789,14 +1065,24 @@ func (a *SEOAuditor) auditSecurity(targetURL string, page playwright.Page) Secur
 		score.Score += 15
 	}
 
-	// Check for security headers (would require HTTP response inspection)
-	// This is a simplified check
-	score.HasSecurityHeaders = false // Placeholder
-	if score.HasSecurityHeaders {
-		score.Score += 30
-	} else {
+	// Check for security headers on the document response
+	if headers == nil {
 		score.Issues = append(score.Issues, "Unable to verify security headers")
 		score.Score += 15
+	} else {
+		missing := []string{}
+		for _, header := range []string{"strict-transport-security", "content-security-policy", "x-content-type-options", "x-frame-options", "referrer-policy"} {
+			if headers[header] == "" {
+				missing = append(missing, header)
+			}
+		}
+		score.HasSecurityHeaders = len(missing) == 0
+		if score.HasSecurityHeaders {
+			score.Score += 30
+		} else {
+			score.Issues = append(score.Issues, fmt.Sprintf("Missing security headers: %s", strings.Join(missing, ", ")))
+			score.Score += 15
+		}
 	}
 
 	return score
@@ -1162,35 +1448,7 @@ func calculateWebVitalsScore(wv *WebVitalsScore) float64 {
 
 // Helper functions
 
-func (a *SEOAuditor) checkHeadingHierarchy(page playwright.Page) bool {
-	h1, _ := page.Locator("h1").Count()
-	h2, _ := page.Locator("h2").Count()
-	h3, _ := page.Locator("h3").Count()
-	h4, _ := page.Locator("h4").Count()
-	h5, _ := page.Locator("h5").Count()
-	h6, _ := page.Locator("h6").Count()
-
-	// Basic check: should have H1, and if H3 exists, H2 should exist, etc.
-	if h1 == 0 {
-		return false
-	}
-	if h3 > 0 && h2 == 0 {
-		return false
-	}
-	if h4 > 0 && h3 == 0 {
-		return false
-	}
-	if h5 > 0 && h4 == 0 {
-		return false
-	}
-	if h6 > 0 && h5 == 0 {
-		return false
-	}
-
-	return true
-}
-
-func (a *SEOAuditor) checkURLExists(urlStr string) bool {
+func (a *SEOAuditor) checkURLExists(urlStr string, targetURL string, opts AuditOptions) bool {
 	client := &http.Client{
 		Timeout: 5 * time.Second,
 		Transport: &http.Transport{
@@ -1198,7 +1456,13 @@ func (a *SEOAuditor) checkURLExists(urlStr string) bool {
 		},
 	}
 
-	resp, err := client.Head(urlStr)
+	req, err := http.NewRequest(http.MethodHead, urlStr, nil)
+	if err != nil {
+		return false
+	}
+	opts.applyToRequest(req, targetURL)
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return false
 	}
@@ -1209,13 +1473,14 @@ func (a *SEOAuditor) checkURLExists(urlStr string) bool {
 
 func (a *SEOAuditor) calculateOverallScore(audit *SEOAudit) float64 {
 	weights := map[string]float64{
-		"technical": 0.30,
-		"onpage":    0.25,
-		"content":   0.20,
-		"links":     0.10,
-		"schema":    0.05,
-		"security":  0.05,
-		"ux":        0.05,
+		"technical":     0.28,
+		"onpage":        0.23,
+		"content":       0.18,
+		"links":         0.10,
+		"schema":        0.05,
+		"security":      0.05,
+		"ux":            0.05,
+		"accessibility": 0.06,
 	}
 
 	score := 0.0
@@ -1226,6 +1491,7 @@ func (a *SEOAuditor) calculateOverallScore(audit *SEOAudit) float64 {
 	score += (audit.SchemaMarkup.Score / audit.SchemaMarkup.MaxScore) * 100 * weights["schema"]
 	score += (audit.Security.Score / audit.Security.MaxScore) * 100 * weights["security"]
 	score += (audit.UserExperience.Score / audit.UserExperience.MaxScore) * 100 * weights["ux"]
+	score += (audit.Accessibility.Score / audit.Accessibility.MaxScore) * 100 * weights["accessibility"]
 
 	return math.Round(score*100) / 100
 }
@@ -1263,6 +1529,7 @@ func (a *SEOAuditor) generateRecommendations(audit *SEOAudit) []string {
 	recommendations := []string{}
 
 	// Collect all issues from all categories
+	recommendations = append(recommendations, audit.Consent.Issues...)
 	recommendations = append(recommendations, audit.TechnicalSEO.Issues...)
 	recommendations = append(recommendations, audit.OnPageSEO.Issues...)
 	recommendations = append(recommendations, audit.ContentQuality.Issues...)
@@ -1270,13 +1537,26 @@ func (a *SEOAuditor) generateRecommendations(audit *SEOAudit) []string {
 	recommendations = append(recommendations, audit.SchemaMarkup.Issues...)
 	recommendations = append(recommendations, audit.Security.Issues...)
 	recommendations = append(recommendations, audit.UserExperience.Issues...)
+	recommendations = append(recommendations, audit.Accessibility.Issues...)
 	recommendations = append(recommendations, audit.WebVitals.Issues...)
+	if audit.Googlebot != nil {
+		recommendations = append(recommendations, audit.Googlebot.Issues...)
+	}
+	if audit.Hreflang != nil {
+		recommendations = append(recommendations, audit.Hreflang.Issues...)
+	}
+	if audit.Images != nil {
+		recommendations = append(recommendations, audit.Images.Issues...)
+	}
+	if audit.RawHTML != nil {
+		recommendations = append(recommendations, audit.RawHTML.Issues...)
+	}
 
 	// Add priority recommendations based on scores
-	if audit.TechnicalSEO.Score < 50 {
+	if audit.TechnicalSEO.Score/audit.TechnicalSEO.MaxScore < 0.5 {
 		recommendations = append([]string{"CRITICAL: Address technical SEO issues immediately"}, recommendations...)
 	}
-	if !audit.Security.IsHTTPS {
+	if !audit.Security.IsHTTPS && hasWebScheme(audit.URL) {
 		recommendations = append([]string{"CRITICAL: Implement HTTPS for security and SEO"}, recommendations...)
 	}
 	if !audit.OnPageSEO.HasTitle {
@@ -1300,7 +1580,40 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 	sb.WriteString(fmt.Sprintf("- **URL**: %s\n", audit.URL))
 	sb.WriteString(fmt.Sprintf("- **Audit Date**: %s\n", audit.Timestamp.Format("2006-01-02 15:04:05 UTC")))
 	sb.WriteString(fmt.Sprintf("- **Overall Score**: %.1f/100\n", audit.OverallScore))
-	sb.WriteString(fmt.Sprintf("- **Grade**: %s\n\n", audit.Grade))
+	sb.WriteString(fmt.Sprintf("- **Grade**: %s\n", audit.Grade))
+	sb.WriteString(fmt.Sprintf("- **User Agent**: %s\n", audit.UserAgent))
+	sb.WriteString(fmt.Sprintf("- **Source**: %s\n", audit.Source))
+	if len(audit.NotApplicable) > 0 {
+		sb.WriteString(fmt.Sprintf("- **Not Applicable**: %s\n", strings.Join(audit.NotApplicable, ", ")))
+	}
+	sb.WriteString("\n")
+
+	// Cookie consent handling
+	sb.WriteString("## Cookie Consent\n\n")
+	sb.WriteString(fmt.Sprintf("- **Consent Banner Detected**: %s\n", boolToStatus(audit.Consent.CMPDetected)))
+	if audit.Consent.CMPDetected {
+		sb.WriteString(fmt.Sprintf("- **Consent Platform**: %s\n", audit.Consent.CMP))
+	}
+	if audit.Consent.RequestedAction != "" {
+		sb.WriteString(fmt.Sprintf("- **Requested Action**: %s (applied: %s)\n", audit.Consent.RequestedAction, boolToStatus(audit.Consent.Applied)))
+	}
+	sb.WriteString(fmt.Sprintf("- **Banner Visible During Checks**: %s\n", boolToStatus(audit.Consent.BannerVisible)))
+	if len(audit.Consent.PreConsentCookies) > 0 {
+		sb.WriteString("\n| Cookie | Domain | Third-Party | Session |\n")
+		sb.WriteString("|--------|--------|-------------|---------|\n")
+		for _, cookie := range audit.Consent.PreConsentCookies {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", cookie.Name, cookie.Domain, boolToStatus(cookie.ThirdParty), boolToStatus(cookie.Session)))
+		}
+	}
+	sb.WriteString("\n")
+
+	if len(audit.Consent.Issues) > 0 {
+		sb.WriteString("### Issues Found\n\n")
+		for _, issue := range audit.Consent.Issues {
+			sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue))
+		}
+		sb.WriteString("\n")
+	}
 
 	// Score breakdown
 	sb.WriteString("## Score Breakdown\n\n")
@@ -1313,7 +1626,12 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 	sb.WriteString(fmt.Sprintf("| Schema Markup | %.0f | %.0f | %.0f%% |\n", audit.SchemaMarkup.Score, audit.SchemaMarkup.MaxScore, (audit.SchemaMarkup.Score/audit.SchemaMarkup.MaxScore)*100))
 	sb.WriteString(fmt.Sprintf("| Security | %.0f | %.0f | %.0f%% |\n", audit.Security.Score, audit.Security.MaxScore, (audit.Security.Score/audit.Security.MaxScore)*100))
 	sb.WriteString(fmt.Sprintf("| User Experience | %.0f | %.0f | %.0f%% |\n", audit.UserExperience.Score, audit.UserExperience.MaxScore, (audit.UserExperience.Score/audit.UserExperience.MaxScore)*100))
-	sb.WriteString(fmt.Sprintf("| Web Vitals | %.0f | %.0f | %.0f%% |\n\n", audit.WebVitals.Score, audit.WebVitals.MaxScore, (audit.WebVitals.Score/audit.WebVitals.MaxScore)*100))
+	sb.WriteString(fmt.Sprintf("| Accessibility | %.0f | %.0f | %.0f%% |\n", audit.Accessibility.Score, audit.Accessibility.MaxScore, (audit.Accessibility.Score/audit.Accessibility.MaxScore)*100))
+	if audit.WebVitals.MaxScore > 0 {
+		sb.WriteString(fmt.Sprintf("| Web Vitals | %.0f | %.0f | %.0f%% |\n\n", audit.WebVitals.Score, audit.WebVitals.MaxScore, (audit.WebVitals.Score/audit.WebVitals.MaxScore)*100))
+	} else {
+		sb.WriteString("| Web Vitals | - | - | N/A |\n\n")
+	}
 
 	// Technical SEO Details
 	sb.WriteString("## Technical SEO Analysis\n\n")
@@ -1321,12 +1639,22 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 	sb.WriteString(fmt.Sprintf("- **HTTPS**: %s\n", boolToStatus(audit.TechnicalSEO.IsHTTPS)))
 	sb.WriteString(fmt.Sprintf("- **Viewport Meta Tag**: %s\n", boolToStatus(audit.TechnicalSEO.HasViewport)))
 	sb.WriteString(fmt.Sprintf("- **Mobile Friendly**: %s\n", boolToStatus(audit.TechnicalSEO.IsMobileFriendly)))
-	sb.WriteString(fmt.Sprintf("- **robots.txt**: %s\n", boolToStatus(audit.TechnicalSEO.HasRobotsTxt)))
-	sb.WriteString(fmt.Sprintf("- **Sitemap**: %s\n", boolToStatus(audit.TechnicalSEO.HasSitemap)))
-	sb.WriteString(fmt.Sprintf("- **Page Load Time**: %.0fms\n", audit.TechnicalSEO.LoadTime))
+	if audit.Source == AuditSourceHTML {
+		sb.WriteString("- **robots.txt**: N/A\n")
+		sb.WriteString("- **Sitemap**: N/A\n")
+		sb.WriteString("- **Page Load Time**: N/A\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("- **robots.txt**: %s\n", boolToStatus(audit.TechnicalSEO.HasRobotsTxt)))
+		sb.WriteString(fmt.Sprintf("- **Sitemap**: %s\n", boolToStatus(audit.TechnicalSEO.HasSitemap)))
+		sb.WriteString(fmt.Sprintf("- **Page Load Time**: %.0fms\n", audit.TechnicalSEO.LoadTime))
+	}
 	sb.WriteString(fmt.Sprintf("- **Page Size**: %s\n", formatBytes(audit.TechnicalSEO.PageSize)))
 	sb.WriteString(fmt.Sprintf("- **HTTP Requests**: %d\n", audit.TechnicalSEO.HTTPRequests))
-	sb.WriteString(fmt.Sprintf("- **HTTP Status Code**: %d\n\n", audit.TechnicalSEO.HTTPStatusCode))
+	if audit.Source == AuditSourceHTML {
+		sb.WriteString("- **HTTP Status Code**: N/A\n\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("- **HTTP Status Code**: %d\n\n", audit.TechnicalSEO.HTTPStatusCode))
+	}
 
 	if len(audit.TechnicalSEO.Issues) > 0 {
 		sb.WriteString("### Issues Found\n\n")
@@ -1339,8 +1667,8 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 	// On-Page SEO Details
 	sb.WriteString("## On-Page SEO Analysis\n\n")
 	sb.WriteString("### Current Status\n\n")
-	sb.WriteString(fmt.Sprintf("- **Title Tag**: %s (Length: %d characters)\n", boolToStatus(audit.OnPageSEO.HasTitle), audit.OnPageSEO.TitleLength))
-	sb.WriteString(fmt.Sprintf("- **Meta Description**: %s (Length: %d characters)\n", boolToStatus(audit.OnPageSEO.HasMetaDescription), audit.OnPageSEO.MetaDescriptionLength))
+	sb.WriteString(fmt.Sprintf("- **Title Tag**: %s (Length: %d characters, %.0fpx)\n", boolToStatus(audit.OnPageSEO.HasTitle), audit.OnPageSEO.TitleLength, audit.OnPageSEO.SERPSnippet.TitleWidth))
+	sb.WriteString(fmt.Sprintf("- **Meta Description**: %s (Length: %d characters, %.0fpx)\n", boolToStatus(audit.OnPageSEO.HasMetaDescription), audit.OnPageSEO.MetaDescriptionLength, audit.OnPageSEO.SERPSnippet.DescriptionWidth))
 	sb.WriteString(fmt.Sprintf("- **H1 Tag**: %s (Count: %d)\n", boolToStatus(audit.OnPageSEO.HasH1), audit.OnPageSEO.H1Count))
 	sb.WriteString(fmt.Sprintf("- **H2 Tags Count**: %d\n", audit.OnPageSEO.H2Count))
 	sb.WriteString(fmt.Sprintf("- **Heading Hierarchy**: %s\n", boolToStatus(audit.OnPageSEO.ProperHeadingHierarchy)))
@@ -1348,6 +1676,93 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 	sb.WriteString(fmt.Sprintf("- **Twitter Card**: %s\n", boolToStatus(audit.OnPageSEO.HasTwitterCard)))
 	sb.WriteString(fmt.Sprintf("- **Canonical Tag**: %s\n\n", boolToStatus(audit.OnPageSEO.HasCanonical)))
 
+	snippet := audit.OnPageSEO.SERPSnippet
+	if snippet.Desktop.Title != "" || snippet.Desktop.Description != "" {
+		sb.WriteString("### Search Result Preview\n\n")
+		for _, device := range []struct {
+			name    string
+			preview SERPPreview
+		}{{"Desktop", snippet.Desktop}, {"Mobile", snippet.Mobile}} {
+			sb.WriteString(fmt.Sprintf("**%s**\n\n", device.name))
+			sb.WriteString(fmt.Sprintf("> %s\n>\n", snippet.DisplayURL))
+			sb.WriteString(fmt.Sprintf("> **%s**\n>\n", valueOrNone(device.preview.Title)))
+			sb.WriteString(fmt.Sprintf("> %s\n\n", valueOrNone(device.preview.Description)))
+		}
+		if snippet.Image != "" {
+			sb.WriteString(fmt.Sprintf("![Search result preview](%s)\n\n", snippet.Image))
+		}
+	}
+
+	social := audit.OnPageSEO.SocialCard
+	if social.OGTitle != "" || social.Image != nil || social.TwitterCard != "" {
+		sb.WriteString("### Social Sharing\n\n")
+		sb.WriteString(fmt.Sprintf("- **og:type**: %s\n", valueOrNone(social.OGType)))
+		sb.WriteString(fmt.Sprintf("- **og:url**: %s (matches canonical: %s)\n", valueOrNone(social.OGURL), boolToStatus(social.OGURLMatchesCanonical)))
+		sb.WriteString(fmt.Sprintf("- **twitter:card**: %s\n", valueOrNone(social.TwitterCard)))
+		twitterImage := valueOrNone(social.TwitterImage)
+		if social.TwitterImageFallback {
+			twitterImage += " (from og:image)"
+		}
+		sb.WriteString(fmt.Sprintf("- **twitter:image**: %s\n", twitterImage))
+		if img := social.Image; img != nil {
+			sb.WriteString(fmt.Sprintf("- **og:image**: %s\n", img.URL))
+			if img.Loads {
+				sb.WriteString(fmt.Sprintf("- **Image**: %d×%d (%.2f:1), %s, %s\n", img.Width, img.Height, img.AspectRatio, formatBytes(img.Bytes), img.ContentType))
+			}
+		}
+		if img := social.TwitterImageDetails; img != nil && img.Loads {
+			sb.WriteString(fmt.Sprintf("- **twitter:image Size**: %d×%d (%.2f:1), %s, %s\n", img.Width, img.Height, img.AspectRatio, formatBytes(img.Bytes), img.ContentType))
+		}
+		sb.WriteString("\n")
+		if len(social.PlatformChecks) > 0 {
+			sb.WriteString("| Platform | Image | Recommended | Status |\n")
+			sb.WriteString("|----------|-------|-------------|--------|\n")
+			for _, check := range social.PlatformChecks {
+				status := "✅ OK"
+				if !check.Meets {
+					status = "❌ " + check.Problem
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", check.Platform, check.Image, check.Recommended, status))
+			}
+			sb.WriteString("\n")
+		}
+		if social.Preview != "" {
+			sb.WriteString(fmt.Sprintf("![Share preview](%s)\n\n", social.Preview))
+		}
+	}
+
+	if len(audit.OnPageSEO.HeadingOutline) > 0 {
+		sb.WriteString("### Heading Outline\n\n")
+		for _, heading := range audit.OnPageSEO.HeadingOutline {
+			text := heading.Text
+			if text == "" {
+				text = "_(empty)_"
+			}
+			line := fmt.Sprintf("%s- **H%d** %s `%s`", strings.Repeat("  ", heading.Level-1), heading.Level, text, heading.Selector)
+			if len(heading.Issues) > 0 {
+				line += " ⚠️ " + strings.Join(heading.Issues, "; ")
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(audit.OnPageSEO.Keywords) > 0 {
+		sb.WriteString("### Target Keywords\n\n")
+		sb.WriteString("| Keyword | Title | Meta Description | H1 | First Paragraph | URL | Headings | Image Alts | Anchors | Density |\n")
+		sb.WriteString("|---------|-------|------------------|----|-----------------|-----|----------|------------|---------|---------|\n")
+		for _, keyword := range audit.OnPageSEO.Keywords {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %d | %d | %d | %.1f%% (%d) |\n", keyword.Keyword, keywordMatchStatus(keyword.Title), keywordMatchStatus(keyword.MetaDescription), keywordMatchStatus(keyword.H1), keywordMatchStatus(keyword.FirstParagraph), boolToStatus(keyword.URLSlug), keyword.Headings, keyword.ImageAlts, keyword.Anchors, keyword.Density, keyword.Occurrences))
+		}
+		sb.WriteString("\n")
+		for _, keyword := range audit.OnPageSEO.Keywords {
+			if len(keyword.Variants) > 0 {
+				sb.WriteString(fmt.Sprintf("- **%s** variants found: %s\n", keyword.Keyword, strings.Join(keyword.Variants, ", ")))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	if len(audit.OnPageSEO.Issues) > 0 {
 		sb.WriteString("### Issues Found\n\n")
 		for _, issue := range audit.OnPageSEO.Issues {
@@ -1359,13 +1774,92 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 	// Content Quality Details
 	sb.WriteString("## Content Quality Analysis\n\n")
 	sb.WriteString("### Current Status\n\n")
-	sb.WriteString(fmt.Sprintf("- **Word Count**: %d\n", audit.ContentQuality.WordCount))
+	sb.WriteString(fmt.Sprintf("- **Word Count**: %d (main content)\n", audit.ContentQuality.WordCount))
+	sb.WriteString(fmt.Sprintf("- **Main Content**: `%s` (found by %s)\n", audit.ContentQuality.MainContent.Selector, audit.ContentQuality.MainContent.Method))
+	sb.WriteString(fmt.Sprintf("- **Text-to-HTML Ratio**: %.1f%%\n", audit.ContentQuality.MainContent.TextToHTMLRatio))
+	sb.WriteString(fmt.Sprintf("- **Boilerplate Share**: %.0f%%\n", audit.ContentQuality.MainContent.BoilerplateShare*100))
 	sb.WriteString(fmt.Sprintf("- **Paragraph Count**: %d\n", audit.ContentQuality.ParagraphCount))
-	sb.WriteString(fmt.Sprintf("- **Images**: %d (with alt text: %d)\n", audit.ContentQuality.ImageCount, audit.ContentQuality.ImagesWithAlt))
+	sb.WriteString(fmt.Sprintf("- **Images**: %d (with good alt text: %d)\n", audit.ContentQuality.ImageCount, audit.ContentQuality.ImagesWithAlt))
 	sb.WriteString(fmt.Sprintf("- **Internal Links**: %d\n", audit.ContentQuality.InternalLinks))
 	sb.WriteString(fmt.Sprintf("- **External Links**: %d\n", audit.ContentQuality.ExternalLinks))
 	sb.WriteString(fmt.Sprintf("- **Readability Score**: %.1f\n\n", audit.ContentQuality.ReadabilityScore))
 
+	language := audit.ContentQuality.Language
+	sb.WriteString("### Language\n\n")
+	if language.Detected != "" {
+		sb.WriteString(fmt.Sprintf("- **Detected**: %s (confidence %.0f%%)\n", languageName(language.Detected), language.Confidence*100))
+	} else {
+		sb.WriteString("- **Detected**: Undetermined\n")
+	}
+	sb.WriteString(fmt.Sprintf("- **html lang**: %s\n", valueOrNone(language.HTMLLang)))
+	sb.WriteString(fmt.Sprintf("- **og:locale**: %s\n", valueOrNone(language.OGLocale)))
+	sb.WriteString(fmt.Sprintf("- **Analyzed As**: %s\n\n", languageName(language.AnalyzedAs)))
+
+	if readability := audit.ContentQuality.Readability; readability.Words > 0 {
+		sb.WriteString("### Readability\n\n")
+		sb.WriteString(fmt.Sprintf("%d words, %d sentences, %.1f words per sentence, %.1f syllables per word.\n\n", readability.Words, readability.Sentences, readability.WordsPerSentence, readability.SyllablesPerWord))
+		if readability.Formula == "" {
+			sb.WriteString(fmt.Sprintf("No reading ease formula is available for %s.\n\n", languageName(readability.Language)))
+		} else {
+			sb.WriteString("| Metric | Value |\n")
+			sb.WriteString("|--------|-------|\n")
+			sb.WriteString(fmt.Sprintf("| %s | %.1f |\n", readability.Formula, readability.ReadingEase))
+			if readability.Language == "en" {
+				sb.WriteString(fmt.Sprintf("| Flesch-Kincaid Grade | %.1f |\n", readability.FleschKincaidGrade))
+				sb.WriteString(fmt.Sprintf("| Gunning Fog | %.1f |\n", readability.GunningFog))
+				sb.WriteString(fmt.Sprintf("| SMOG | %.1f |\n", readability.SMOG))
+				sb.WriteString(fmt.Sprintf("| Coleman-Liau | %.1f |\n", readability.ColemanLiau))
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(readability.HardestParagraphs) > 0 {
+			sb.WriteString("#### Hardest Paragraphs\n\n")
+			for _, paragraph := range readability.HardestParagraphs {
+				if readability.Language == "en" {
+					sb.WriteString(fmt.Sprintf("- **Grade %.1f** (Flesch %.1f): %s\n", paragraph.FleschKincaidGrade, paragraph.ReadingEase, paragraph.Text))
+				} else {
+					sb.WriteString(fmt.Sprintf("- **%s %.1f**: %s\n", readability.Formula, paragraph.ReadingEase, paragraph.Text))
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if topics := audit.ContentQuality.Topics; len(topics.Terms) > 0 {
+		sb.WriteString("### Topics\n\n")
+		sb.WriteString(fmt.Sprintf("- **Dominant Topic**: %s\n", topics.DominantTopic))
+		sb.WriteString(fmt.Sprintf("- **Title Reflects Topic**: %s\n", boolToStatus(topics.TitleReflectsTopic)))
+		sb.WriteString(fmt.Sprintf("- **H1 Reflects Topic**: %s\n\n", boolToStatus(topics.H1ReflectsTopic)))
+		sb.WriteString("| Term | Occurrences | Score |\n")
+		sb.WriteString("|------|-------------|-------|\n")
+		for _, term := range topics.Terms {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %.1f |\n", term.Term, term.Occurrences, term.Score))
+		}
+		sb.WriteString("\n")
+	}
+
+	flaggedAlts := []ImageAltText{}
+	for _, img := range audit.ContentQuality.AltText.Images {
+		if !img.acceptable() {
+			flaggedAlts = append(flaggedAlts, img)
+		}
+	}
+	if len(flaggedAlts) > 0 {
+		sb.WriteString("### Image Alt Text\n\n")
+		sb.WriteString("| Image | Selector | Alt | Status |\n")
+		sb.WriteString("|-------|----------|-----|--------|\n")
+		for _, img := range flaggedAlts {
+			preview := img.Src
+			if img.Thumbnail != "" {
+				preview = fmt.Sprintf("![%s](%s)", img.Status, img.Thumbnail)
+			}
+			alt := strings.ReplaceAll(img.Alt, "|", "\\|")
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n", preview, img.Selector, valueOrNone(alt), strings.Join(img.Problems, "; ")))
+		}
+		sb.WriteString("\n")
+	}
+
 	if len(audit.ContentQuality.Issues) > 0 {
 		sb.WriteString("### Issues Found\n\n")
 		for _, issue := range audit.ContentQuality.Issues {
@@ -1398,9 +1892,42 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 	if len(audit.SchemaMarkup.SchemaTypes) > 0 {
 		sb.WriteString(fmt.Sprintf("- **Schema Types Found**: %s\n", strings.Join(audit.SchemaMarkup.SchemaTypes, ", ")))
 	}
+	if len(audit.SchemaMarkup.Syntaxes) > 0 {
+		sb.WriteString(fmt.Sprintf("- **Syntaxes Used**: %s\n", strings.Join(audit.SchemaMarkup.Syntaxes, ", ")))
+	}
 	sb.WriteString(fmt.Sprintf("- **Organization Schema**: %s\n", boolToStatus(audit.SchemaMarkup.HasOrganization)))
 	sb.WriteString(fmt.Sprintf("- **Breadcrumb Schema**: %s\n\n", boolToStatus(audit.SchemaMarkup.HasBreadcrumb)))
 
+	if len(audit.SchemaMarkup.Entities) > 0 {
+		sb.WriteString("### Structured Data Items\n\n")
+		sb.WriteString("| Item | Syntax | Valid | Missing Required | Missing Recommended |\n")
+		sb.WriteString("|------|--------|-------|------------------|---------------------|\n")
+		for _, entity := range audit.SchemaMarkup.Entities {
+			sb.WriteString(fmt.Sprintf("| %s (%s) | %s | %s | %s | %s |\n", entity.Path, strings.Join(entity.Types, ", "), entity.Syntax, boolToStatus(entity.Valid), strings.Join(entity.MissingRequired, ", "), strings.Join(entity.MissingRecommended, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(audit.SchemaMarkup.RichResults) > 0 {
+		sb.WriteString("### Rich Result Eligibility\n\n")
+		sb.WriteString("| Feature | Item | Eligible | Missing Required | Missing Recommended |\n")
+		sb.WriteString("|---------|------|----------|------------------|---------------------|\n")
+		for _, result := range audit.SchemaMarkup.RichResults {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", result.Feature, result.Item, boolToStatus(result.Eligible), strings.Join(result.MissingRequired, ", "), strings.Join(result.MissingRecommended, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(audit.SchemaMarkup.Consistency) > 0 {
+		sb.WriteString("### Structured Data vs Visible Content\n\n")
+		sb.WriteString("| Check | Item | Structured Data | Visible | Consistent |\n")
+		sb.WriteString("|-------|------|-----------------|---------|------------|\n")
+		for _, check := range audit.SchemaMarkup.Consistency {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", check.Check, check.Item, check.Structured, check.Visible, boolToStatus(check.Consistent)))
+		}
+		sb.WriteString("\n")
+	}
+
 	if len(audit.SchemaMarkup.Issues) > 0 {
 		sb.WriteString("### Issues Found\n\n")
 		for _, issue := range audit.SchemaMarkup.Issues {
@@ -1409,6 +1936,18 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 		sb.WriteString("\n")
 	}
 
+	if len(audit.SchemaMarkup.Suggestions) > 0 {
+		sb.WriteString("### Suggested JSON-LD\n\n")
+		sb.WriteString("Review these blocks generated from the page content, then paste them into the page `<head>`.\n\n")
+		for _, suggestion := range audit.SchemaMarkup.Suggestions {
+			sb.WriteString(fmt.Sprintf("#### %s\n\n", suggestion.Type))
+			sb.WriteString(fmt.Sprintf("%s.\n\n", suggestion.Reason))
+			sb.WriteString("```html\n<script type=\"application/ld+json\">\n")
+			sb.WriteString(suggestion.JSONLD)
+			sb.WriteString("\n</script>\n```\n\n")
+		}
+	}
+
 	// Security Details
 	sb.WriteString("## Security Analysis\n\n")
 	sb.WriteString("### Current Status\n\n")
@@ -1441,31 +1980,269 @@ func (a *SEOAuditor) generateMarkdown(audit *SEOAudit) string {
 		sb.WriteString("\n")
 	}
 
-	// Web Vitals Details
-	sb.WriteString("## Core Web Vitals Analysis\n\n")
-	sb.WriteString("### Performance Metrics\n\n")
-	sb.WriteString("| Metric | Value | Rating | Target |\n")
-	sb.WriteString("|--------|-------|--------|--------|\n")
-	sb.WriteString(fmt.Sprintf("| LCP (Largest Contentful Paint) | %.0fms | %s | ≤2500ms |\n", audit.WebVitals.LCP, ratingToEmoji(audit.WebVitals.LCPRating)))
-	sb.WriteString(fmt.Sprintf("| FCP (First Contentful Paint) | %.0fms | %s | ≤1800ms |\n", audit.WebVitals.FCP, ratingToEmoji(audit.WebVitals.FCPRating)))
-	sb.WriteString(fmt.Sprintf("| CLS (Cumulative Layout Shift) | %.3f | %s | ≤0.1 |\n", audit.WebVitals.CLS, ratingToEmoji(audit.WebVitals.CLSRating)))
-	sb.WriteString(fmt.Sprintf("| INP (Interaction to Next Paint) | %.0fms | %s | ≤200ms |\n", audit.WebVitals.INP, ratingToEmoji(audit.WebVitals.INPRating)))
-	sb.WriteString(fmt.Sprintf("| TTFB (Time to First Byte) | %.0fms | %s | ≤800ms |\n\n", audit.WebVitals.TTFB, ratingToEmoji(audit.WebVitals.TTFBRating)))
-
-	sb.WriteString("### Additional Metrics\n\n")
-	sb.WriteString(fmt.Sprintf("- **DOM Content Loaded**: %.0fms\n", audit.WebVitals.DOMContentLoaded))
-	sb.WriteString(fmt.Sprintf("- **DOM Complete**: %.0fms\n", audit.WebVitals.DOMComplete))
-	sb.WriteString(fmt.Sprintf("- **Total Transfer Size**: %s\n", formatBytes(audit.WebVitals.TransferSize)))
-	sb.WriteString(fmt.Sprintf("- **Resource Count**: %d\n\n", audit.WebVitals.ResourceCount))
-
-	if len(audit.WebVitals.Issues) > 0 {
+	// Accessibility Details
+	accessibility := audit.Accessibility
+	sb.WriteString("## Accessibility Analysis\n\n")
+	sb.WriteString("### Current Status\n\n")
+	sb.WriteString(fmt.Sprintf("- **Color Contrast**: %d of %d text elements below WCAG AA\n", accessibility.RuleCounts[RuleColorContrast], accessibility.ContrastChecked))
+	sb.WriteString(fmt.Sprintf("- **Landmarks**: %d main, %d banner, %d contentinfo, %d navigation\n", accessibility.Landmarks.Main, accessibility.Landmarks.Banner, accessibility.Landmarks.ContentInfo, accessibility.Landmarks.Navigation))
+	sb.WriteString(fmt.Sprintf("- **Unlabeled Form Controls**: %d\n", accessibility.RuleCounts[RuleFormLabel]))
+	sb.WriteString(fmt.Sprintf("- **Unnamed Links and Buttons**: %d\n", accessibility.RuleCounts[RuleAccessibleName]))
+	sb.WriteString(fmt.Sprintf("- **ARIA Errors**: %d\n", accessibility.RuleCounts[RuleARIA]))
+	sb.WriteString(fmt.Sprintf("- **Hidden Focusable Elements**: %d\n", accessibility.RuleCounts[RuleHiddenFocusable]))
+	sb.WriteString(fmt.Sprintf("- **tabindex Anti-Patterns**: %d\n", accessibility.RuleCounts[RuleTabindex]))
+	sb.WriteString(fmt.Sprintf("- **Keyboard Problems**: %d\n\n", accessibility.RuleCounts[RuleKeyboard]))
+
+	if len(accessibility.ContrastIssues) > 0 {
+		sb.WriteString("### Color Contrast\n\n")
+		sb.WriteString("| Element | Text | Colors | Ratio | Required |\n")
+		sb.WriteString("|---------|------|--------|-------|----------|\n")
+		for _, issue := range accessibility.ContrastIssues {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s on %s | %.2f:1 | %.1f:1 |\n", issue.Selector, strings.ReplaceAll(issue.Text, "|", "\\|"), issue.Foreground, issue.Background, issue.Ratio, issue.Required))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(accessibility.Violations) > 0 {
+		sb.WriteString("### Violations\n\n")
+		sb.WriteString("| Rule | Element | Problem |\n")
+		sb.WriteString("|------|---------|---------|\n")
+		for _, violation := range accessibility.Violations {
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", violation.Rule, violation.Selector, violation.Message))
+		}
+		sb.WriteString("\n")
+	}
+
+	keyboard := accessibility.Keyboard
+	sb.WriteString("### Keyboard Navigation\n\n")
+	sb.WriteString(fmt.Sprintf("- **Tab Stops**: %d (went all the way round: %s)\n", len(keyboard.FocusOrder), boolToStatus(keyboard.Complete)))
+	sb.WriteString(fmt.Sprintf("- **Keyboard Trap**: %s\n", boolToStatus(keyboard.Trap != nil)))
+	sb.WriteString(fmt.Sprintf("- **Skip Link**: %s (works: %s)\n", boolToStatus(keyboard.SkipLink.Present), boolToStatus(keyboard.SkipLink.Works)))
+	sb.WriteString(fmt.Sprintf("- **Missing Focus Indicator**: %d\n", keyboard.MissingFocusStyle))
+	sb.WriteString(fmt.Sprintf("- **Unreachable by Keyboard**: %d\n\n", len(keyboard.Unreachable)))
+
+	if len(keyboard.FocusOrder) > 0 {
+		sb.WriteString("| # | Element | Name | Focus Indicator |\n")
+		sb.WriteString("|---|---------|------|-----------------|\n")
+		for _, stop := range keyboard.FocusOrder {
+			indicator := boolToStatus(stop.FocusIndicator)
+			if stop.Screenshot != "" {
+				indicator += fmt.Sprintf(" ![Focused %s](%s)", stop.Tag, stop.Screenshot)
+			}
+			sb.WriteString(fmt.Sprintf("| %d | `%s` | %s | %s |\n", stop.Order, stop.Selector, valueOrNone(strings.ReplaceAll(stop.Name, "|", "\\|")), indicator))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(keyboard.Unreachable) > 0 {
+		sb.WriteString("| Unreachable Element | Problem |\n")
+		sb.WriteString("|---------------------|---------|\n")
+		for _, element := range keyboard.Unreachable {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s |\n", element.Selector, element.Message))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(accessibility.Issues) > 0 {
 		sb.WriteString("### Issues Found\n\n")
-		for _, issue := range audit.WebVitals.Issues {
+		for _, issue := range accessibility.Issues {
 			sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue))
 		}
 		sb.WriteString("\n")
 	}
 
+	// Web Vitals Details
+	sb.WriteString("## Core Web Vitals Analysis\n\n")
+	if audit.WebVitals.MaxScore == 0 {
+		sb.WriteString("Not applicable: Web Vitals need a live page load.\n\n")
+	} else {
+		sb.WriteString("### Performance Metrics\n\n")
+		sb.WriteString("| Metric | Value | Rating | Target |\n")
+		sb.WriteString("|--------|-------|--------|--------|\n")
+		sb.WriteString(fmt.Sprintf("| LCP (Largest Contentful Paint) | %dms | %s | ≤2500ms |\n", audit.WebVitals.LCP, ratingToEmoji(audit.WebVitals.LCPRating)))
+		sb.WriteString(fmt.Sprintf("| FCP (First Contentful Paint) | %dms | %s | ≤1800ms |\n", audit.WebVitals.FCP, ratingToEmoji(audit.WebVitals.FCPRating)))
+		sb.WriteString(fmt.Sprintf("| CLS (Cumulative Layout Shift) | %.3f | %s | ≤0.1 |\n", audit.WebVitals.CLS, ratingToEmoji(audit.WebVitals.CLSRating)))
+		sb.WriteString(fmt.Sprintf("| INP (Interaction to Next Paint) | %.0fms | %s | ≤200ms |\n", audit.WebVitals.INP, ratingToEmoji(audit.WebVitals.INPRating)))
+		sb.WriteString(fmt.Sprintf("| TTFB (Time to First Byte) | %.0fms | %s | ≤800ms |\n\n", audit.WebVitals.TTFB, ratingToEmoji(audit.WebVitals.TTFBRating)))
+
+		sb.WriteString("### Additional Metrics\n\n")
+		sb.WriteString(fmt.Sprintf("- **DOM Content Loaded**: %.0fms\n", audit.WebVitals.DOMContentLoaded))
+		sb.WriteString(fmt.Sprintf("- **DOM Complete**: %.0fms\n", audit.WebVitals.DOMComplete))
+		sb.WriteString(fmt.Sprintf("- **Total Transfer Size**: %s\n", formatBytes(audit.WebVitals.TransferSize)))
+		sb.WriteString(fmt.Sprintf("- **Resource Count**: %d\n\n", audit.WebVitals.ResourceCount))
+
+		if path := audit.WebVitals.CriticalPath; path != nil {
+			sb.WriteString("### Critical Rendering Path\n\n")
+			sb.WriteString(fmt.Sprintf("- **First Contentful Paint**: %.0fms\n", path.FCP))
+			sb.WriteString(fmt.Sprintf("- **Render-Blocking Resources**: %d (finished at %.0fms)\n", len(path.RenderBlocking), path.RenderBlockingEnd))
+			sb.WriteString(fmt.Sprintf("- **Critical Chain Depth**: %d (%.0fms)\n", path.ChainDepth, path.LongestChainDuration))
+			if path.LCPResource != "" {
+				sb.WriteString(fmt.Sprintf("- **LCP Resource**: %s (preloaded: %s)\n", path.LCPResource, boolToStatus(path.LCPPreloaded)))
+			}
+			sb.WriteString("\n")
+
+			if len(path.RenderBlocking) > 0 {
+				sb.WriteString("| Render-Blocking Resource | Type | Size | Start | End |\n")
+				sb.WriteString("|--------------------------|------|------|-------|-----|\n")
+				for _, resource := range path.RenderBlocking {
+					sb.WriteString(fmt.Sprintf("| %s | %s | %s | %.0fms | %.0fms |\n", resource.URL, resource.Type, formatBytes(resource.Bytes), resource.Start, resource.End))
+				}
+				sb.WriteString("\n")
+			}
+
+			if len(path.LongestChain) > 1 {
+				sb.WriteString("**Longest critical request chain:**\n\n")
+				for i, entry := range path.LongestChain {
+					sb.WriteString(fmt.Sprintf("%s- %s (%s, %.0f-%.0fms)\n", strings.Repeat("  ", i), entry.URL, entry.ResourceType, entry.Start, entry.End))
+				}
+				sb.WriteString("\n")
+			}
+
+			if len(path.UnusedPreloads) > 0 {
+				sb.WriteString("| Unused Preload | as | Reason |\n")
+				sb.WriteString("|----------------|----|--------|\n")
+				for _, preload := range path.UnusedPreloads {
+					sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", preload.URL, valueOrNone(preload.As), preload.Reason))
+				}
+				sb.WriteString("\n")
+			}
+		}
+
+		if coverage := audit.WebVitals.Coverage; coverage != nil && coverage.TotalBytes > 0 {
+			sb.WriteString("### Unused JavaScript and CSS\n\n")
+			sb.WriteString(fmt.Sprintf("- **Unused**: %s of %s\n", formatBytes(coverage.UnusedBytes), formatBytes(coverage.TotalBytes)))
+			sb.WriteString(fmt.Sprintf("- **First-Party / Third-Party Unused**: %s / %s\n", formatBytes(coverage.FirstPartyUnused), formatBytes(coverage.ThirdPartyUnused)))
+			sb.WriteString(fmt.Sprintf("- **Estimated Savings**: %s\n\n", formatBytes(coverage.EstimatedSavings)))
+
+			sb.WriteString("| Resource | Type | Party | Size | Unused | Savings |\n")
+			sb.WriteString("|----------|------|-------|------|--------|---------|\n")
+			for _, kind := range []struct {
+				name    string
+				entries []CoverageEntry
+			}{{"JS", coverage.Scripts}, {"CSS", coverage.Stylesheets}} {
+				for _, entry := range kind.entries {
+					party := "Third"
+					if entry.FirstParty {
+						party = "First"
+					}
+					name := entry.URL
+					if entry.Inline {
+						name += " (inline)"
+					}
+					sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s (%.1f%%) | %s |\n", name, kind.name, party, formatBytes(entry.TotalBytes), formatBytes(entry.UnusedBytes), entry.UnusedPercent, formatBytes(entry.EstimatedSavings)))
+				}
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(audit.WebVitals.Issues) > 0 {
+			sb.WriteString("### Issues Found\n\n")
+			for _, issue := range audit.WebVitals.Issues {
+				sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// hreflang Cluster Details
+	if audit.Hreflang != nil {
+		sb.WriteString("## hreflang Cluster\n\n")
+		sb.WriteString(fmt.Sprintf("- **Languages**: %s\n", strings.Join(audit.Hreflang.Codes, ", ")))
+		sb.WriteString(fmt.Sprintf("- **Self-Reference**: %s\n", boolToStatus(audit.Hreflang.HasSelfReference)))
+		sb.WriteString(fmt.Sprintf("- **x-default**: %s\n\n", boolToStatus(audit.Hreflang.HasXDefault)))
+
+		if len(audit.Hreflang.Alternates) > 0 {
+			sb.WriteString(hreflangMatrix(audit.Hreflang, audit.URL))
+		}
+
+		if len(audit.Hreflang.Issues) > 0 {
+			sb.WriteString("### Issues Found\n\n")
+			for _, issue := range audit.Hreflang.Issues {
+				sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Image Optimization Details
+	if audit.Images != nil {
+		sb.WriteString("## Image Optimization\n\n")
+		sb.WriteString(fmt.Sprintf("- **Images**: %d (%d downloaded)\n", audit.Images.ImageCount, audit.Images.Analyzed))
+		sb.WriteString(fmt.Sprintf("- **Total Size**: %s\n", formatBytes(audit.Images.TotalBytes)))
+		sb.WriteString(fmt.Sprintf("- **Potential Savings**: %s\n\n", formatBytes(audit.Images.PotentialSavings)))
+
+		if audit.Images.Analyzed > 0 {
+			sb.WriteString("| Image | Format | Size | Intrinsic | Displayed | WebP (est.) | AVIF (est.) | Savings |\n")
+			sb.WriteString("|-------|--------|------|-----------|-----------|-------------|-------------|---------|\n")
+			for i, img := range audit.Images.Images {
+				if i >= 10 || img.PotentialSavings == 0 {
+					break
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d×%d | %d×%d | %s | %s | %s |\n", img.URL, img.Format, formatBytes(img.Bytes), img.IntrinsicWidth, img.IntrinsicHeight, img.DisplayedWidth, img.DisplayedHeight, formatBytes(img.WebPBytes), formatBytes(img.AVIFBytes), formatBytes(img.PotentialSavings)))
+			}
+			sb.WriteString("\n")
+			sb.WriteString(fmt.Sprintf("_%s_\n\n", audit.Images.EstimateMethod))
+		}
+
+		if len(audit.Images.Issues) > 0 {
+			sb.WriteString("### Issues Found\n\n")
+			for _, issue := range audit.Images.Issues {
+				sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Raw HTML Comparison Details
+	if audit.RawHTML != nil {
+		sb.WriteString("## Raw HTML vs Rendered DOM\n\n")
+		sb.WriteString(fmt.Sprintf("- **Server Status Code**: %d\n", audit.RawHTML.StatusCode))
+		sb.WriteString(fmt.Sprintf("- **Server HTML Size**: %s\n", formatBytes(audit.RawHTML.Size)))
+		sb.WriteString(fmt.Sprintf("- **Main Text Words**: %d raw, %d rendered (%.0f%% similar)\n\n", audit.RawHTML.RawWordCount, audit.RawHTML.RenderedWordCount, audit.RawHTML.ContentSimilarity*100))
+
+		if len(audit.RawHTML.Elements) > 0 {
+			sb.WriteString("| Element | Raw HTML | Rendered DOM | Differs |\n")
+			sb.WriteString("|---------|----------|--------------|---------|\n")
+			for _, element := range audit.RawHTML.Elements {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", element.Element, element.RawValue, element.RenderedValue, boolToStatus(element.Differs)))
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(audit.RawHTML.Issues) > 0 {
+			sb.WriteString("### Issues Found\n\n")
+			for _, issue := range audit.RawHTML.Issues {
+				sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Googlebot Comparison Details
+	if audit.Googlebot != nil {
+		sb.WriteString("## Googlebot Rendering Comparison\n\n")
+		sb.WriteString(fmt.Sprintf("- **Googlebot Status Code**: %d\n", audit.Googlebot.StatusCode))
+		sb.WriteString(fmt.Sprintf("- **Main Content Similarity**: %.0f%%\n", audit.Googlebot.ContentSimilarity*100))
+		sb.WriteString(fmt.Sprintf("- **Possible Cloaking**: %s\n\n", boolToStatus(audit.Googlebot.PossibleCloaking)))
+
+		if len(audit.Googlebot.Differences) > 0 {
+			sb.WriteString("| Element | Regular User | Googlebot |\n")
+			sb.WriteString("|---------|--------------|-----------|\n")
+			for _, diff := range audit.Googlebot.Differences {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", diff.Element, diff.User, diff.Googlebot))
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(audit.Googlebot.Issues) > 0 {
+			sb.WriteString("### Issues Found\n\n")
+			for _, issue := range audit.Googlebot.Issues {
+				sb.WriteString(fmt.Sprintf("- ❌ %s\n", issue))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
 	// All Recommendations Summary
 	if len(audit.Recommendations) > 0 {
 		sb.WriteString("## All Issues Summary\n\n")
@@ -1497,6 +2274,25 @@ func boolToStatus(b bool) string {
 	return "❌ No"
 }
 
+// Helper function to describe where a keyword was found
+func keywordMatchStatus(match KeywordMatch) string {
+	if !match.Found {
+		return "❌ No"
+	}
+	if match.Exact {
+		return fmt.Sprintf("✅ Word %d", match.Position)
+	}
+	return fmt.Sprintf("✅ Word %d (variant)", match.Position)
+}
+
+// Helper function to show a missing value as "Not set"
+func valueOrNone(value string) string {
+	if value == "" {
+		return "Not set"
+	}
+	return value
+}
+
 // Helper function to convert rating to emoji
 func ratingToEmoji(rating string) string {
 	switch rating {
@@ -1528,10 +2324,27 @@ func formatBytes(bytes int64) string {
 // AuditRequest represents the request body for the audit endpoint
 type AuditRequest struct {
 	URL string `json:"url"`
+	AuditOptions
 }
 
 // Main function
 func main() {
+	// Command line mode: audit an HTML file instead of starting the server
+	htmlFile := flag.String("html", "", "audit an HTML file without network access (use - for stdin)")
+	baseURL := flag.String("base-url", "", "URL the HTML would be served from")
+	format := flag.String("format", "json", "output format for -html: json or markdown")
+	headers := headerFlags{}
+	flag.Var(headers, "header", "response header for -html as \"Name: value\" (repeatable)")
+	flag.Parse()
+
+	if *htmlFile != "" {
+		if err := runHTMLAuditCLI(*htmlFile, *baseURL, headers, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error auditing HTML: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		JSONEncoder: json.Marshal,
@@ -1578,8 +2391,15 @@ func main() {
 			})
 		}
 
+		if err := req.AuditOptions.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid audit options",
+				"details": err.Error(),
+			})
+		}
+
 		// Audit the website
-		audit, err := auditor.AuditWebsite(req.URL)
+		audit, err := auditor.AuditWebsite(req.URL, req.AuditOptions)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error":   "Error auditing website",
@@ -1591,6 +2411,42 @@ func main() {
 		return c.JSON(audit)
 	})
 
+	// POST endpoint to audit supplied HTML without network access
+	app.Post("/api/audit/html", func(c *fiber.Ctx) error {
+		var req HTMLAuditRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+		}
+
+		if req.HTML == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "HTML is required",
+			})
+		}
+
+		if err := req.AuditOptions.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid audit options",
+				"details": err.Error(),
+			})
+		}
+
+		// Audit the HTML
+		audit, err := auditor.AuditHTML(req)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Error auditing HTML",
+				"details": err.Error(),
+			})
+		}
+
+		// Return the audit results as JSON
+		return c.JSON(audit)
+	})
+
 	// GET endpoint to audit a website (via query parameter)
 	app.Get("/api/audit", func(c *fiber.Ctx) error {
 		targetURL := c.Query("url")
@@ -1600,8 +2456,25 @@ func main() {
 			})
 		}
 
+		opts := AuditOptions{
+			Consent:                 c.Query("consent"),
+			ReportPreConsentCookies: c.QueryBool("pre_consent_cookies"),
+			UserAgent:               c.Query("user_agent"),
+			RenderAsGooglebot:       c.QueryBool("googlebot"),
+			CompareWithGooglebot:    c.QueryBool("compare_googlebot"),
+		}
+		if keywords := c.Query("keywords"); keywords != "" {
+			opts.Keywords = strings.Split(keywords, ",")
+		}
+		if err := opts.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid audit options",
+				"details": err.Error(),
+			})
+		}
+
 		// Audit the website
-		audit, err := auditor.AuditWebsite(targetURL)
+		audit, err := auditor.AuditWebsite(targetURL, opts)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error":   "Error auditing website",
@@ -1619,6 +2492,7 @@ func main() {
 	fmt.Println("  GET  /api/health")
 	fmt.Println("  POST /api/audit  (body: {\"url\": \"https://example.com\"})")
 	fmt.Println("  GET  /api/audit?url=https://example.com")
+	fmt.Println("  POST /api/audit/html  (body: {\"html\": \"<html>...</html>\", \"base_url\": \"https://example.com\"})")
 
 	if err := app.Listen(getPort()); err != nil {
 		fmt.Printf("Error starting server: %v\n", err)
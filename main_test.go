@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateRecommendations(t *testing.T) {
+	audit := &SEOAudit{
+		Consent:      ConsentResult{Issues: []string{"Consent banner (OneTrust) was left open, layout and content metrics include the banner"}},
+		TechnicalSEO: TechnicalSEOScore{Score: 80, Issues: []string{"Missing robots.txt"}},
+		OnPageSEO:    OnPageSEOScore{HasTitle: true},
+		Security:     SecurityScore{IsHTTPS: true},
+	}
+	got := (&SEOAuditor{}).generateRecommendations(audit)
+	want := []string{
+		"Consent banner (OneTrust) was left open, layout and content metrics include the banner",
+		"Missing robots.txt",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("generateRecommendations() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateRecommendationsPriorities(t *testing.T) {
+	tests := []struct {
+		name  string
+		audit SEOAudit
+		want  []string
+	}{
+		{
+			name: "HTML audit without base URL scoring below half",
+			audit: SEOAudit{
+				URL:          "about:blank",
+				TechnicalSEO: TechnicalSEOScore{Score: 20, MaxScore: 45},
+				OnPageSEO:    OnPageSEOScore{HasTitle: true},
+			},
+			want: []string{"CRITICAL: Address technical SEO issues immediately"},
+		},
+		{
+			name: "HTML audit scoring above half of its reduced maximum",
+			audit: SEOAudit{
+				URL:          "about:blank",
+				TechnicalSEO: TechnicalSEOScore{Score: 40, MaxScore: 45},
+				OnPageSEO:    OnPageSEOScore{HasTitle: true},
+			},
+			want: []string{},
+		},
+		{
+			name: "plain HTTP page",
+			audit: SEOAudit{
+				URL:          "http://example.com/",
+				TechnicalSEO: TechnicalSEOScore{Score: 70, MaxScore: 100},
+				OnPageSEO:    OnPageSEOScore{HasTitle: true},
+			},
+			want: []string{"CRITICAL: Implement HTTPS for security and SEO"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, recommendation := range (&SEOAuditor{}).generateRecommendations(&tt.audit) {
+				if strings.HasPrefix(recommendation, "CRITICAL") {
+					got = append(got, recommendation)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("critical recommendations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasWebScheme(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/":  true,
+		"http://example.com/":   true,
+		"about:blank":           false,
+		"file:///tmp/page.html": false,
+		"":                      false,
+	}
+	for rawURL, want := range tests {
+		if got := hasWebScheme(rawURL); got != want {
+			t.Errorf("hasWebScheme(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Main content extraction methods recorded in MainContent.Method
+const (
+	MainContentLandmark = "main"
+	MainContentArticle  = "article"
+	MainContentScored   = "scored"
+	MainContentBody     = "body"
+)
+
+// minTextToHTMLRatio is the main content text share of the HTML, in percent, below which a page
+// is reported as mostly markup. Typical content pages land between 2% and 10%.
+const minTextToHTMLRatio = 1.5
+
+// MainContent is the primary content of the page, separated from navigation, footers and banners
+type MainContent struct {
+	Method           string  `json:"method"`   // How the content root was found: main, article, scored or body
+	Selector         string  `json:"selector"` // CSS selector of the content root
+	Text             string  `json:"text"`
+	WordCount        int     `json:"word_count"`
+	TextToHTMLRatio  float64 `json:"text_to_html_ratio"` // Main content text bytes as a percentage of the HTML bytes
+	BoilerplateShare float64 `json:"boilerplate_share"`  // Share of the visible page text outside the main content, 0-1
+
+	paragraphs []string
+}
+
+// mainContentFunction is a JavaScript function that finds the main content of the page.
+// A single visible main landmark or article is used when it holds real text; otherwise
+// paragraphs vote for their ancestors readability-style and the best scoring block wins.
+const mainContentFunction = `() => {
+	const html = document.documentElement ? document.documentElement.outerHTML : '';
+	const result = { method: 'body', selector: 'body', blocks: [], text: '', htmlBytes: new TextEncoder().encode(html).length, pageTextLength: 0 };
+	const body = document.body;
+	if (!body) return result;
+
+	const textOf = (el) => (el.innerText || el.textContent || '').trim().replace(/\s+/g, ' ');
+	const isVisible = (el) => {
+		const style = window.getComputedStyle(el);
+		return style.display !== 'none' && style.visibility !== 'hidden';
+	};
+	const linkDensity = (el) => {
+		const length = textOf(el).length || 1;
+		let links = 0;
+		el.querySelectorAll('a').forEach(a => links += textOf(a).length);
+		return links / length;
+	};
+	const boilerplate = 'nav, header, footer, aside, form, dialog, [role="navigation"], [role="banner"], [role="contentinfo"], [role="complementary"], [role="dialog"], [aria-modal="true"], [id*="cookie" i], [class*="cookie" i], [id*="consent" i], [class*="consent" i]';
+	const negative = /comment|footer|footnote|masthead|outbrain|promo|related|share|shoutbox|sidebar|sponsor|shopping|tags|tool|widget|nav|menu|cookie|consent|banner|breadcrumb|newsletter|subscribe|popup|modal/i;
+	const positive = /article|body|content|entry|hentry|main|page|post|text|blog|story|prose/i;
+	const classWeight = (el) => {
+		const names = (typeof el.className === 'string' ? el.className : '') + ' ' + (el.id || '');
+		return (negative.test(names) ? -25 : 0) + (positive.test(names) ? 25 : 0);
+	};
+	const tagWeight = { ARTICLE: 10, MAIN: 10, SECTION: 5, DIV: 5, PRE: 3, TD: 3, BLOCKQUOTE: 3, FORM: -3, UL: -3, OL: -3, DL: -3, ADDRESS: -3, TH: -5, H1: -5, H2: -5, H3: -5, H4: -5, H5: -5, H6: -5 };
+
+	result.pageTextLength = textOf(body).length;
+
+	let root = null;
+	const landmarks = Array.from(document.querySelectorAll('main, [role="main"]')).filter(isVisible);
+	const articles = Array.from(document.querySelectorAll('article')).filter(isVisible);
+	if (landmarks.length === 1 && textOf(landmarks[0]).length >= 200) {
+		root = landmarks[0];
+		result.method = 'main';
+	} else if (articles.length === 1 && textOf(articles[0]).length >= 200) {
+		root = articles[0];
+		result.method = 'article';
+	}
+
+	if (!root) {
+		const scores = new Map();
+		const vote = (el, points) => {
+			if (!el || el === document.documentElement) return;
+			if (!scores.has(el)) scores.set(el, (tagWeight[el.tagName] || 0) + classWeight(el));
+			scores.set(el, scores.get(el) + points);
+		};
+		body.querySelectorAll('p, pre, td, blockquote').forEach(p => {
+			if (p.closest(boilerplate) || !isVisible(p)) return;
+			const text = textOf(p);
+			if (text.length < 25) return;
+			const points = 1 + text.split(/[,，、]/).length - 1 + Math.min(Math.floor(text.length / 100), 3);
+			vote(p.parentElement, points);
+			if (p.parentElement) vote(p.parentElement.parentElement, points / 2);
+		});
+		let bestScore = 0;
+		scores.forEach((score, el) => {
+			const adjusted = score * (1 - linkDensity(el));
+			if (adjusted > bestScore) {
+				root = el;
+				bestScore = adjusted;
+			}
+		});
+		if (root) result.method = 'scored';
+	}
+	if (!root) root = body;
+
+	const selectorOf = ` + cssSelectorFunction + `;
+	result.selector = selectorOf(root);
+
+	// Keep the text blocks of the root, dropping boilerplate nested inside it and link lists
+	const blockSelector = 'p, li, blockquote, pre, dd, figcaption, h1, h2, h3, h4, h5, h6';
+	Array.from(root.querySelectorAll(blockSelector)).forEach(el => {
+		const container = el.closest(boilerplate);
+		if (container && container !== root && root.contains(container)) return;
+		if (el.querySelector('p, li, blockquote, pre')) return;
+		if (!isVisible(el)) return;
+		const text = textOf(el);
+		if (!text) return;
+		if (el.tagName === 'LI' && text.length < 200 && linkDensity(el) > 0.5) return;
+		result.blocks.push({ text: text, heading: /^H[1-6]$/.test(el.tagName) });
+	});
+
+	if (result.blocks.length) {
+		result.text = result.blocks.map(b => b.text).join('\n');
+	} else {
+		result.text = (root.innerText || root.textContent || '').trim();
+		result.blocks = result.text.split(/\n+/).map(s => s.trim()).filter(Boolean).map(t => ({ text: t, heading: false }));
+	}
+	return result;
+}`
+
+// mainContentResult is what mainContentFunction returns
+type mainContentResult struct {
+	Method         string `json:"method"`
+	Selector       string `json:"selector"`
+	Text           string `json:"text"`
+	HTMLBytes      int    `json:"htmlBytes"`
+	PageTextLength int    `json:"pageTextLength"`
+	Blocks         []struct {
+		Text    string `json:"text"`
+		Heading bool   `json:"heading"`
+	} `json:"blocks"`
+}
+
+// extractMainContent finds the main content of the page, falling back to the whole body text
+func extractMainContent(page playwright.Page, fallbackText string) MainContent {
+	var result mainContentResult
+	if err := evaluateInto(page, mainContentFunction, &result); err != nil {
+		result = mainContentResult{Method: MainContentBody, Selector: "body", Text: fallbackText, PageTextLength: len(fallbackText)}
+	}
+	return newMainContent(result)
+}
+
+// newMainContent builds the main content report from the extraction result
+func newMainContent(result mainContentResult) MainContent {
+	content := MainContent{
+		Method:     result.Method,
+		Selector:   result.Selector,
+		Text:       result.Text,
+		paragraphs: []string{},
+	}
+	for _, block := range result.Blocks {
+		// Headings count as content but aren't prose, so readability skips them
+		if !block.Heading {
+			content.paragraphs = append(content.paragraphs, block.Text)
+		}
+	}
+	if len(content.paragraphs) == 0 && content.Text != "" {
+		content.paragraphs = strings.Split(content.Text, "\n")
+	}
+
+	if result.HTMLBytes > 0 {
+		content.TextToHTMLRatio = math.Round(float64(len(content.Text))/float64(result.HTMLBytes)*1000) / 10
+	}
+	if result.PageTextLength > 0 {
+		content.BoilerplateShare = math.Round(math.Max(0, 1-float64(len([]rune(content.Text)))/float64(result.PageTextLength))*100) / 100
+	}
+	return content
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewMainContent(t *testing.T) {
+	type block = struct {
+		Text    string `json:"text"`
+		Heading bool   `json:"heading"`
+	}
+	tests := []struct {
+		name           string
+		result         mainContentResult
+		wantParagraphs []string
+		wantRatio      float64
+		wantShare      float64
+	}{
+		{
+			name: "headings are skipped for readability",
+			result: mainContentResult{
+				Method: MainContentLandmark, Selector: "main",
+				Text:      "Title\nFirst paragraph.\nSecond paragraph.",
+				HTMLBytes: 400, PageTextLength: 80,
+				Blocks: []block{{"Title", true}, {"First paragraph.", false}, {"Second paragraph.", false}},
+			},
+			wantParagraphs: []string{"First paragraph.", "Second paragraph."},
+			wantRatio:      10,
+			wantShare:      0.5,
+		},
+		{
+			name: "text lines when there are no blocks",
+			result: mainContentResult{
+				Method: MainContentBody, Selector: "body",
+				Text: "One\nTwo", PageTextLength: 7,
+			},
+			wantParagraphs: []string{"One", "Two"},
+		},
+		{
+			name:           "empty page",
+			result:         mainContentResult{Method: MainContentBody, Selector: "body"},
+			wantParagraphs: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := newMainContent(tt.result)
+			if content.Method != tt.result.Method || content.Selector != tt.result.Selector {
+				t.Errorf("method, selector = %q, %q", content.Method, content.Selector)
+			}
+			if !reflect.DeepEqual(content.paragraphs, tt.wantParagraphs) {
+				t.Errorf("paragraphs = %q, want %q", content.paragraphs, tt.wantParagraphs)
+			}
+			if content.TextToHTMLRatio != tt.wantRatio {
+				t.Errorf("TextToHTMLRatio = %v, want %v", content.TextToHTMLRatio, tt.wantRatio)
+			}
+			if content.BoilerplateShare != tt.wantShare {
+				t.Errorf("BoilerplateShare = %v, want %v", content.BoilerplateShare, tt.wantShare)
+			}
+		})
+	}
+}
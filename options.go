@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Consent actions accepted in AuditOptions.Consent
+const (
+	ConsentNone   = ""
+	ConsentAccept = "accept"
+	ConsentReject = "reject"
+)
+
+// AuditOptions controls how the target page is prepared before the checks run
+type AuditOptions struct {
+	Consent                 string            `json:"consent"`                    // "", "accept" or "reject"
+	ReportPreConsentCookies bool              `json:"report_pre_consent_cookies"` // List cookies set before any consent was given
+	HTTPCredentials         *HTTPCredentials  `json:"http_credentials"`           // HTTP basic auth credentials
+	Headers                 map[string]string `json:"headers"`                    // Extra headers sent to the audited host
+	Cookies                 []AuditCookie     `json:"cookies"`                    // Cookies added to the browser context
+	Login                   *LoginStep        `json:"login"`                      // Login form submitted once before navigation
+	UserAgent               string            `json:"user_agent"`                 // Custom user agent string
+	RenderAsGooglebot       bool              `json:"render_as_googlebot"`        // Render with the Googlebot Smartphone preset
+	CompareWithGooglebot    bool              `json:"compare_with_googlebot"`     // Also render as Googlebot and report differences
+	Keywords                []string          `json:"keywords"`                   // Target keywords for on-page analysis
+}
+
+// HTTPCredentials holds HTTP basic auth credentials
+type HTTPCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// AuditCookie is a cookie added to the browser context before navigation.
+// When neither URL nor Domain is set the cookie is scoped to the audited URL.
+type AuditCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	URL      string `json:"url"`
+	Domain   string `json:"domain"`
+	Path     string `json:"path"`
+	Secure   bool   `json:"secure"`
+	HTTPOnly bool   `json:"http_only"`
+}
+
+// LoginStep describes a scripted login form submission
+type LoginStep struct {
+	URL     string       `json:"url"`      // Login page, defaults to the audited URL
+	Fields  []LoginField `json:"fields"`   // Inputs to fill in order
+	Submit  string       `json:"submit"`   // Selector of the element to click to submit the form
+	WaitFor string       `json:"wait_for"` // Optional selector that appears once logged in
+}
+
+// LoginField is a single input filled during the login step
+type LoginField struct {
+	Selector string `json:"selector"`
+	Value    string `json:"value"`
+}
+
+// Validate checks the options for unsupported values
+func (o AuditOptions) Validate() error {
+	switch o.Consent {
+	case ConsentNone, ConsentAccept, ConsentReject:
+	default:
+		return fmt.Errorf("invalid consent action %q (expected %q or %q)", o.Consent, ConsentAccept, ConsentReject)
+	}
+
+	for _, cookie := range o.Cookies {
+		if cookie.Name == "" {
+			return fmt.Errorf("cookie name is required")
+		}
+	}
+
+	if len(o.Keywords) > maxKeywords {
+		return fmt.Errorf("too many keywords (%d, at most %d)", len(o.Keywords), maxKeywords)
+	}
+
+	if o.Login != nil {
+		if len(o.Login.Fields) == 0 && o.Login.Submit == "" {
+			return fmt.Errorf("login step needs fields or a submit selector")
+		}
+		for _, field := range o.Login.Fields {
+			if field.Selector == "" {
+				return fmt.Errorf("login field selector is required")
+			}
+		}
+	}
+
+	return nil
+}
+
+// newBrowserContext creates an isolated browser context configured with the request options
+func (a *SEOAuditor) newBrowserContext(targetURL string, opts AuditOptions) (playwright.BrowserContext, error) {
+	contextOptions := playwright.BrowserNewContextOptions{}
+	if opts.HTTPCredentials != nil {
+		contextOptions.HttpCredentials = &playwright.HttpCredentials{
+			Username: opts.HTTPCredentials.Username,
+			Password: opts.HTTPCredentials.Password,
+		}
+		// Without an origin Chromium answers auth challenges from any host with the credentials
+		if origin := targetOrigin(targetURL); origin != "" {
+			contextOptions.HttpCredentials.Origin = playwright.String(origin)
+		}
+	}
+	if opts.RenderAsGooglebot {
+		applyGooglebotPreset(&contextOptions)
+	} else if opts.UserAgent != "" {
+		contextOptions.UserAgent = playwright.String(opts.UserAgent)
+	}
+
+	context, err := a.browser.NewContext(contextOptions)
+	if err != nil {
+		return nil, fmt.Errorf("could not create browser context: %v", err)
+	}
+
+	// Custom headers often carry secrets, so they only go to the audited host, never to third parties
+	if len(opts.Headers) > 0 {
+		if err := context.Route("**/*", targetHeadersHandler(targetHost(targetURL), opts.Headers)); err != nil {
+			context.Close()
+			return nil, fmt.Errorf("could not configure headers: %v", err)
+		}
+	}
+
+	if opts.RenderAsGooglebot {
+		if err := context.AddInitScript(playwright.Script{Content: playwright.String(googlebotStorageScript)}); err != nil {
+			context.Close()
+			return nil, fmt.Errorf("could not configure Googlebot storage: %v", err)
+		}
+	}
+
+	if len(opts.Cookies) > 0 {
+		cookies := make([]playwright.OptionalCookie, 0, len(opts.Cookies))
+		for _, c := range opts.Cookies {
+			cookie := playwright.OptionalCookie{
+				Name:     c.Name,
+				Value:    c.Value,
+				Secure:   playwright.Bool(c.Secure),
+				HttpOnly: playwright.Bool(c.HTTPOnly),
+			}
+			switch {
+			case c.Domain != "":
+				cookie.Domain = playwright.String(c.Domain)
+				path := c.Path
+				if path == "" {
+					path = "/"
+				}
+				cookie.Path = playwright.String(path)
+			case c.URL != "":
+				cookie.URL = playwright.String(c.URL)
+			default:
+				cookie.URL = playwright.String(targetURL)
+			}
+			cookies = append(cookies, cookie)
+		}
+		if err := context.AddCookies(cookies); err != nil {
+			context.Close()
+			return nil, fmt.Errorf("could not add cookies: %v", err)
+		}
+	}
+
+	if opts.Login != nil {
+		if err := a.login(context, targetURL, opts.Login); err != nil {
+			context.Close()
+			return nil, err
+		}
+	}
+
+	return context, nil
+}
+
+// targetHeadersHandler adds the custom headers to requests for the audited host and lets every
+// other request through unchanged
+func targetHeadersHandler(host string, headers map[string]string) func(playwright.Route) {
+	return func(route playwright.Route) {
+		request := route.Request()
+		if u, err := url.Parse(request.URL()); err != nil || u.Hostname() != host {
+			route.Continue()
+			return
+		}
+		merged, err := request.AllHeaders()
+		if err != nil {
+			merged = request.Headers()
+		}
+		for name, value := range headers {
+			merged[strings.ToLower(name)] = value
+		}
+		route.Continue(playwright.RouteContinueOptions{Headers: merged})
+	}
+}
+
+// targetOrigin returns the scheme://host[:port] of the audited URL, or "" when it has no host
+func targetOrigin(targetURL string) string {
+	parsedURL, err := url.Parse(targetURL)
+	if err != nil || parsedURL.Host == "" {
+		return ""
+	}
+	return parsedURL.Scheme + "://" + parsedURL.Host
+}
+
+// targetHost returns the host name of the audited URL, or "" when it can't be parsed
+func targetHost(targetURL string) string {
+	if parsedURL, err := url.Parse(targetURL); err == nil {
+		return parsedURL.Hostname()
+	}
+	return ""
+}
+
+// login submits the login form on a throwaway page so the session is stored in the context
+func (a *SEOAuditor) login(context playwright.BrowserContext, targetURL string, step *LoginStep) error {
+	page, err := context.NewPage()
+	if err != nil {
+		return fmt.Errorf("could not create login page: %v", err)
+	}
+	defer page.Close()
+
+	loginURL := step.URL
+	if loginURL == "" {
+		loginURL = targetURL
+	}
+
+	_, err = page.Goto(loginURL, playwright.PageGotoOptions{
+		WaitUntil: playwright.WaitUntilStateNetworkidle,
+		Timeout:   playwright.Float(30000),
+	})
+	if err != nil {
+		return fmt.Errorf("could not navigate to login page: %v", err)
+	}
+
+	for _, field := range step.Fields {
+		if err := page.Locator(field.Selector).First().Fill(field.Value); err != nil {
+			return fmt.Errorf("could not fill login field %q: %v", field.Selector, err)
+		}
+	}
+
+	if step.Submit != "" {
+		if err := page.Locator(step.Submit).First().Click(); err != nil {
+			return fmt.Errorf("could not submit login form: %v", err)
+		}
+	}
+
+	if step.WaitFor != "" {
+		err = page.Locator(step.WaitFor).First().WaitFor(playwright.LocatorWaitForOptions{
+			Timeout: playwright.Float(30000),
+		})
+		if err != nil {
+			return fmt.Errorf("login did not complete (waiting for %q): %v", step.WaitFor, err)
+		}
+	} else {
+		page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+			State:   playwright.LoadStateNetworkidle,
+			Timeout: playwright.Float(30000),
+		})
+	}
+
+	return nil
+}
+
+// applyToRequest adds the credentials, headers and matching cookies to a plain HTTP request
+// so that robots.txt, sitemap and similar checks reach protected hosts too
+func (o AuditOptions) applyToRequest(req *http.Request, targetURL string) {
+	host := targetHost(targetURL)
+
+	// Credentials and headers are only meant for the audited host, never for third parties
+	if o.HTTPCredentials != nil && req.URL.Hostname() == host {
+		req.SetBasicAuth(o.HTTPCredentials.Username, o.HTTPCredentials.Password)
+	}
+	if o.RenderAsGooglebot {
+		req.Header.Set("User-Agent", googlebotSmartphoneUserAgent)
+	} else if o.UserAgent != "" {
+		req.Header.Set("User-Agent", o.UserAgent)
+	}
+	if req.URL.Hostname() == host {
+		for name, value := range o.Headers {
+			req.Header.Set(name, value)
+		}
+	}
+	for _, cookie := range o.Cookies {
+		if cookieMatchesURL(cookie, req.URL, host) {
+			req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+		}
+	}
+}
+
+// cookieMatchesURL reports whether a configured cookie would be sent to the given URL
+func cookieMatchesURL(cookie AuditCookie, u *url.URL, targetHost string) bool {
+	host := u.Hostname()
+	switch {
+	case cookie.Domain != "":
+		domain := strings.TrimPrefix(cookie.Domain, ".")
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+		return cookie.Path == "" || strings.HasPrefix(u.Path, cookie.Path)
+	case cookie.URL != "":
+		cookieURL, err := url.Parse(cookie.URL)
+		return err == nil && cookieURL.Hostname() == host
+	default:
+		return host == targetHost
+	}
+}
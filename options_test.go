@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestAuditOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    AuditOptions
+		wantErr bool
+	}{
+		{"no consent action", AuditOptions{}, false},
+		{"accept", AuditOptions{Consent: ConsentAccept}, false},
+		{"reject", AuditOptions{Consent: ConsentReject}, false},
+		{"unknown action", AuditOptions{Consent: "ignore"}, true},
+		{"wrong case", AuditOptions{Consent: "Accept"}, true},
+		{"cookie without name", AuditOptions{Cookies: []AuditCookie{{Value: "1"}}}, true},
+		{"empty login step", AuditOptions{Login: &LoginStep{URL: "https://example.com/login"}}, true},
+		{"login field without selector", AuditOptions{Login: &LoginStep{Fields: []LoginField{{Value: "ada"}}}}, true},
+		{"login with submit only", AuditOptions{Login: &LoginStep{Submit: "button"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCookieMatchesURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie AuditCookie
+		url    string
+		want   bool
+	}{
+		{"default scope on the audited host", AuditCookie{Name: "s"}, "https://example.com/robots.txt", true},
+		{"default scope on another host", AuditCookie{Name: "s"}, "https://cdn.example.net/a.js", false},
+		{"default scope on a subdomain", AuditCookie{Name: "s"}, "https://www.example.com/", false},
+		{"domain on a subdomain", AuditCookie{Name: "s", Domain: ".example.com"}, "https://www.example.com/", true},
+		{"domain on a look-alike host", AuditCookie{Name: "s", Domain: "example.com"}, "https://notexample.com/", false},
+		{"domain with a matching path", AuditCookie{Name: "s", Domain: "example.com", Path: "/shop"}, "https://example.com/shop/cart", true},
+		{"domain with another path", AuditCookie{Name: "s", Domain: "example.com", Path: "/shop"}, "https://example.com/blog", false},
+		{"URL on the same host", AuditCookie{Name: "s", URL: "https://api.example.com/"}, "https://api.example.com/v1", true},
+		{"URL on another host", AuditCookie{Name: "s", URL: "https://api.example.com/"}, "https://example.com/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.url)
+			if got := cookieMatchesURL(tt.cookie, u, "example.com"); got != tt.want {
+				t.Errorf("cookieMatchesURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyToRequest(t *testing.T) {
+	opts := AuditOptions{
+		HTTPCredentials: &HTTPCredentials{Username: "ada", Password: "secret"},
+		Headers:         map[string]string{"X-Api-Key": "secret"},
+		Cookies:         []AuditCookie{{Name: "session", Value: "abc"}},
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantAuth bool
+	}{
+		{"audited host", "https://example.com/sitemap.xml", true},
+		{"third-party host", "https://cdn.example.net/image.jpg", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			opts.applyToRequest(req, "https://example.com/page")
+
+			if _, _, ok := req.BasicAuth(); ok != tt.wantAuth {
+				t.Errorf("basic auth sent = %v, want %v", ok, tt.wantAuth)
+			}
+			if got := req.Header.Get("X-Api-Key") != ""; got != tt.wantAuth {
+				t.Errorf("custom header sent = %v, want %v", got, tt.wantAuth)
+			}
+			if _, err := req.Cookie("session"); (err == nil) != tt.wantAuth {
+				t.Errorf("session cookie sent = %v, want %v", err == nil, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestApplyToRequestUserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		opts AuditOptions
+		want string
+	}{
+		{"custom user agent", AuditOptions{UserAgent: "TestBot/1.0"}, "TestBot/1.0"},
+		{"Googlebot preset wins", AuditOptions{UserAgent: "TestBot/1.0", RenderAsGooglebot: true}, googlebotSmartphoneUserAgent},
+		{"default", AuditOptions{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "https://example.com/robots.txt", nil)
+			tt.opts.applyToRequest(req, "https://example.com/")
+			if got := req.Header.Get("User-Agent"); got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetHost(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com:8443/page": "example.com",
+		"https://example.com/":          "example.com",
+		"about:blank":                   "",
+		"%":                             "",
+	}
+	for targetURL, want := range tests {
+		if got := targetHost(targetURL); got != want {
+			t.Errorf("targetHost(%q) = %q, want %q", targetURL, got, want)
+		}
+	}
+}
+
+func TestTargetOrigin(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/page?q=1":  "https://example.com",
+		"http://example.com:8080/login": "http://example.com:8080",
+		"about:blank":                   "",
+		"%":                             "",
+	}
+	for targetURL, want := range tests {
+		if got := targetOrigin(targetURL); got != want {
+			t.Errorf("targetOrigin(%q) = %q, want %q", targetURL, got, want)
+		}
+	}
+}
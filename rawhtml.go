@@ -0,0 +1,254 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxRawHTMLSize caps how much of the server response is read
+const maxRawHTMLSize = 10 * 1024 * 1024
+
+// RawHTMLComparison reports what crawlers that don't run JavaScript see compared to the rendered DOM
+type RawHTMLComparison struct {
+	StatusCode        int                 `json:"status_code"`
+	Size              int64               `json:"size_bytes"`
+	RawWordCount      int                 `json:"raw_word_count"`
+	RenderedWordCount int                 `json:"rendered_word_count"`
+	ContentSimilarity float64             `json:"content_similarity"` // 0-1 similarity of the main content
+	Elements          []ElementComparison `json:"elements"`
+	RenderedOnly      []string            `json:"rendered_only"` // Elements that only exist after JavaScript runs
+	Issues            []string            `json:"issues"`
+}
+
+// ElementComparison compares one SEO-critical element between the server HTML and the rendered DOM
+type ElementComparison struct {
+	Element       string `json:"element"`
+	InRawHTML     bool   `json:"in_raw_html"`
+	InRendered    bool   `json:"in_rendered"`
+	Differs       bool   `json:"differs"`
+	RawValue      string `json:"raw_value"`
+	RenderedValue string `json:"rendered_value"`
+}
+
+// rawHTMLResponse is the unrendered server response for a URL
+type rawHTMLResponse struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// fetchRawHTML downloads the server HTML without running any JavaScript. Credentials, headers and
+// cookies from the options are only sent when targetURL is on the same host as scopeURL, the audited URL.
+func (a *SEOAuditor) fetchRawHTML(targetURL, scopeURL string, opts AuditOptions, userAgent string) (*rawHTMLResponse, error) {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Ask as the same browser that rendered the page, unless the options say otherwise
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	opts.applyToRequest(req, scopeURL)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRawHTMLSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &rawHTMLResponse{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, nil
+}
+
+// snapshotRawHTML parses server HTML in a browser with JavaScript disabled and extracts its snapshot.
+// The document is served from memory at its own URL so relative links resolve as they would for a crawler.
+func (a *SEOAuditor) snapshotRawHTML(raw *rawHTMLResponse) (pageSnapshot, error) {
+	context, err := a.browser.NewContext(playwright.BrowserNewContextOptions{
+		JavaScriptEnabled: playwright.Bool(false),
+	})
+	if err != nil {
+		return pageSnapshot{}, fmt.Errorf("could not create browser context: %v", err)
+	}
+	defer context.Close()
+
+	page, err := context.NewPage()
+	if err != nil {
+		return pageSnapshot{}, fmt.Errorf("could not create page: %v", err)
+	}
+	defer page.Close()
+
+	err = page.Route("**/*", func(route playwright.Route) {
+		// Only the top-level document is served; iframes would otherwise load the page itself again
+		if request := route.Request(); request.IsNavigationRequest() && request.Frame() == page.MainFrame() {
+			route.Fulfill(playwright.RouteFulfillOptions{
+				Status:      playwright.Int(200),
+				ContentType: playwright.String("text/html; charset=utf-8"),
+				Body:        raw.Body,
+			})
+			return
+		}
+		route.Abort()
+	})
+	if err != nil {
+		return pageSnapshot{}, fmt.Errorf("could not intercept requests: %v", err)
+	}
+
+	_, err = page.Goto(raw.URL, playwright.PageGotoOptions{
+		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+		Timeout:   playwright.Float(30000),
+	})
+	if err != nil {
+		return pageSnapshot{}, fmt.Errorf("could not load raw HTML: %v", err)
+	}
+
+	return takeSnapshot(page)
+}
+
+// compareRawHTML fetches the unrendered server HTML and compares it with the rendered DOM snapshot
+func (a *SEOAuditor) compareRawHTML(targetURL string, opts AuditOptions, userAgent string, rendered pageSnapshot) (*RawHTMLComparison, error) {
+	raw, err := a.fetchRawHTML(targetURL, targetURL, opts, userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch raw HTML: %v", err)
+	}
+
+	rawSnapshot, err := a.snapshotRawHTML(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &RawHTMLComparison{
+		StatusCode:   raw.StatusCode,
+		Size:         int64(len(raw.Body)),
+		Elements:     []ElementComparison{},
+		RenderedOnly: []string{},
+		Issues:       []string{},
+	}
+
+	addElement := func(element, rawValue, renderedValue string, differs bool) {
+		item := ElementComparison{
+			Element:       element,
+			InRawHTML:     rawValue != "",
+			InRendered:    renderedValue != "",
+			Differs:       differs,
+			RawValue:      rawValue,
+			RenderedValue: renderedValue,
+		}
+		comparison.Elements = append(comparison.Elements, item)
+
+		switch {
+		case !item.InRawHTML && item.InRendered:
+			comparison.RenderedOnly = append(comparison.RenderedOnly, element)
+			comparison.Issues = append(comparison.Issues, fmt.Sprintf("%s only exists after JavaScript runs", capitalize(element)))
+		case item.InRawHTML && !item.InRendered:
+			comparison.Issues = append(comparison.Issues, fmt.Sprintf("%s is removed by JavaScript", capitalize(element)))
+		case differs:
+			comparison.Issues = append(comparison.Issues, fmt.Sprintf("%s changes after JavaScript runs", capitalize(element)))
+		}
+	}
+
+	compareValue := func(element, rawValue, renderedValue string) {
+		addElement(element, rawValue, renderedValue, normalizeText(rawValue) != normalizeText(renderedValue))
+	}
+
+	compareList := func(element string, rawItems, renderedItems []string) {
+		added, removed := diffStringSets(rawItems, renderedItems)
+		rawValue, renderedValue := "", ""
+		if len(rawItems) > 0 {
+			rawValue = fmt.Sprintf("%d", len(rawItems))
+		}
+		if len(renderedItems) > 0 {
+			renderedValue = fmt.Sprintf("%d", len(renderedItems))
+			if added > 0 {
+				renderedValue += fmt.Sprintf(" (%d only after JavaScript)", added)
+			}
+		}
+		addElement(element, rawValue, renderedValue, added > 0 || removed > 0)
+	}
+
+	compareValue("title", rawSnapshot.Title, rendered.Title)
+	compareValue("meta description", rawSnapshot.MetaDescription, rendered.MetaDescription)
+	compareValue("canonical", rawSnapshot.Canonical, rendered.Canonical)
+	compareValue("robots meta", rawSnapshot.RobotsMeta, rendered.RobotsMeta)
+	compareValue("h1", strings.Join(rawSnapshot.H1, " | "), strings.Join(rendered.H1, " | "))
+	compareList("headings", rawSnapshot.Headings, rendered.Headings)
+	compareList("links", rawSnapshot.Links, rendered.Links)
+	compareList("JSON-LD blocks", normalizeAll(rawSnapshot.JSONLD), normalizeAll(rendered.JSONLD))
+
+	comparison.RawWordCount = len(strings.Fields(rawSnapshot.MainText))
+	comparison.RenderedWordCount = len(strings.Fields(rendered.MainText))
+	comparison.ContentSimilarity = math.Round(contentSimilarity(rawSnapshot.MainText, rendered.MainText)*100) / 100
+
+	rawText, renderedText := "", ""
+	if comparison.RawWordCount > 0 {
+		rawText = fmt.Sprintf("%d words", comparison.RawWordCount)
+	}
+	if comparison.RenderedWordCount > 0 {
+		renderedText = fmt.Sprintf("%d words", comparison.RenderedWordCount)
+	}
+	addElement("main text", rawText, renderedText, comparison.ContentSimilarity < minContentSimilarity)
+
+	return comparison, nil
+}
+
+// diffStringSets counts the items only present in b (added) and only present in a (removed)
+func diffStringSets(a, b []string) (added, removed int) {
+	inA := map[string]bool{}
+	for _, item := range a {
+		inA[item] = true
+	}
+	inB := map[string]bool{}
+	for _, item := range b {
+		inB[item] = true
+	}
+	for item := range inB {
+		if !inA[item] {
+			added++
+		}
+	}
+	for item := range inA {
+		if !inB[item] {
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// normalizeAll applies normalizeText to every item
+func normalizeAll(items []string) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = normalizeText(item)
+	}
+	return result
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDiffStringSets(t *testing.T) {
+	tests := []struct {
+		name                   string
+		a, b                   []string
+		wantAdded, wantRemoved int
+	}{
+		{"same", []string{"a", "b"}, []string{"b", "a"}, 0, 0},
+		{"added by JavaScript", []string{"a"}, []string{"a", "b", "c"}, 2, 0},
+		{"removed by JavaScript", []string{"a", "b"}, []string{"a"}, 0, 1},
+		{"duplicates count once", []string{"a", "a"}, []string{"b", "b"}, 1, 1},
+		{"both empty", nil, nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := diffStringSets(tt.a, tt.b)
+			if added != tt.wantAdded || removed != tt.wantRemoved {
+				t.Errorf("diffStringSets() = %d, %d, want %d, %d", added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestNormalizeAll(t *testing.T) {
+	got := normalizeAll([]string{" Home ", "About  Us"})
+	want := []string{"home", "about us"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeAll() = %q, want %q", got, want)
+	}
+}
+
+func TestCapitalize(t *testing.T) {
+	tests := map[string]string{
+		"":      "",
+		"title": "Title",
+		"H1":    "H1",
+	}
+	for input, want := range tests {
+		if got := capitalize(input); got != want {
+			t.Errorf("capitalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestFetchRawHTMLScopesCredentials(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	opts := AuditOptions{
+		HTTPCredentials: &HTTPCredentials{Username: "ada", Password: "secret"},
+		Headers:         map[string]string{"X-Api-Key": "secret"},
+		Cookies:         []AuditCookie{{Name: "session", Value: "abc"}},
+	}
+	tests := []struct {
+		name     string
+		scopeURL string
+		wantAuth bool
+	}{
+		{"audited host", server.URL + "/page", true},
+		{"alternate on another host", "https://example.com/page", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &SEOAuditor{}
+			if _, err := a.fetchRawHTML(server.URL+"/de/", tt.scopeURL, opts, "test"); err != nil {
+				t.Fatalf("fetchRawHTML() error: %v", err)
+			}
+			if _, _, ok := got.BasicAuth(); ok != tt.wantAuth {
+				t.Errorf("basic auth sent = %v, want %v", ok, tt.wantAuth)
+			}
+			if sent := got.Header.Get("X-Api-Key") != ""; sent != tt.wantAuth {
+				t.Errorf("custom header sent = %v, want %v", sent, tt.wantAuth)
+			}
+			if _, err := got.Cookie("session"); (err == nil) != tt.wantAuth {
+				t.Errorf("session cookie sent = %v, want %v", err == nil, tt.wantAuth)
+			}
+		})
+	}
+}
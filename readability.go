@@ -0,0 +1,526 @@
+package main
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minParagraphWords is the shortest paragraph that gets its own readability score
+const minParagraphWords = 20
+
+// maxHardParagraphs is how many of the hardest paragraphs are reported
+const maxHardParagraphs = 3
+
+// ReadabilityReport holds readability metrics computed from real syllable and sentence counts
+type ReadabilityReport struct {
+	Language         string  `json:"language"`
+	Formula          string  `json:"formula"`      // Reading ease formula used for the language, empty when none applies
+	ReadingEase      float64 `json:"reading_ease"` // 0-100 scale, higher is easier
+	Words            int     `json:"words"`
+	Sentences        int     `json:"sentences"`
+	Syllables        int     `json:"syllables"`
+	ComplexWords     int     `json:"complex_words"` // Words with three or more syllables
+	WordsPerSentence float64 `json:"words_per_sentence"`
+	SyllablesPerWord float64 `json:"syllables_per_word"`
+	// Grade-level formulas are calibrated for English and only reported for English content
+	FleschReadingEase  float64                `json:"flesch_reading_ease,omitempty"`
+	FleschKincaidGrade float64                `json:"flesch_kincaid_grade,omitempty"`
+	GunningFog         float64                `json:"gunning_fog,omitempty"`
+	SMOG               float64                `json:"smog,omitempty"`
+	ColemanLiau        float64                `json:"coleman_liau,omitempty"`
+	HardestParagraphs  []ParagraphReadability `json:"hardest_paragraphs"`
+}
+
+// ParagraphReadability scores a single paragraph
+type ParagraphReadability struct {
+	Text               string  `json:"text"`
+	Words              int     `json:"words"`
+	Sentences          int     `json:"sentences"`
+	ReadingEase        float64 `json:"reading_ease"`
+	FleschKincaidGrade float64 `json:"flesch_kincaid_grade,omitempty"`
+}
+
+// textStats holds the raw counts the readability formulas are built from
+type textStats struct {
+	words, sentences, syllables, complexWords, polysyllables, letters int
+}
+
+// readingEaseFormulas are the Flesch adaptations for each supported language.
+// asl is words per sentence, asw syllables per word, and standard the score
+// at or above which text reads comfortably for a general audience.
+var readingEaseFormulas = map[string]struct {
+	name     string
+	standard float64
+	formula  func(asl, asw float64) float64
+}{
+	"en": {"Flesch Reading Ease", 60, func(asl, asw float64) float64 { return 206.835 - 1.015*asl - 84.6*asw }},
+	"es": {"Fernández Huerta", 60, func(asl, asw float64) float64 { return 206.84 - 60*asw - 102/asl }},
+	"pt": {"Flesch (Martins et al.)", 50, func(asl, asw float64) float64 { return 248.835 - 1.015*asl - 84.6*asw }},
+	"de": {"Amstad", 60, func(asl, asw float64) float64 { return 180 - asl - 58.5*asw }},
+	"fr": {"Kandel-Moles", 60, func(asl, asw float64) float64 { return 207 - 1.015*asl - 73.6*asw }},
+	"it": {"Flesch-Vacca", 60, func(asl, asw float64) float64 { return 217 - 1.3*asl - 60*asw }},
+	"nl": {"Flesch-Douma", 60, func(asl, asw float64) float64 { return 206.835 - 0.93*asl - 77*asw }},
+}
+
+// readsComfortably reports whether the reading ease meets the standard of the report's formula
+func (r ReadabilityReport) readsComfortably() bool {
+	formula, ok := readingEaseFormulas[r.Language]
+	return !ok || r.ReadingEase >= formula.standard
+}
+
+// commonAbbreviations end with a period that doesn't end the sentence
+var commonAbbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true, "st": true,
+	"vs": true, "etc": true, "e.g": true, "i.e": true, "cf": true, "al": true, "approx": true, "ca": true,
+	"inc": true, "ltd": true, "co": true, "corp": true, "dept": true, "est": true, "fig": true, "figs": true,
+	"no": true, "nos": true, "vol": true, "p": true, "pp": true, "ed": true, "eds": true, "gen": true,
+	"gov": true, "rev": true, "hon": true, "sgt": true, "capt": true, "lt": true, "col": true, "mt": true,
+	"jan": true, "feb": true, "mar": true, "apr": true, "jun": true, "jul": true, "aug": true, "sep": true,
+	"sept": true, "oct": true, "nov": true, "dec": true, "a.m": true, "p.m": true, "u.s": true, "u.k": true,
+	"ph.d": true, "b.a": true, "m.a": true, "min": true, "max": true, "incl": true, "excl": true,
+}
+
+// languageAbbreviations are abbreviations of other languages that don't end a sentence
+var languageAbbreviations = map[string]map[string]bool{
+	"es": {"sr": true, "sra": true, "srta": true, "dra": true, "ud": true, "uds": true, "pág": true, "núm": true, "aprox": true, "avda": true, "ej": true, "p.ej": true, "ee.uu": true, "admón": true, "tel": true},
+	"pt": {"sr": true, "sra": true, "srta": true, "dra": true, "pág": true, "núm": true, "aprox": true, "av": true, "ex": true, "p.ex": true, "tel": true, "ltda": true, "cia": true},
+	"fr": {"m": true, "mme": true, "mlle": true, "mm": true, "dr": true, "p.ex": true, "ex": true, "env": true, "cf": true, "av": true, "bd": true, "tél": true, "chap": true},
+	"de": {"z.b": true, "bzw": true, "usw": true, "ca": true, "nr": true, "hr": true, "fr": true, "str": true, "s": true, "d.h": true, "u.a": true, "vgl": true, "evtl": true, "ggf": true, "inkl": true, "tel": true, "dipl": true, "ing": true},
+	"it": {"sig": true, "sigg": true, "dott": true, "ing": true, "avv": true, "pag": true, "ecc": true, "es": true, "tel": true},
+	"nl": {"dhr": true, "mevr": true, "bijv": true, "enz": true, "o.a": true, "d.w.z": true, "blz": true, "nr": true, "tel": true},
+}
+
+// languageVowels are the letters that form syllable nuclei in each language
+var languageVowels = map[string]string{
+	"es": "aeiouáéíóúü",
+	"pt": "aeiouáéíóúâêôãõàü",
+	"it": "aeiouàèéìíòóùú",
+	"fr": "aeiouyàâäéèêëîïôöùûüœæ",
+	"de": "aeiouyäöü",
+	"nl": "aeiouyëïéèáóú",
+}
+
+// Syllable adjustments from the Lingua::EN::Syllable heuristic
+var (
+	syllableVowelGroups = regexp.MustCompile(`[aeiouy]+`)
+	syllableSubtract    = []*regexp.Regexp{
+		regexp.MustCompile(`cial`), regexp.MustCompile(`tia`), regexp.MustCompile(`cius`),
+		regexp.MustCompile(`cious`), regexp.MustCompile(`giu`), regexp.MustCompile(`ion`),
+		regexp.MustCompile(`iou`), regexp.MustCompile(`sia$`), regexp.MustCompile(`.ely$`),
+	}
+	syllableAdd = []*regexp.Regexp{
+		regexp.MustCompile(`ia`), regexp.MustCompile(`riet`), regexp.MustCompile(`dien`),
+		regexp.MustCompile(`iu`), regexp.MustCompile(`io`), regexp.MustCompile(`ii`),
+		regexp.MustCompile(`[aeiouym]bl$`), regexp.MustCompile(`[aeiou]{3}`), regexp.MustCompile(`^mc`),
+		regexp.MustCompile(`ism$`), regexp.MustCompile(`[^l]lien`), regexp.MustCompile(`^coa[dglx].`),
+		regexp.MustCompile(`[^gq]ua[^auieo]`), regexp.MustCompile(`dnt$`),
+	}
+)
+
+// syllableExceptions are common words the heuristic gets wrong
+var syllableExceptions = map[string]int{
+	"the": 1, "every": 3, "everything": 4, "everyone": 4, "business": 2, "businesses": 3,
+	"area": 3, "idea": 3, "ideas": 3, "create": 2, "created": 3, "people": 2, "science": 2,
+	"being": 2, "going": 2, "doing": 2, "seeing": 2, "really": 3, "poem": 2, "quiet": 2,
+	"queue": 1, "something": 2, "sometimes": 2, "whole": 1, "whose": 1, "simile": 3,
+}
+
+// analyzeReadability scores a list of paragraphs in the given language as a whole and ranks the hardest ones
+func analyzeReadability(paragraphs []string, lang string) ReadabilityReport {
+	report := ReadabilityReport{
+		Language:          lang,
+		HardestParagraphs: []ParagraphReadability{},
+	}
+	formula, hasFormula := readingEaseFormulas[lang]
+	if hasFormula {
+		report.Formula = formula.name
+	}
+
+	var total textStats
+	for _, paragraph := range paragraphs {
+		stats := analyzeText(paragraph, lang)
+		total.words += stats.words
+		total.sentences += stats.sentences
+		total.syllables += stats.syllables
+		total.complexWords += stats.complexWords
+		total.polysyllables += stats.polysyllables
+		total.letters += stats.letters
+
+		if hasFormula && stats.words >= minParagraphWords {
+			paragraphReadability := ParagraphReadability{
+				Text:        truncateRunes(paragraph, 200),
+				Words:       stats.words,
+				Sentences:   stats.sentences,
+				ReadingEase: round1(stats.readingEase(formula.formula)),
+			}
+			if lang == "en" {
+				paragraphReadability.FleschKincaidGrade = round1(stats.fleschKincaidGrade())
+			}
+			report.HardestParagraphs = append(report.HardestParagraphs, paragraphReadability)
+		}
+	}
+
+	sort.SliceStable(report.HardestParagraphs, func(i, j int) bool {
+		return report.HardestParagraphs[i].ReadingEase < report.HardestParagraphs[j].ReadingEase
+	})
+	if len(report.HardestParagraphs) > maxHardParagraphs {
+		report.HardestParagraphs = report.HardestParagraphs[:maxHardParagraphs]
+	}
+
+	report.Words = total.words
+	report.Sentences = total.sentences
+	report.Syllables = total.syllables
+	report.ComplexWords = total.complexWords
+	if total.words == 0 {
+		return report
+	}
+
+	report.WordsPerSentence = round1(total.wordsPerSentence())
+	report.SyllablesPerWord = round1(float64(total.syllables) / float64(total.words))
+	if hasFormula {
+		report.ReadingEase = round1(total.readingEase(formula.formula))
+	}
+	if lang != "en" {
+		return report
+	}
+
+	report.FleschReadingEase = report.ReadingEase
+	report.FleschKincaidGrade = round1(total.fleschKincaidGrade())
+	report.GunningFog = round1(0.4 * (total.wordsPerSentence() + 100*float64(total.complexWords)/float64(total.words)))
+	report.SMOG = round1(1.0430*math.Sqrt(float64(total.polysyllables)*30/float64(total.sentences)) + 3.1291)
+	lettersPer100 := float64(total.letters) / float64(total.words) * 100
+	sentencesPer100 := float64(total.sentences) / float64(total.words) * 100
+	report.ColemanLiau = round1(0.0588*lettersPer100 - 0.296*sentencesPer100 - 15.8)
+
+	return report
+}
+
+// analyzeText counts words, sentences, syllables and letters in a block of text
+func analyzeText(text, lang string) textStats {
+	var stats textStats
+	for _, sentence := range splitSentences(text, lang) {
+		words := splitWords(sentence, lang)
+		if len(words) == 0 {
+			continue
+		}
+		stats.sentences++
+		for _, word := range words {
+			syllables := countSyllablesIn(word, lang)
+			stats.words++
+			stats.syllables += syllables
+			if syllables >= 3 {
+				stats.polysyllables++
+				// Gunning Fog doesn't count hyphenated compounds as complex words
+				if !strings.Contains(word, "-") {
+					stats.complexWords++
+				}
+			}
+			for _, r := range word {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					stats.letters++
+				}
+			}
+		}
+	}
+	return stats
+}
+
+func (s textStats) wordsPerSentence() float64 {
+	if s.sentences == 0 {
+		return float64(s.words)
+	}
+	return float64(s.words) / float64(s.sentences)
+}
+
+func (s textStats) readingEase(formula func(asl, asw float64) float64) float64 {
+	if s.words == 0 {
+		return 0
+	}
+	return formula(s.wordsPerSentence(), float64(s.syllables)/float64(s.words))
+}
+
+func (s textStats) fleschKincaidGrade() float64 {
+	if s.words == 0 {
+		return 0
+	}
+	return 0.39*s.wordsPerSentence() + 11.8*float64(s.syllables)/float64(s.words) - 15.59
+}
+
+// splitSentences segments text into sentences without breaking on abbreviations, initials or decimals
+func splitSentences(text, lang string) []string {
+	// Full-width punctuation isn't followed by a space in Chinese and Japanese
+	text = fullWidthTerminators.Replace(text)
+
+	sentences := []string{}
+	tokens := strings.Fields(text)
+	start := 0
+	for i, token := range tokens {
+		next := ""
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+		if endsSentence(token, next, lang) {
+			sentences = append(sentences, strings.Join(tokens[start:i+1], " "))
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		sentences = append(sentences, strings.Join(tokens[start:], " "))
+	}
+	return sentences
+}
+
+var fullWidthTerminators = strings.NewReplacer("。", "。 ", "！", "！ ", "？", "？ ")
+
+// endsSentence reports whether a token ends a sentence, given the token that follows it
+func endsSentence(token, next, lang string) bool {
+	trimmed := strings.TrimRight(token, "\"'”’)]»")
+	if trimmed == "" {
+		return false
+	}
+
+	last, _ := lastRune(trimmed)
+	switch last {
+	case '!', '?', '…', '。', '！', '？':
+		return true
+	case '.':
+	default:
+		return false
+	}
+	if next == "" {
+		return true
+	}
+
+	// A following lowercase word means the period was part of the sentence, e.g. "approx. five" or "..."
+	first, _ := firstLetter(next)
+	if unicode.IsLower(first) {
+		return false
+	}
+
+	word := strings.ToLower(strings.TrimLeft(strings.TrimRight(trimmed, "."), "\"'“‘(["))
+	if commonAbbreviations[word] || languageAbbreviations[lang][word] {
+		return false
+	}
+	// German ordinals such as "3. Oktober"
+	if lang == "de" && isDigits(word) {
+		return false
+	}
+	// Initials such as "J. R. Tolkien"
+	if runes := []rune(word); len(runes) == 1 && unicode.IsLetter(runes[0]) {
+		return false
+	}
+	return true
+}
+
+// splitWords returns the tokens of a sentence that contain a letter or digit, stripped of punctuation.
+// Chinese and Japanese are written without spaces, so each ideograph or kana counts as a word.
+func splitWords(sentence, lang string) []string {
+	words := []string{}
+	for _, token := range strings.Fields(sentence) {
+		word := strings.TrimFunc(token, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if word == "" {
+			continue
+		}
+		if lang == "zh" || lang == "ja" {
+			words = append(words, splitCJK(word)...)
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// splitCJK splits a token into single ideographs and kana, keeping runs of other letters together
+func splitCJK(token string) []string {
+	words := []string{}
+	run := []rune{}
+	for _, r := range token {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			if len(run) > 0 {
+				words = append(words, string(run))
+				run = run[:0]
+			}
+			words = append(words, string(r))
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			run = append(run, r)
+		}
+	}
+	if len(run) > 0 {
+		words = append(words, string(run))
+	}
+	return words
+}
+
+// countSyllablesIn estimates the number of syllables in a word of the given language
+func countSyllablesIn(word, lang string) int {
+	vowels, ok := languageVowels[lang]
+	if !ok {
+		return countSyllables(word)
+	}
+	word = strings.ToLower(word)
+
+	// French and Italian elisions such as "l'homme" or "dell'anno" add no syllable
+	if i := strings.LastIndexAny(word, "'’"); i >= 0 && (lang == "fr" || lang == "it") {
+		_, size := utf8.DecodeRuneInString(word[i:])
+		word = word[i+size:]
+	}
+	// French final "e" and "es" are silent
+	if lang == "fr" && len([]rune(word)) > 3 {
+		if strings.HasSuffix(word, "es") {
+			word = strings.TrimSuffix(word, "es")
+		} else if strings.HasSuffix(word, "e") {
+			word = strings.TrimSuffix(word, "e")
+		}
+	}
+
+	count := 0
+	var previous rune
+	for i, r := range []rune(word) {
+		isVowel := strings.ContainsRune(vowels, r)
+		// Spanish "y" is a vowel at the end of a word, as in "hoy" or "muy"
+		if lang == "es" && r == 'y' && i == len([]rune(word))-1 && i > 0 {
+			isVowel = true
+		}
+		if isVowel && (i == 0 || !strings.ContainsRune(vowels, previous) || startsHiatus(previous, r, lang)) {
+			count++
+		}
+		previous = r
+	}
+
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+// startsHiatus reports whether a vowel following another vowel starts a new syllable
+func startsHiatus(previous, current rune, lang string) bool {
+	switch lang {
+	case "es", "pt", "it":
+		// Nasal Portuguese vowels form diphthongs, as in "mão" and "põe"
+		if previous == 'ã' || previous == 'õ' {
+			return false
+		}
+		return isStrongVowel(previous) && isStrongVowel(current) || strings.ContainsRune("íú", current) || strings.ContainsRune("íú", previous)
+	case "fr":
+		return strings.ContainsRune("éè", previous) || current == 'é'
+	}
+	return false
+}
+
+// isStrongVowel reports whether a Romance-language vowel is open (a, e, o)
+func isStrongVowel(r rune) bool {
+	return strings.ContainsRune("aeoáéóâêôàèòã", r)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// countSyllables estimates the number of syllables in an English word
+func countSyllables(word string) int {
+	word = strings.ToLower(word)
+
+	// Hyphenated compounds are counted part by part
+	if strings.Contains(word, "-") {
+		total := 0
+		for _, part := range strings.Split(word, "-") {
+			total += countSyllables(part)
+		}
+		if total == 0 {
+			return 1
+		}
+		return total
+	}
+
+	letters := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		return -1
+	}, word)
+	if letters == "" {
+		// Numbers and non-Latin words count as one syllable
+		return 1
+	}
+	if n, ok := syllableExceptions[letters]; ok {
+		return n
+	}
+	if len(letters) <= 3 {
+		return 1
+	}
+
+	// A final "e" is usually silent, as are "es" and "ed" after most consonants
+	stem := letters
+	switch {
+	case strings.HasSuffix(stem, "ed") && !strings.HasSuffix(stem, "ted") && !strings.HasSuffix(stem, "ded"):
+		stem = strings.TrimSuffix(stem, "ed")
+	case strings.HasSuffix(stem, "es") && !hasAnySuffix(stem, "ses", "xes", "zes", "ces", "ges", "ches", "shes"):
+		stem = strings.TrimSuffix(stem, "es")
+	case strings.HasSuffix(stem, "e") && !strings.HasSuffix(stem, "le") && !strings.HasSuffix(stem, "ee"):
+		stem = strings.TrimSuffix(stem, "e")
+	}
+
+	count := len(syllableVowelGroups.FindAllString(stem, -1))
+	for _, pattern := range syllableSubtract {
+		if pattern.MatchString(letters) {
+			count--
+		}
+	}
+	for _, pattern := range syllableAdd {
+		if pattern.MatchString(letters) {
+			count++
+		}
+	}
+
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func lastRune(s string) (rune, bool) {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0, false
+	}
+	return runes[len(runes)-1], true
+}
+
+// firstLetter returns the first letter or digit of a token, skipping opening quotes and brackets
+func firstLetter(s string) (rune, bool) {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// round1 rounds to one decimal place
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
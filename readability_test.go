@@ -0,0 +1,107 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"cat", 1},
+		{"the", 1},
+		{"table", 2},
+		{"readability", 5},
+		{"reading", 2},
+		{"make", 1},
+		{"created", 3},
+		{"well-known", 2},
+	}
+	for _, tt := range tests {
+		if got := countSyllables(tt.word); got != tt.want {
+			t.Errorf("countSyllables(%q) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		lang string
+		want []string
+	}{
+		{
+			name: "plain",
+			text: "The cat sat. The dog ran! Did it?",
+			lang: "en",
+			want: []string{"The cat sat.", "The dog ran!", "Did it?"},
+		},
+		{
+			name: "abbreviation",
+			text: "Dr. Smith arrived. He was late.",
+			lang: "en",
+			want: []string{"Dr. Smith arrived.", "He was late."},
+		},
+		{
+			name: "decimal",
+			text: "It costs 3.50 dollars. That is cheap.",
+			lang: "en",
+			want: []string{"It costs 3.50 dollars.", "That is cheap."},
+		},
+		{
+			name: "lowercase after period",
+			text: "It weighs approx. five kilos.",
+			lang: "en",
+			want: []string{"It weighs approx. five kilos."},
+		},
+		{
+			name: "full-width punctuation",
+			text: "今日は晴れです。明日は雨です。",
+			lang: "ja",
+			want: []string{"今日は晴れです。", "明日は雨です。"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitSentences(tt.text, tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitSentences() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeText(t *testing.T) {
+	stats := analyzeText("The cat sat on the mat. A well-known readability formula.", "en")
+	want := textStats{sentences: 2, words: 10, syllables: 17, polysyllables: 2, complexWords: 2, letters: 45}
+	if stats != want {
+		t.Errorf("analyzeText() = %+v, want %+v", stats, want)
+	}
+	if got := round1(stats.readingEase(readingEaseFormulas["en"].formula)); got != 57.9 {
+		t.Errorf("readingEase() = %v, want 57.9", got)
+	}
+}
+
+func TestCountSyllablesIn(t *testing.T) {
+	tests := []struct {
+		word, lang string
+		want       int
+	}{
+		{"casa", "es", 2},
+		{"día", "es", 2},
+		{"muy", "es", 1},
+		{"coração", "pt", 3},
+		{"mão", "pt", 1},
+		{"l'homme", "fr", 1},
+		{"table", "fr", 1},
+		{"Wochenende", "de", 4},
+		{"readability", "en", 5},
+	}
+	for _, tt := range tests {
+		if got := countSyllablesIn(tt.word, tt.lang); got != tt.want {
+			t.Errorf("countSyllablesIn(%q, %q) = %d, want %d", tt.word, tt.lang, got, tt.want)
+		}
+	}
+}
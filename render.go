@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// renderHTMLToPNG renders a self-contained HTML document in a scratch page of the given
+// context and returns a full-page screenshot as a PNG data URI for embedding in reports
+func renderHTMLToPNG(context playwright.BrowserContext, html string, width int) (string, error) {
+	page, err := context.NewPage()
+	if err != nil {
+		return "", fmt.Errorf("could not open render page: %v", err)
+	}
+	defer page.Close()
+
+	if err := page.SetViewportSize(width, 100); err != nil {
+		return "", fmt.Errorf("could not size render page: %v", err)
+	}
+	if err := page.SetContent(html); err != nil {
+		return "", fmt.Errorf("could not set render content: %v", err)
+	}
+
+	png, err := page.Screenshot(playwright.PageScreenshotOptions{
+		Type:     playwright.ScreenshotTypePng,
+		FullPage: playwright.Bool(true),
+		Scale:    playwright.ScreenshotScaleCss,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not render screenshot: %v", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
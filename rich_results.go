@@ -0,0 +1,148 @@
+package main
+
+// RichResultEligibility reports whether a structured data item qualifies for a Google rich result feature
+type RichResultEligibility struct {
+	Feature            string   `json:"feature"`
+	Item               string   `json:"item"`
+	Syntax             string   `json:"syntax"`
+	Eligible           bool     `json:"eligible"`
+	MissingRequired    []string `json:"missing_required"`
+	MissingRecommended []string `json:"missing_recommended"`
+}
+
+// richResultFeature describes the structured data Google asks for to show a rich result.
+// Property rules use the same "a|b" and "a.b" notation as schemaRules.
+type richResultFeature struct {
+	name        string
+	types       []string
+	required    []string
+	recommended []string
+	standalone  []string // Required when the item isn't attached to a parent item
+	nestable    bool     // Also eligible when nested in another item, e.g. a Product as a WebPage's mainEntity
+	exclude     []string // Subtypes covered by a feature of their own
+}
+
+// richResultFeatures follows Google Search Central's structured data documentation
+var richResultFeatures = []richResultFeature{
+	{
+		name:        "Article",
+		types:       []string{"Article"},
+		recommended: []string{"headline", "image", "datePublished", "dateModified", "author", "author.name", "author.url"},
+		nestable:    true,
+	},
+	{
+		name:        "Product snippet",
+		types:       []string{"Product"},
+		required:    []string{"name", "review|aggregateRating|offers"},
+		recommended: []string{"image", "description", "brand.name|brand", "sku|gtin|gtin13|gtin8|mpn", "offers.price|offers.lowPrice", "offers.priceCurrency", "offers.availability", "aggregateRating.ratingValue", "review.author"},
+		nestable:    true,
+	},
+	{
+		name:     "FAQ",
+		types:    []string{"FAQPage"},
+		required: []string{"mainEntity", "mainEntity.name", "mainEntity.acceptedAnswer.text"},
+		nestable: true,
+	},
+	{
+		name:        "Breadcrumb",
+		types:       []string{"BreadcrumbList"},
+		required:    []string{"itemListElement", "itemListElement.position", "itemListElement.name|itemListElement.item.name"},
+		recommended: []string{"itemListElement.item"},
+		nestable:    true,
+	},
+	{
+		name:        "Organization logo",
+		types:       []string{"Organization"},
+		exclude:     []string{"LocalBusiness"},
+		required:    []string{"logo", "url"},
+		recommended: []string{"name", "sameAs", "contactPoint", "address"},
+	},
+	{
+		name:        "Recipe",
+		types:       []string{"Recipe"},
+		required:    []string{"name", "image"},
+		recommended: []string{"aggregateRating", "author", "cookTime", "datePublished", "description", "keywords", "nutrition.calories", "prepTime", "recipeCategory", "recipeCuisine", "recipeIngredient", "recipeInstructions", "recipeYield", "totalTime", "video"},
+		nestable:    true,
+	},
+	{
+		name:        "Event",
+		types:       []string{"Event"},
+		required:    []string{"name", "startDate", "location", "location.name|location.address|location.url"},
+		recommended: []string{"description", "endDate", "eventAttendanceMode", "eventStatus", "image", "offers", "offers.price", "organizer", "organizer.name", "performer", "location.address"},
+		nestable:    true,
+	},
+	{
+		name:        "Video",
+		types:       []string{"VideoObject"},
+		required:    []string{"name", "thumbnailUrl", "uploadDate"},
+		recommended: []string{"contentUrl", "description", "duration", "embedUrl", "expires", "interactionStatistic"},
+		nestable:    true,
+	},
+	{
+		name:        "Local business",
+		types:       []string{"LocalBusiness"},
+		required:    []string{"name", "address"},
+		recommended: []string{"aggregateRating", "geo", "openingHoursSpecification|openingHours", "priceRange", "review", "telephone", "url", "image", "department", "menu"},
+	},
+	{
+		name:        "Review snippet",
+		types:       []string{"Review"},
+		required:    []string{"author", "author.name|author", "reviewRating", "reviewRating.ratingValue"},
+		recommended: []string{"datePublished", "reviewRating.bestRating", "reviewRating.worstRating"},
+		standalone:  []string{"itemReviewed", "itemReviewed.name"},
+		nestable:    true,
+	},
+	{
+		name:        "Review snippet",
+		types:       []string{"AggregateRating"},
+		required:    []string{"ratingValue", "ratingCount|reviewCount"},
+		recommended: []string{"bestRating", "worstRating"},
+		standalone:  []string{"itemReviewed", "itemReviewed.name"},
+		nestable:    true,
+	},
+}
+
+// richResults evaluates every item against the rich result features it could qualify for. Nested
+// items are only evaluated for features that can be attached to a parent item.
+func (d *structuredData) richResults() []RichResultEligibility {
+	results := []RichResultEligibility{}
+	for _, entity := range d.entities {
+		for _, feature := range richResultFeatures {
+			if !entityMatchesTypes(entity, feature.types) || entityMatchesTypes(entity, feature.exclude) {
+				continue
+			}
+			if entity.nested && !feature.nestable {
+				continue
+			}
+
+			// A parent item provides what standalone items must declare themselves
+			required := append([]string{}, feature.required...)
+			if !entity.nested {
+				required = append(required, feature.standalone...)
+			}
+
+			result := RichResultEligibility{
+				Feature:            feature.name,
+				Item:               entity.Path,
+				Syntax:             entity.Syntax,
+				MissingRequired:    d.missingProperties(entity, required),
+				MissingRecommended: d.missingProperties(entity, feature.recommended),
+			}
+			result.Eligible = len(result.MissingRequired) == 0
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// entityMatchesTypes reports whether any of the entity's types is one of the given types or a subtype
+func entityMatchesTypes(entity *SchemaEntity, types []string) bool {
+	for _, t := range entity.Types {
+		for _, want := range types {
+			if schemaTypeIs(t, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
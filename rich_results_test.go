@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRichResults(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		want        map[string]bool // "Feature Item" -> eligible
+		wantMissing []string        // Missing required properties of the first result
+	}{
+		{
+			name:    "eligible product",
+			content: `{"@type":"Product","name":"Shoe","offers":{"@type":"Offer","price":"10","priceCurrency":"EUR"}}`,
+			want:    map[string]bool{"Product snippet Product": true},
+		},
+		{
+			name:        "product without offers or reviews",
+			content:     `{"@type":"Product","name":"Shoe"}`,
+			want:        map[string]bool{"Product snippet Product": false},
+			wantMissing: []string{"review or aggregateRating or offers"},
+		},
+		{
+			name:    "subtype matches the feature",
+			content: `{"@type":"NewsArticle","headline":"News"}`,
+			want:    map[string]bool{"Article NewsArticle": true},
+		},
+		{
+			name:        "standalone review needs the reviewed item",
+			content:     `{"@type":"Review","author":{"@type":"Person","name":"Ada"},"reviewRating":{"@type":"Rating","ratingValue":"5"}}`,
+			want:        map[string]bool{"Review snippet Review": false},
+			wantMissing: []string{"itemReviewed", "itemReviewed.name"},
+		},
+		{
+			name:    "no rich result feature",
+			content: `{"@type":"WebSite","url":"https://example.com"}`,
+			want:    map[string]bool{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newStructuredData()
+			d.parseJSONLD(0, tt.content)
+			results := d.richResults()
+
+			got := map[string]bool{}
+			for _, result := range results {
+				got[result.Feature+" "+result.Item] = result.Eligible
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("richResults() = %v, want %v", got, tt.want)
+			}
+			if tt.wantMissing != nil && !reflect.DeepEqual(results[0].MissingRequired, tt.wantMissing) {
+				t.Errorf("missing required = %v, want %v", results[0].MissingRequired, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestRichResultsNested(t *testing.T) {
+	d := newStructuredData()
+	d.parseJSONLD(0, `{"@type":"WebPage","mainEntity":{"@type":"Product","name":"Shoe","offers":{"@type":"Offer","price":"10"}}}`)
+	d.parseJSONLD(1, `{"@type":"Restaurant","name":"Bistro","address":"1 Main St"}`)
+
+	features := map[string]bool{}
+	for _, result := range d.richResults() {
+		features[result.Feature+" "+result.Item] = result.Eligible
+	}
+	if eligible, ok := features["Product snippet WebPage.mainEntity"]; !ok || !eligible {
+		t.Errorf("nested Product not evaluated as eligible: %v", features)
+	}
+	if _, ok := features["Organization logo Restaurant"]; ok {
+		t.Errorf("LocalBusiness got an Organization logo row: %v", features)
+	}
+	if eligible := features["Local business Restaurant"]; !eligible {
+		t.Errorf("Restaurant not eligible for Local business: %v", features)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+// schemaRule lists the required and recommended properties of a schema.org type.
+// "a|b" is satisfied by either property and "a.b" checks a property of a nested item.
+type schemaRule struct {
+	required    []string
+	recommended []string
+}
+
+// schemaRules is a schema.org-derived rule set for the types we validate.
+// Subtypes without their own entry are validated with the rule of their parent in schemaParentTypes.
+var schemaRules = map[string]schemaRule{
+	"Thing": {
+		required: []string{"name"},
+	},
+	"Organization": {
+		required:    []string{"name"},
+		recommended: []string{"url", "logo", "sameAs", "contactPoint"},
+	},
+	"LocalBusiness": {
+		required:    []string{"name", "address"},
+		recommended: []string{"url", "telephone", "image", "geo", "openingHoursSpecification|openingHours", "priceRange"},
+	},
+	"Person": {
+		required:    []string{"name"},
+		recommended: []string{"url", "sameAs"},
+	},
+	"WebSite": {
+		required:    []string{"name|url"},
+		recommended: []string{"url", "potentialAction"},
+	},
+	"WebPage": {
+		required:    []string{"name|headline"},
+		recommended: []string{"url", "description"},
+	},
+	"Article": {
+		required:    []string{"headline"},
+		recommended: []string{"image", "datePublished", "dateModified", "author", "publisher"},
+	},
+	"Product": {
+		required:    []string{"name"},
+		recommended: []string{"image", "description", "brand", "sku|gtin|gtin13|gtin8|mpn", "offers|review|aggregateRating"},
+	},
+	"Offer": {
+		required:    []string{"price|priceSpecification", "priceCurrency|priceSpecification"},
+		recommended: []string{"availability", "url", "priceValidUntil", "itemCondition"},
+	},
+	"AggregateOffer": {
+		required:    []string{"lowPrice", "priceCurrency"},
+		recommended: []string{"highPrice", "offerCount"},
+	},
+	"BreadcrumbList": {
+		required: []string{"itemListElement"},
+	},
+	"ItemList": {
+		required: []string{"itemListElement"},
+	},
+	"ListItem": {
+		required:    []string{"position"},
+		recommended: []string{"name|item.name", "item"},
+	},
+	"FAQPage": {
+		required: []string{"mainEntity"},
+	},
+	"Question": {
+		required: []string{"name", "acceptedAnswer|suggestedAnswer"},
+	},
+	"Answer": {
+		required: []string{"text"},
+	},
+	"Recipe": {
+		required:    []string{"name", "image"},
+		recommended: []string{"recipeIngredient", "recipeInstructions", "author", "datePublished", "totalTime|cookTime", "recipeYield", "nutrition"},
+	},
+	"Event": {
+		required:    []string{"name", "startDate", "location"},
+		recommended: []string{"endDate", "description", "image", "offers", "organizer", "performer", "eventStatus", "eventAttendanceMode"},
+	},
+	"Place": {
+		required:    []string{"name|address"},
+		recommended: []string{"address", "geo"},
+	},
+	"PostalAddress": {
+		required:    []string{"streetAddress|addressLocality"},
+		recommended: []string{"addressLocality", "postalCode", "addressCountry"},
+	},
+	"VideoObject": {
+		required:    []string{"name", "thumbnailUrl", "uploadDate"},
+		recommended: []string{"description", "contentUrl|embedUrl", "duration"},
+	},
+	"ImageObject": {
+		required:    []string{"url|contentUrl"},
+		recommended: []string{"width", "height", "caption"},
+	},
+	"Review": {
+		required:    []string{"author", "reviewRating|reviewBody"},
+		recommended: []string{"itemReviewed", "datePublished", "reviewRating.ratingValue"},
+	},
+	"AggregateRating": {
+		required:    []string{"ratingValue", "ratingCount|reviewCount"},
+		recommended: []string{"bestRating", "worstRating"},
+	},
+	"Rating": {
+		required:    []string{"ratingValue"},
+		recommended: []string{"bestRating", "worstRating"},
+	},
+	"Brand": {
+		required: []string{"name"},
+	},
+	"ContactPoint": {
+		required:    []string{"telephone|email|url"},
+		recommended: []string{"contactType"},
+	},
+	"SearchAction": {
+		required: []string{"target", "query-input"},
+	},
+	"HowTo": {
+		required:    []string{"name", "step"},
+		recommended: []string{"image", "totalTime", "supply", "tool"},
+	},
+	"HowToStep": {
+		required: []string{"text|itemListElement"},
+	},
+	"SoftwareApplication": {
+		required:    []string{"name"},
+		recommended: []string{"offers", "aggregateRating|review", "operatingSystem", "applicationCategory"},
+	},
+	"Course": {
+		required:    []string{"name", "description"},
+		recommended: []string{"provider"},
+	},
+	"JobPosting": {
+		required:    []string{"title", "description", "datePosted", "hiringOrganization", "jobLocation|applicantLocationRequirements"},
+		recommended: []string{"validThrough", "employmentType", "baseSalary"},
+	},
+}
+
+// schemaParentTypes maps common schema.org subtypes to the type whose rule applies to them
+var schemaParentTypes = map[string]string{
+	// CreativeWork
+	"NewsArticle":             "Article",
+	"BlogPosting":             "Article",
+	"TechArticle":             "Article",
+	"ScholarlyArticle":        "Article",
+	"Report":                  "Article",
+	"SocialMediaPosting":      "Article",
+	"LiveBlogPosting":         "BlogPosting",
+	"AboutPage":               "WebPage",
+	"CollectionPage":          "WebPage",
+	"ContactPage":             "WebPage",
+	"ItemPage":                "WebPage",
+	"ProfilePage":             "WebPage",
+	"SearchResultsPage":       "WebPage",
+	"CheckoutPage":            "WebPage",
+	"QAPage":                  "WebPage",
+	"FAQPage":                 "WebPage",
+	"MobileApplication":       "SoftwareApplication",
+	"WebApplication":          "SoftwareApplication",
+	"CriticReview":            "Review",
+	"EmployerAggregateRating": "AggregateRating",
+	// Organization and LocalBusiness
+	"Corporation":                 "Organization",
+	"EducationalOrganization":     "Organization",
+	"NGO":                         "Organization",
+	"NewsMediaOrganization":       "Organization",
+	"OnlineStore":                 "Organization",
+	"OnlineBusiness":              "Organization",
+	"MedicalOrganization":         "Organization",
+	"SportsOrganization":          "Organization",
+	"LocalBusiness":               "Organization",
+	"Store":                       "LocalBusiness",
+	"Restaurant":                  "LocalBusiness",
+	"FoodEstablishment":           "LocalBusiness",
+	"CafeOrCoffeeShop":            "LocalBusiness",
+	"Bakery":                      "LocalBusiness",
+	"BarOrPub":                    "LocalBusiness",
+	"Dentist":                     "LocalBusiness",
+	"Physician":                   "LocalBusiness",
+	"MedicalClinic":               "LocalBusiness",
+	"Hotel":                       "LocalBusiness",
+	"LodgingBusiness":             "LocalBusiness",
+	"AutoDealer":                  "LocalBusiness",
+	"AutoRepair":                  "LocalBusiness",
+	"ProfessionalService":         "LocalBusiness",
+	"LegalService":                "LocalBusiness",
+	"Attorney":                    "LocalBusiness",
+	"RealEstateAgent":             "LocalBusiness",
+	"FinancialService":            "LocalBusiness",
+	"HealthAndBeautyBusiness":     "LocalBusiness",
+	"HomeAndConstructionBusiness": "LocalBusiness",
+	"SportsActivityLocation":      "LocalBusiness",
+	"ClothingStore":               "Store",
+	"ElectronicsStore":            "Store",
+	"GroceryStore":                "Store",
+	"HardwareStore":               "Store",
+	"BookStore":                   "Store",
+	"FurnitureStore":              "Store",
+	// Events
+	"BusinessEvent":   "Event",
+	"MusicEvent":      "Event",
+	"SportsEvent":     "Event",
+	"TheaterEvent":    "Event",
+	"EducationEvent":  "Event",
+	"Festival":        "Event",
+	"ComedyEvent":     "Event",
+	"ExhibitionEvent": "Event",
+	"SaleEvent":       "Event",
+	// Products and places
+	"ProductModel":      "Product",
+	"IndividualProduct": "Product",
+	"ProductGroup":      "Product",
+	"Car":               "Product",
+	"Vehicle":           "Product",
+	"City":              "Place",
+	"Country":           "Place",
+	"TouristAttraction": "Place",
+	"Residence":         "Place",
+	"EmployerReview":    "Review",
+	"UserReview":        "Review",
+	"VideoGame":         "SoftwareApplication",
+}
+
+// schemaRuleFor returns the rule that applies to a type, walking up schemaParentTypes
+func schemaRuleFor(schemaType string) (schemaRule, string, bool) {
+	for t, depth := schemaType, 0; t != "" && depth < 10; t, depth = schemaParentTypes[t], depth+1 {
+		if rule, ok := schemaRules[t]; ok {
+			return rule, t, true
+		}
+	}
+	return schemaRule{}, "", false
+}
+
+// schemaTypeIs reports whether a type is the given type or one of its known subtypes
+func schemaTypeIs(schemaType, ancestor string) bool {
+	for t, depth := schemaType, 0; t != "" && depth < 10; t, depth = schemaParentTypes[t], depth+1 {
+		if t == ancestor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// SchemaSuggestion is a ready-to-paste JSON-LD block built from the page content
+type SchemaSuggestion struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+	JSONLD string `json:"json_ld"`
+}
+
+// schemaSource holds the page content a JSON-LD suggestion is built from
+type schemaSource struct {
+	URL           string              `json:"url"`
+	Title         string              `json:"title"`
+	H1            string              `json:"h1"`
+	Description   string              `json:"description"`
+	Image         string              `json:"image"`
+	SiteName      string              `json:"siteName"`
+	OGType        string              `json:"ogType"`
+	Logo          string              `json:"logo"`
+	Published     string              `json:"published"`
+	Modified      string              `json:"modified"`
+	Authors       []string            `json:"authors"`
+	AuthorURL     string              `json:"authorUrl"`
+	SameAs        []string            `json:"sameAs"`
+	Breadcrumbs   []visibleBreadcrumb `json:"breadcrumbs"`
+	BreadcrumbEnd string              `json:"breadcrumbEnd"`
+}
+
+// schemaSourceScript collects title, headings, meta tags, logo, dates, bylines and breadcrumbs for JSON-LD suggestions
+const schemaSourceScript = `(breadcrumbSelector) => {
+	const meta = (selector) => {
+		const el = document.querySelector(selector);
+		return el ? (el.getAttribute('content') || '').trim() : '';
+	};
+	const text = (el) => el ? (el.innerText || el.textContent || '').trim().replace(/\s+/g, ' ') : '';
+	const absolute = (value) => {
+		try { return value ? new URL(value, document.baseURI).href : ''; } catch (e) { return ''; }
+	};
+
+	const canonical = document.querySelector('link[rel="canonical"]');
+	const h1 = document.querySelector('h1');
+
+	const logo = document.querySelector('header img[src*="logo" i], header img[alt*="logo" i], header img[class*="logo" i], [class*="logo" i] img, img[class*="logo" i], img[id*="logo" i], img[src*="logo" i]');
+	const icon = document.querySelector('link[rel="apple-touch-icon"], link[rel="icon"]');
+
+	const time = (prop) => {
+		const el = document.querySelector('[itemprop="' + prop + '"]');
+		return el ? (el.getAttribute('datetime') || el.getAttribute('content') || text(el)) : '';
+	};
+	const firstTime = document.querySelector('article time[datetime], main time[datetime], time[datetime]');
+
+	const authors = [];
+	const addAuthor = (name) => {
+		name = (name || '').replace(/^(by|por|von|par)\s+/i, '').trim();
+		if (name && name.length <= 80 && !authors.includes(name)) authors.push(name);
+	};
+	addAuthor(meta('meta[name="author" i]'));
+	document.querySelectorAll('[rel="author"], [itemprop="author"], .author, .byline, [class*="author-name" i]').forEach(el => {
+		if (authors.length < 3) addAuthor(el.getAttribute('content') || text(el));
+	});
+	const authorLink = document.querySelector('a[rel="author"], .author a[href], .byline a[href]');
+
+	const socialHosts = /(^|\.)(facebook|twitter|x|linkedin|instagram|youtube|tiktok|pinterest|github)\.com$/i;
+	const sameAs = [];
+	document.querySelectorAll('a[href^="http"]').forEach(a => {
+		try {
+			const host = new URL(a.href).hostname;
+			if (socialHosts.test(host) && !sameAs.includes(a.href) && sameAs.length < 10) sameAs.push(a.href);
+		} catch (e) {}
+	});
+
+	const crumbs = document.querySelector(breadcrumbSelector);
+	const breadcrumbs = crumbs ? Array.from(crumbs.querySelectorAll('a[href]')).map(a => ({ text: text(a), url: a.href })).filter(c => c.text) : [];
+	let breadcrumbEnd = '';
+	if (crumbs) {
+		const items = Array.from(crumbs.querySelectorAll('li, [itemprop="itemListElement"], span, a'));
+		const last = items.length ? items[items.length - 1] : null;
+		if (last && !last.querySelector('a') && last.tagName !== 'A') breadcrumbEnd = text(last);
+	}
+
+	return {
+		url: canonical ? canonical.href : location.href,
+		title: (document.title || '').trim(),
+		h1: text(h1),
+		description: meta('meta[name="description" i]') || meta('meta[property="og:description"]'),
+		image: absolute(meta('meta[property="og:image"]') || meta('meta[name="twitter:image"]')),
+		siteName: meta('meta[property="og:site_name"]') || meta('meta[name="application-name"]'),
+		ogType: meta('meta[property="og:type"]'),
+		logo: logo ? absolute(logo.getAttribute('src')) : (icon ? absolute(icon.getAttribute('href')) : ''),
+		published: meta('meta[property="article:published_time"]') || time('datePublished') || (firstTime ? firstTime.getAttribute('datetime') : ''),
+		modified: meta('meta[property="article:modified_time"]') || meta('meta[property="og:updated_time"]') || time('dateModified'),
+		authors: authors,
+		authorUrl: authorLink ? authorLink.href : '',
+		sameAs: sameAs,
+		breadcrumbs: breadcrumbs,
+		breadcrumbEnd: breadcrumbEnd
+	};
+}`
+
+// titleSeparators split a title like "Page name | Brand" into page and site name
+var titleSeparators = []string{" | ", " - ", " – ", " — ", " :: ", " · ", " • "}
+
+// suggestSchemaMarkup proposes JSON-LD blocks for the structured data the page is missing
+func suggestSchemaMarkup(page playwright.Page, score SchemaMarkupScore) ([]SchemaSuggestion, error) {
+	suggestions := []SchemaSuggestion{}
+	if score.HasSchema && score.HasOrganization && score.HasBreadcrumb {
+		return suggestions, nil
+	}
+
+	var source schemaSource
+	result, err := page.Evaluate(schemaSourceScript, breadcrumbSelector)
+	if err != nil {
+		return nil, fmt.Errorf("could not read page content for schema suggestions: %v", err)
+	}
+	if err := decodeInto(result, &source); err != nil {
+		return nil, fmt.Errorf("could not read page content for schema suggestions: %v", err)
+	}
+
+	add := func(schemaType, reason string, item map[string]interface{}) {
+		item["@context"] = "https://schema.org"
+		item["@type"] = schemaType
+		suggestions = append(suggestions, SchemaSuggestion{
+			Type:   schemaType,
+			Reason: reason,
+			JSONLD: marshalJSONLD(item),
+		})
+	}
+
+	if !score.HasSchema {
+		if source.isArticle() {
+			add("Article", "No structured data found and the page looks like an article", source.article())
+		} else {
+			add("WebPage", "No structured data found", source.webPage())
+		}
+	}
+	if !score.HasOrganization {
+		add("Organization", "Missing Organization schema", source.organization())
+	}
+	if !score.HasBreadcrumb {
+		if item, ok := source.breadcrumbList(); ok {
+			add("BreadcrumbList", "Missing BreadcrumbList schema", item)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// isArticle reports whether the page looks like an article rather than a generic page
+func (s schemaSource) isArticle() bool {
+	return strings.EqualFold(s.OGType, "article") || (s.Published != "" && len(s.Authors) > 0)
+}
+
+func (s schemaSource) article() map[string]interface{} {
+	item := map[string]interface{}{
+		"headline":         truncateRunes(s.pageName(), 110),
+		"mainEntityOfPage": s.URL,
+	}
+	setIfPresent(item, "description", s.Description)
+	setIfPresent(item, "image", s.Image)
+	setIfPresent(item, "datePublished", s.Published)
+	setIfPresent(item, "dateModified", s.Modified)
+
+	authors := []interface{}{}
+	for i, name := range s.Authors {
+		author := map[string]interface{}{"@type": "Person", "name": name}
+		if i == 0 && s.AuthorURL != "" {
+			author["url"] = s.AuthorURL
+		}
+		authors = append(authors, author)
+	}
+	if len(authors) == 1 {
+		item["author"] = authors[0]
+	} else if len(authors) > 1 {
+		item["author"] = authors
+	}
+
+	publisher := map[string]interface{}{"@type": "Organization", "name": s.siteName()}
+	if s.Logo != "" {
+		publisher["logo"] = map[string]interface{}{"@type": "ImageObject", "url": s.Logo}
+	}
+	item["publisher"] = publisher
+	return item
+}
+
+func (s schemaSource) webPage() map[string]interface{} {
+	item := map[string]interface{}{
+		"name": s.pageName(),
+		"url":  s.URL,
+	}
+	setIfPresent(item, "description", s.Description)
+	if s.Image != "" {
+		item["primaryImageOfPage"] = map[string]interface{}{"@type": "ImageObject", "url": s.Image}
+	}
+	setIfPresent(item, "datePublished", s.Published)
+	setIfPresent(item, "dateModified", s.Modified)
+	return item
+}
+
+func (s schemaSource) organization() map[string]interface{} {
+	item := map[string]interface{}{
+		"name": s.siteName(),
+		"url":  s.origin(),
+	}
+	setIfPresent(item, "logo", s.Logo)
+	if len(s.SameAs) > 0 {
+		item["sameAs"] = s.SameAs
+	}
+	return item
+}
+
+// breadcrumbList builds a BreadcrumbList from the visible breadcrumbs, or from the URL path when there are none
+func (s schemaSource) breadcrumbList() (map[string]interface{}, bool) {
+	type crumb struct{ name, url string }
+	crumbs := []crumb{}
+	for _, link := range s.Breadcrumbs {
+		crumbs = append(crumbs, crumb{link.Text, link.URL})
+	}
+	if len(crumbs) > 0 && s.BreadcrumbEnd != "" {
+		crumbs = append(crumbs, crumb{s.BreadcrumbEnd, ""})
+	}
+
+	if len(crumbs) == 0 {
+		u, err := url.Parse(s.URL)
+		if err != nil || u.Host == "" {
+			return nil, false
+		}
+		segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+		if len(segments) == 0 {
+			return nil, false
+		}
+		crumbs = append(crumbs, crumb{"Home", s.origin() + "/"})
+		for i, segment := range segments {
+			name := segment
+			if i == len(segments)-1 && s.H1 != "" {
+				name = s.H1
+			} else if unescaped, err := url.PathUnescape(segment); err == nil {
+				name = capitalize(strings.NewReplacer("-", " ", "_", " ").Replace(unescaped))
+			}
+			crumbs = append(crumbs, crumb{name, s.origin() + "/" + strings.Join(segments[:i+1], "/")})
+		}
+	}
+
+	elements := []interface{}{}
+	for i, c := range crumbs {
+		element := map[string]interface{}{
+			"@type":    "ListItem",
+			"position": i + 1,
+			"name":     c.name,
+		}
+		setIfPresent(element, "item", c.url)
+		elements = append(elements, element)
+	}
+	return map[string]interface{}{"itemListElement": elements}, true
+}
+
+// pageName prefers the H1 and falls back to the title without the site name
+func (s schemaSource) pageName() string {
+	if s.H1 != "" {
+		return s.H1
+	}
+	name, _ := splitTitle(s.Title)
+	return name
+}
+
+// siteName prefers og:site_name, then the site part of the title, then the host name
+func (s schemaSource) siteName() string {
+	if s.SiteName != "" {
+		return s.SiteName
+	}
+	if _, site := splitTitle(s.Title); site != "" {
+		return site
+	}
+	if u, err := url.Parse(s.URL); err == nil && u.Hostname() != "" {
+		return strings.TrimPrefix(u.Hostname(), "www.")
+	}
+	return s.Title
+}
+
+func (s schemaSource) origin() string {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.Host == "" {
+		return s.URL
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// splitTitle splits "Page name | Brand" into its page and site parts
+func splitTitle(title string) (string, string) {
+	for _, separator := range titleSeparators {
+		if i := strings.LastIndex(title, separator); i > 0 {
+			return strings.TrimSpace(title[:i]), strings.TrimSpace(title[i+len(separator):])
+		}
+	}
+	return title, ""
+}
+
+func setIfPresent(item map[string]interface{}, name, value string) {
+	if value != "" {
+		item[name] = value
+	}
+}
+
+// truncateRunes shortens text to at most n runes
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:n]))
+}
+
+// marshalJSONLD formats an item as an indented JSON-LD document without HTML escaping
+func marshalJSONLD(item map[string]interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(item); err != nil {
+		return ""
+	}
+	// A literal "</script>" inside a value would end the script element early
+	return strings.ReplaceAll(strings.TrimSpace(buf.String()), "</", "<\\/")
+}
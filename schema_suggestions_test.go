@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitTitle(t *testing.T) {
+	tests := []struct {
+		title, page, site string
+	}{
+		{"Running Shoes | Acme", "Running Shoes", "Acme"},
+		{"Blog - Posts - Acme", "Blog - Posts", "Acme"},
+		{"Spring – Acme Store", "Spring", "Acme Store"},
+		{"Well-known brands", "Well-known brands", ""},
+		{"Acme", "Acme", ""},
+	}
+	for _, tt := range tests {
+		page, site := splitTitle(tt.title)
+		if page != tt.page || site != tt.site {
+			t.Errorf("splitTitle(%q) = %q, %q, want %q, %q", tt.title, page, site, tt.page, tt.site)
+		}
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"hello world", 6, "hello"},
+		{"café au lait", 4, "café"},
+	}
+	for _, tt := range tests {
+		if got := truncateRunes(tt.text, tt.n); got != tt.want {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.text, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaSourceSiteName(t *testing.T) {
+	tests := []struct {
+		name   string
+		source schemaSource
+		want   string
+	}{
+		{"og:site_name", schemaSource{SiteName: "Acme", Title: "Shoes | Store"}, "Acme"},
+		{"title", schemaSource{Title: "Shoes | Store", URL: "https://www.example.com/"}, "Store"},
+		{"host name", schemaSource{Title: "Shoes", URL: "https://www.example.com/"}, "example.com"},
+	}
+	for _, tt := range tests {
+		if got := tt.source.siteName(); got != tt.want {
+			t.Errorf("%s: siteName() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaSourceBreadcrumbList(t *testing.T) {
+	type crumb struct{ name, item string }
+	tests := []struct {
+		name   string
+		source schemaSource
+		want   []crumb
+	}{
+		{
+			name: "visible breadcrumbs",
+			source: schemaSource{
+				URL:           "https://example.com/shoes/trail",
+				Breadcrumbs:   []visibleBreadcrumb{{Text: "Home", URL: "https://example.com/"}, {Text: "Shoes", URL: "https://example.com/shoes"}},
+				BreadcrumbEnd: "Trail",
+			},
+			want: []crumb{{"Home", "https://example.com/"}, {"Shoes", "https://example.com/shoes"}, {"Trail", ""}},
+		},
+		{
+			name:   "from the URL path",
+			source: schemaSource{URL: "https://example.com/running-shoes/trail", H1: "Trail Running Shoes"},
+			want:   []crumb{{"Home", "https://example.com/"}, {"Running shoes", "https://example.com/running-shoes"}, {"Trail Running Shoes", "https://example.com/running-shoes/trail"}},
+		},
+		{
+			name:   "home page",
+			source: schemaSource{URL: "https://example.com/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, ok := tt.source.breadcrumbList()
+			if ok != (tt.want != nil) {
+				t.Fatalf("breadcrumbList() ok = %v", ok)
+			}
+			if !ok {
+				return
+			}
+			var got []crumb
+			for _, element := range item["itemListElement"].([]interface{}) {
+				e := element.(map[string]interface{})
+				link, _ := e["item"].(string)
+				got = append(got, crumb{e["name"].(string), link})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("breadcrumbList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Search result snippet fonts and the widths Google truncates at, in CSS pixels
+const (
+	serpTitleFont          = "20px Arial"
+	serpDescriptionFont    = "14px Arial"
+	serpTitleLimit         = 600
+	serpMobileTitle        = 660 // Two lines of the narrower mobile result
+	serpDesktopDescription = 920
+	serpMobileDescription  = 680
+)
+
+// Shortest title and meta description that make use of the snippet, in characters
+const (
+	minTitleLength           = 50
+	minMetaDescriptionLength = 150
+)
+
+// SERPSnippet simulates how the page appears in a search result
+type SERPSnippet struct {
+	Title            string      `json:"title"`
+	Description      string      `json:"description"`
+	DisplayURL       string      `json:"display_url"`
+	TitleWidth       float64     `json:"title_width"`       // Pixels in 20px Arial
+	DescriptionWidth float64     `json:"description_width"` // Pixels in 14px Arial
+	Desktop          SERPPreview `json:"desktop"`
+	Mobile           SERPPreview `json:"mobile"`
+	Image            string      `json:"image,omitempty"` // Rendered preview as a PNG data URI
+}
+
+// SERPPreview is the snippet text as displayed on one device, after truncation
+type SERPPreview struct {
+	Title                string `json:"title"`
+	Description          string `json:"description"`
+	TitleTruncated       bool   `json:"title_truncated"`
+	DescriptionTruncated bool   `json:"description_truncated"`
+}
+
+// serpMeasureScript measures text with canvas font metrics and truncates it at a word
+// boundary the way Google does. Headless Linux usually substitutes Liberation Sans for
+// Arial, which has the same metrics.
+const serpMeasureScript = `(input) => {
+	const context = document.createElement('canvas').getContext('2d');
+	const measure = (text, font) => {
+		context.font = font;
+		return context.measureText(text).width;
+	};
+	const truncate = (text, font, limit) => {
+		if (measure(text, font) <= limit) return { text: text, truncated: false };
+		const ellipsis = ' ...';
+		let fitted = '';
+		for (const word of text.split(/\s+/)) {
+			const next = fitted ? fitted + ' ' + word : word;
+			if (measure(next + ellipsis, font) > limit) break;
+			fitted = next;
+		}
+		if (!fitted) {
+			// A single overlong word is cut by character instead
+			for (const char of Array.from(text)) {
+				if (measure(fitted + char + ellipsis, font) > limit) break;
+				fitted += char;
+			}
+		}
+		return { text: fitted + ellipsis, truncated: true };
+	};
+	return {
+		titleWidth: measure(input.title, input.titleFont),
+		descriptionWidth: measure(input.description, input.descriptionFont),
+		desktopTitle: truncate(input.title, input.titleFont, input.titleLimit),
+		mobileTitle: truncate(input.title, input.titleFont, input.mobileTitleLimit),
+		desktopDescription: truncate(input.description, input.descriptionFont, input.desktopLimit),
+		mobileDescription: truncate(input.description, input.descriptionFont, input.mobileLimit)
+	};
+}`
+
+// serpMeasurement is what serpMeasureScript returns
+type serpMeasurement struct {
+	TitleWidth         float64       `json:"titleWidth"`
+	DescriptionWidth   float64       `json:"descriptionWidth"`
+	DesktopTitle       truncatedText `json:"desktopTitle"`
+	MobileTitle        truncatedText `json:"mobileTitle"`
+	DesktopDescription truncatedText `json:"desktopDescription"`
+	MobileDescription  truncatedText `json:"mobileDescription"`
+}
+
+type truncatedText struct {
+	Text      string `json:"text"`
+	Truncated bool   `json:"truncated"`
+}
+
+// simulateSERPSnippet measures the title and meta description in pixels, predicts their
+// truncation on desktop and mobile and renders a preview of the result
+func simulateSERPSnippet(page playwright.Page, targetURL, title, description string) (SERPSnippet, error) {
+	title = strings.Join(strings.Fields(title), " ")
+	description = strings.Join(strings.Fields(description), " ")
+	snippet := SERPSnippet{
+		Title:       title,
+		Description: description,
+		DisplayURL:  serpDisplayURL(targetURL),
+	}
+
+	result, err := page.Evaluate(serpMeasureScript, map[string]interface{}{
+		"title":            title,
+		"description":      description,
+		"titleFont":        serpTitleFont,
+		"descriptionFont":  serpDescriptionFont,
+		"titleLimit":       serpTitleLimit,
+		"mobileTitleLimit": serpMobileTitle,
+		"desktopLimit":     serpDesktopDescription,
+		"mobileLimit":      serpMobileDescription,
+	})
+	if err != nil {
+		return snippet, fmt.Errorf("could not measure snippet: %v", err)
+	}
+	var measured serpMeasurement
+	if err := decodeInto(result, &measured); err != nil {
+		return snippet, fmt.Errorf("could not measure snippet: %v", err)
+	}
+
+	snippet.TitleWidth = round1(measured.TitleWidth)
+	snippet.DescriptionWidth = round1(measured.DescriptionWidth)
+	snippet.Desktop = SERPPreview{
+		Title:                measured.DesktopTitle.Text,
+		Description:          measured.DesktopDescription.Text,
+		TitleTruncated:       measured.DesktopTitle.Truncated,
+		DescriptionTruncated: measured.DesktopDescription.Truncated,
+	}
+	snippet.Mobile = SERPPreview{
+		Title:                measured.MobileTitle.Text,
+		Description:          measured.MobileDescription.Text,
+		TitleTruncated:       measured.MobileTitle.Truncated,
+		DescriptionTruncated: measured.MobileDescription.Truncated,
+	}
+
+	if image, err := renderHTMLToPNG(page.Context(), serpPreviewHTML(snippet), 680); err == nil {
+		snippet.Image = image
+	}
+	return snippet, nil
+}
+
+// serpDisplayURL formats a URL the way search results show it, e.g. "example.com › blog › post"
+func serpDisplayURL(targetURL string) string {
+	u, err := url.Parse(targetURL)
+	if err != nil || u.Host == "" {
+		return targetURL
+	}
+	parts := []string{u.Scheme + "://" + u.Host}
+	for _, segment := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		if segment != "" {
+			if unescaped, err := url.PathUnescape(segment); err == nil {
+				segment = unescaped
+			}
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, " › ")
+}
+
+// serpPreviewHTML lays out the desktop and mobile results in Google's colors and fonts
+func serpPreviewHTML(snippet SERPSnippet) string {
+	result := func(label string, preview SERPPreview, width int) string {
+		description := preview.Description
+		if description == "" {
+			description = "No meta description: the search engine will pick text from the page."
+		}
+		return fmt.Sprintf(`<div class="label">%s</div><div class="result" style="width:%dpx"><div class="url">%s</div><div class="title">%s</div><div class="description">%s</div></div>`,
+			label, width, html.EscapeString(snippet.DisplayURL), html.EscapeString(preview.Title), html.EscapeString(description))
+	}
+	return `<!DOCTYPE html><html><head><meta charset="utf-8"><style>
+body { margin: 0; padding: 16px; background: #fff; font-family: Arial, sans-serif; }
+.label { font-size: 12px; color: #70757a; text-transform: uppercase; margin: 0 0 6px; }
+.result { margin-bottom: 24px; }
+.url { font-size: 14px; line-height: 20px; color: #202124; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+.title { font-size: 20px; line-height: 26px; color: #1a0dab; margin: 4px 0 3px; }
+.description { font-size: 14px; line-height: 22px; color: #4d5156; }
+</style></head><body>` +
+		result("Desktop", snippet.Desktop, serpTitleLimit) +
+		result("Mobile", snippet.Mobile, 360) +
+		`</body></html>`
+}
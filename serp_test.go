@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSERPDisplayURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/", "https://example.com"},
+		{"https://example.com/blog/my-post/", "https://example.com › blog › my-post"},
+		{"https://example.com/caf%C3%A9?x=1", "https://example.com › café"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := serpDisplayURL(tt.url); got != tt.want {
+			t.Errorf("serpDisplayURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSERPPreviewHTML(t *testing.T) {
+	snippet := SERPSnippet{
+		DisplayURL: "https://example.com › shoes",
+		Desktop:    SERPPreview{Title: "Shoes <new>", Description: "Fast & light"},
+		Mobile:     SERPPreview{Title: "Shoes <new>"},
+	}
+	page := serpPreviewHTML(snippet)
+	for _, want := range []string{
+		"Shoes &lt;new&gt;",
+		"Fast &amp; light",
+		"No meta description", // Mobile preview without a description
+		"https://example.com › shoes",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("serpPreviewHTML() doesn't contain %q", want)
+		}
+	}
+	if strings.Contains(page, "<new>") {
+		t.Errorf("serpPreviewHTML() doesn't escape the title")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// pageSnapshot captures the SEO-critical elements of a page so two renders can be compared
+type pageSnapshot struct {
+	Title           string         `json:"title"`
+	MetaDescription string         `json:"metaDescription"`
+	Canonical       string         `json:"canonical"`
+	RobotsMeta      string         `json:"robotsMeta"`
+	RobotsHeader    string         `json:"-"`
+	StatusCode      int            `json:"-"`
+	H1              []string       `json:"h1"`
+	Headings        []string       `json:"headings"`
+	Links           []string       `json:"links"`
+	JSONLD          []string       `json:"jsonLd"`
+	MainText        string         `json:"mainText"`
+	Hreflang        []HreflangLink `json:"hreflang"`
+}
+
+// snapshotScript extracts the SEO-critical elements from the current DOM
+const snapshotScript = `() => {
+	const meta = (name) => {
+		const el = document.querySelector('meta[name="' + name + '" i]');
+		return el ? (el.getAttribute('content') || '').trim() : '';
+	};
+	const canonical = document.querySelector('link[rel="canonical"]');
+	const robots = [meta('robots'), meta('googlebot')].filter(Boolean).join(', ');
+	const main = (` + mainContentFunction + `)();
+
+	return {
+		title: (document.title || '').trim(),
+		metaDescription: meta('description'),
+		canonical: canonical ? canonical.href : '',
+		robotsMeta: robots,
+		h1: Array.from(document.querySelectorAll('h1')).map(h => (h.textContent || '').trim()).filter(Boolean),
+		headings: Array.from(document.querySelectorAll('h1, h2, h3, h4, h5, h6'))
+			.map(h => h.tagName.toLowerCase() + ': ' + (h.textContent || '').trim().replace(/\s+/g, ' ')),
+		links: Array.from(new Set(Array.from(document.querySelectorAll('a[href]')).map(a => a.href))),
+		jsonLd: Array.from(document.querySelectorAll('script[type="application/ld+json"]')).map(s => (s.textContent || '').trim()),
+		mainText: main.text,
+		hreflang: ` + hreflangLinksExpression + `
+	};
+}`
+
+// takeSnapshot extracts a pageSnapshot from the page
+func takeSnapshot(page playwright.Page) (pageSnapshot, error) {
+	var snapshot pageSnapshot
+	err := evaluateInto(page, snapshotScript, &snapshot)
+	return snapshot, err
+}
+
+// cssSelectorFunction is a JavaScript function that builds a short CSS selector for an element,
+// anchored at the nearest ancestor with an id
+const cssSelectorFunction = `(el) => {
+	if (el === document.body) return 'body';
+	if (el.id) return '#' + CSS.escape(el.id);
+	const parts = [];
+	for (let node = el; node && node !== document.body && parts.length < 6; node = node.parentElement) {
+		if (node.id) {
+			parts.unshift('#' + CSS.escape(node.id));
+			break;
+		}
+		const siblings = node.parentElement ? Array.from(node.parentElement.children).filter(c => c.tagName === node.tagName) : [];
+		const tag = node.tagName.toLowerCase();
+		parts.unshift(siblings.length > 1 ? tag + ':nth-of-type(' + (siblings.indexOf(node) + 1) + ')' : tag);
+	}
+	return parts.join(' > ');
+}`
+
+// evaluateInto runs a script in the page and decodes its result into v
+func evaluateInto(page playwright.Page, script string, v interface{}) error {
+	result, err := page.Evaluate(script)
+	if err != nil {
+		return err
+	}
+	return decodeInto(result, v)
+}
+
+// decodeInto converts a value returned by page.Evaluate into v
+func decodeInto(result interface{}, v interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// normalizeText lowercases text and collapses whitespace so cosmetic differences are ignored
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// contentSimilarity returns the Jaccard similarity of the three-word shingles of two texts (0-1)
+func contentSimilarity(a, b string) float64 {
+	shinglesA := textShingles(a, 3)
+	shinglesB := textShingles(b, 3)
+	if len(shinglesA) == 0 && len(shinglesB) == 0 {
+		return 1
+	}
+
+	intersection := 0
+	for shingle := range shinglesA {
+		if shinglesB[shingle] {
+			intersection++
+		}
+	}
+	union := len(shinglesA) + len(shinglesB) - intersection
+
+	return float64(intersection) / float64(union)
+}
+
+// textShingles splits text into the set of its n-word sequences
+func textShingles(text string, n int) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	shingles := map[string]bool{}
+	if len(words) < n {
+		if len(words) > 0 {
+			shingles[strings.Join(words, " ")] = true
+		}
+		return shingles
+	}
+	for i := 0; i+n <= len(words); i++ {
+		shingles[strings.Join(words[i:i+n], " ")] = true
+	}
+
+	return shingles
+}
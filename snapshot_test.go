@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	if got := normalizeText("  Hello\n\tWorld  AGAIN "); got != "hello world again" {
+		t.Errorf("normalizeText() = %q", got)
+	}
+}
+
+func TestContentSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"identical", "the quick brown fox jumps", "the quick brown fox jumps", 1},
+		{"case and punctuation", "The quick, brown fox!", "the quick brown fox", 1},
+		{"both empty", "", "", 1},
+		{"one empty", "the quick brown fox", "", 0},
+		{"disjoint", "one two three four", "five six seven eight", 0},
+		{"half shared", "a b c d", "a b c e", 1.0 / 3},
+		{"short texts", "hello world", "hello world", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("contentSimilarity() = %.3f, want %.3f", got, tt.want)
+			}
+		})
+	}
+}
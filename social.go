@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxSocialImageSize is the largest share image any platform accepts, and how much is downloaded
+const maxSocialImageSize = 8 * 1024 * 1024
+
+// socialAspectTolerance is how far an image's aspect ratio may stray from a platform's before it is cropped
+const socialAspectTolerance = 0.1
+
+// SocialCardReport validates the Open Graph and Twitter Card values the page is shared with
+type SocialCardReport struct {
+	OGTitle               string             `json:"og_title"`
+	OGDescription         string             `json:"og_description"`
+	OGType                string             `json:"og_type"`
+	OGURL                 string             `json:"og_url"`
+	OGSiteName            string             `json:"og_site_name"`
+	Canonical             string             `json:"canonical"`
+	OGURLMatchesCanonical bool               `json:"og_url_matches_canonical"`
+	TwitterCard           string             `json:"twitter_card"`
+	TwitterImage          string             `json:"twitter_image"`          // Effective image, after falling back to og:image
+	TwitterImageFallback  bool               `json:"twitter_image_fallback"` // twitter:image is missing and og:image is used instead
+	Image                 *SocialImage       `json:"image,omitempty"`
+	TwitterImageDetails   *SocialImage       `json:"twitter_image_details,omitempty"` // Set when twitter:image differs from og:image
+	PlatformChecks        []SocialImageCheck `json:"platform_checks"`
+	Preview               string             `json:"preview,omitempty"` // Rendered share card as a PNG data URI
+}
+
+// SocialImage describes a share image as platforms fetch it
+type SocialImage struct {
+	URL         string  `json:"url"`
+	Absolute    bool    `json:"absolute"`
+	Loads       bool    `json:"loads"`
+	Status      int     `json:"status"`
+	ContentType string  `json:"content_type"`
+	Bytes       int64   `json:"bytes"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	AspectRatio float64 `json:"aspect_ratio"`
+	Alt         string  `json:"alt"`
+
+	data []byte
+}
+
+// SocialImageCheck compares the share image with one platform's recommendations
+type SocialImageCheck struct {
+	Platform    string `json:"platform"`
+	Image       string `json:"image"` // "og:image" or "twitter:image"
+	Recommended string `json:"recommended"`
+	Meets       bool   `json:"meets"`
+	Problem     string `json:"problem,omitempty"`
+}
+
+// socialPlatform holds a platform's share image requirements
+type socialPlatform struct {
+	name        string
+	minWidth    int
+	minHeight   int
+	recommended string
+	aspectRatio float64
+	maxBytes    int64
+}
+
+var (
+	facebookImage    = socialPlatform{"Facebook", 600, 315, "1200×630 (1.91:1)", 1.91, 8 * 1024 * 1024}
+	linkedInImage    = socialPlatform{"LinkedIn", 1200, 627, "1200×627 (1.91:1)", 1.91, 5 * 1024 * 1024}
+	xLargeImage      = socialPlatform{"X (summary_large_image)", 300, 157, "1200×600 (2:1)", 2, 5 * 1024 * 1024}
+	xSummaryImage    = socialPlatform{"X (summary)", 144, 144, "400×400 (1:1)", 1, 5 * 1024 * 1024}
+	validOGTypes     = []string{"website", "article", "book", "profile", "product", "video.movie", "video.episode", "video.tv_show", "video.other", "music.song", "music.album", "music.playlist", "music.radio_station"}
+	validTwitterCard = []string{"summary", "summary_large_image", "app", "player"}
+)
+
+// socialMetaScript reads the Open Graph and Twitter Card values and the canonical URL
+const socialMetaScript = `() => {
+	const meta = (key) => {
+		const el = document.querySelector('meta[property="' + key + '"], meta[name="' + key + '"]');
+		return el ? (el.getAttribute('content') || '').trim() : '';
+	};
+	const canonical = document.querySelector('link[rel="canonical"]');
+	return {
+		ogTitle: meta('og:title'),
+		ogDescription: meta('og:description'),
+		ogType: meta('og:type'),
+		ogURL: meta('og:url'),
+		ogSiteName: meta('og:site_name'),
+		ogImage: meta('og:image') || meta('og:image:url') || meta('og:image:secure_url'),
+		ogImageAlt: meta('og:image:alt'),
+		twitterCard: meta('twitter:card'),
+		twitterImage: meta('twitter:image') || meta('twitter:image:src'),
+		canonical: canonical ? canonical.href : ''
+	};
+}`
+
+// socialMeta is what socialMetaScript returns
+type socialMeta struct {
+	OGTitle       string `json:"ogTitle"`
+	OGDescription string `json:"ogDescription"`
+	OGType        string `json:"ogType"`
+	OGURL         string `json:"ogURL"`
+	OGSiteName    string `json:"ogSiteName"`
+	OGImage       string `json:"ogImage"`
+	OGImageAlt    string `json:"ogImageAlt"`
+	TwitterCard   string `json:"twitterCard"`
+	TwitterImage  string `json:"twitterImage"`
+	Canonical     string `json:"canonical"`
+}
+
+// imageSizeScript loads an image in a blank page and reports its natural size, for formats Go can't decode
+const imageSizeScript = `(src) => new Promise((resolve) => {
+	const img = new Image();
+	img.onload = () => resolve({ width: img.naturalWidth, height: img.naturalHeight });
+	img.onerror = () => resolve({ width: 0, height: 0 });
+	img.src = src;
+})`
+
+// auditSocialCard validates og:url, og:type, the share image and the Twitter Card fallbacks.
+// The image is only fetched when the network is available.
+func (a *SEOAuditor) auditSocialCard(page playwright.Page, target auditTarget) (SocialCardReport, []string) {
+	report := SocialCardReport{PlatformChecks: []SocialImageCheck{}}
+	issues := []string{}
+
+	var meta socialMeta
+	if err := evaluateInto(page, socialMetaScript, &meta); err != nil {
+		return report, issues
+	}
+	report.OGTitle = meta.OGTitle
+	report.OGDescription = meta.OGDescription
+	report.OGType = meta.OGType
+	report.OGURL = meta.OGURL
+	report.OGSiteName = meta.OGSiteName
+	report.Canonical = meta.Canonical
+	report.TwitterCard = meta.TwitterCard
+
+	// og:url is the URL shares are counted against, so it should be the canonical one
+	if report.OGURL != "" {
+		expected := report.Canonical
+		if expected == "" {
+			expected = target.url
+		}
+		report.OGURLMatchesCanonical = sameURL(report.OGURL, expected)
+		if !report.OGURLMatchesCanonical {
+			issues = append(issues, fmt.Sprintf("og:url %q doesn't match the canonical URL %q", report.OGURL, expected))
+		}
+	} else {
+		issues = append(issues, "Missing og:url")
+	}
+
+	if report.OGType == "" {
+		issues = append(issues, "Missing og:type (defaults to website)")
+	} else if !containsString(validOGTypes, report.OGType) {
+		issues = append(issues, fmt.Sprintf("Unknown og:type %q", report.OGType))
+	}
+
+	if report.TwitterCard != "" && !containsString(validTwitterCard, report.TwitterCard) {
+		issues = append(issues, fmt.Sprintf("Unknown twitter:card %q", report.TwitterCard))
+	}
+	report.TwitterImage = meta.TwitterImage
+	if report.TwitterImage == "" && meta.OGImage != "" {
+		report.TwitterImage = meta.OGImage
+		report.TwitterImageFallback = true
+	}
+	if report.TwitterCard != "" && report.TwitterImage == "" {
+		issues = append(issues, "Twitter Card has no image: neither twitter:image nor og:image is set")
+	}
+
+	xImage := xLargeImage
+	if report.TwitterCard == "summary" {
+		xImage = xSummaryImage
+	}
+	// X uses twitter:image when it is set, so og:image is only checked for X when it is the fallback
+	separateTwitterImage := meta.TwitterImage != "" && !sameURL(meta.TwitterImage, meta.OGImage)
+
+	if meta.OGImage != "" {
+		img := &SocialImage{URL: meta.OGImage, Alt: meta.OGImageAlt}
+		report.Image = img
+		if img.Alt == "" {
+			issues = append(issues, "Missing og:image:alt")
+		}
+		platforms := []socialPlatform{facebookImage, linkedInImage}
+		if !separateTwitterImage {
+			platforms = append(platforms, xImage)
+		}
+		issues = append(issues, a.checkSocialImage(page, img, "og:image", platforms, target, &report)...)
+	}
+
+	if separateTwitterImage {
+		img := &SocialImage{URL: meta.TwitterImage}
+		report.TwitterImageDetails = img
+		issues = append(issues, a.checkSocialImage(page, img, "twitter:image", []socialPlatform{xImage}, target, &report)...)
+	}
+
+	if image, err := renderHTMLToPNG(page.Context(), socialCardHTML(report, target.url), 540); err == nil {
+		report.Preview = image
+	}
+	return report, issues
+}
+
+// checkSocialImage validates a share image's URL and, when the network is available, fetches it and
+// compares it with the given platforms' requirements
+func (a *SEOAuditor) checkSocialImage(page playwright.Page, img *SocialImage, tag string, platforms []socialPlatform, target auditTarget, report *SocialCardReport) []string {
+	issues := []string{}
+	if u, err := url.Parse(img.URL); err == nil && u.IsAbs() && u.Host != "" {
+		img.Absolute = true
+	} else {
+		issues = append(issues, fmt.Sprintf("%s must be an absolute URL (%q)", tag, img.URL))
+	}
+	if !img.Absolute || !target.networkChecks {
+		return issues
+	}
+
+	if err := a.fetchSocialImage(img, target.opts, target.url); err != nil {
+		issues = append(issues, fmt.Sprintf("%s could not be loaded: %v", tag, err))
+	} else if img.Width == 0 {
+		// Formats the standard library can't decode, like WebP and AVIF, are measured by the browser
+		img.Width, img.Height = imageSizeInBrowser(page.Context(), img)
+		if img.Width == 0 {
+			issues = append(issues, fmt.Sprintf("%s could not be decoded", tag))
+		}
+	}
+	if img.Width > 0 && img.Height > 0 {
+		img.AspectRatio = round2(float64(img.Width) / float64(img.Height))
+		for _, platform := range platforms {
+			check := platform.check(img)
+			check.Image = tag
+			report.PlatformChecks = append(report.PlatformChecks, check)
+			if !check.Meets {
+				issues = append(issues, fmt.Sprintf("%s doesn't suit %s: %s", tag, check.Platform, check.Problem))
+			}
+		}
+	}
+	return issues
+}
+
+// fetchSocialImage downloads the share image the way a platform's crawler would and reads its size
+func (a *SEOAuditor) fetchSocialImage(img *SocialImage, opts AuditOptions, targetURL string) error {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, img.URL, nil)
+	if err != nil {
+		return err
+	}
+	opts.applyToRequest(req, targetURL)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	img.Status = resp.StatusCode
+	img.ContentType = resp.Header.Get("Content-Type")
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(img.ContentType, "image/") {
+		return fmt.Errorf("served as %q instead of an image", img.ContentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSocialImageSize+1))
+	if err != nil {
+		return err
+	}
+	img.Loads = true
+	img.Bytes = int64(len(data))
+	img.data = data
+
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		img.Width = config.Width
+		img.Height = config.Height
+	}
+	return nil
+}
+
+// imageSizeInBrowser measures a downloaded image in a scratch page, returning zeros when it can't be decoded
+func imageSizeInBrowser(context playwright.BrowserContext, img *SocialImage) (int, int) {
+	page, err := context.NewPage()
+	if err != nil {
+		return 0, 0
+	}
+	defer page.Close()
+
+	result, err := page.Evaluate(imageSizeScript, img.dataURI())
+	if err != nil {
+		return 0, 0
+	}
+	var size struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}
+	if err := decodeInto(result, &size); err != nil {
+		return 0, 0
+	}
+	return size.Width, size.Height
+}
+
+// dataURI embeds the downloaded image so it renders without network access
+func (img *SocialImage) dataURI() string {
+	contentType := strings.TrimSpace(strings.Split(img.ContentType, ";")[0])
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(img.data)
+}
+
+// check compares an image with the platform's minimum size, aspect ratio and file size
+func (p socialPlatform) check(img *SocialImage) SocialImageCheck {
+	check := SocialImageCheck{Platform: p.name, Recommended: p.recommended, Meets: true}
+	switch {
+	case img.Width < p.minWidth || img.Height < p.minHeight:
+		check.Problem = fmt.Sprintf("%d×%d is below the %d×%d minimum", img.Width, img.Height, p.minWidth, p.minHeight)
+	case img.Bytes > p.maxBytes:
+		check.Problem = fmt.Sprintf("%s exceeds the %s limit", formatBytes(img.Bytes), formatBytes(p.maxBytes))
+	case math.Abs(img.AspectRatio-p.aspectRatio)/p.aspectRatio > socialAspectTolerance:
+		check.Problem = fmt.Sprintf("aspect ratio %.2f:1 will be cropped to %s", img.AspectRatio, p.recommended)
+	}
+	check.Meets = check.Problem == ""
+	return check
+}
+
+// socialCardHTML lays out a link preview card as Facebook and LinkedIn show it
+func socialCardHTML(report SocialCardReport, targetURL string) string {
+	imageTag := `<div class="placeholder">No og:image</div>`
+	if report.Image != nil && report.Image.Loads {
+		imageTag = fmt.Sprintf(`<img src="%s" alt="">`, html.EscapeString(report.Image.dataURI()))
+	} else if report.Image != nil {
+		imageTag = `<div class="placeholder">og:image not loaded</div>`
+	}
+
+	domain := targetURL
+	if u, err := url.Parse(targetURL); err == nil && u.Host != "" {
+		domain = strings.TrimPrefix(u.Host, "www.")
+	}
+	title := report.OGTitle
+	if title == "" {
+		title = "Missing og:title"
+	}
+
+	return `<!DOCTYPE html><html><head><meta charset="utf-8"><style>
+body { margin: 0; padding: 20px; background: #f0f2f5; font-family: Helvetica, Arial, sans-serif; }
+.card { width: 500px; background: #fff; border: 1px solid #dadde1; }
+.card img, .placeholder { display: block; width: 500px; height: 262px; object-fit: cover; background: #e4e6eb; }
+.placeholder { line-height: 262px; text-align: center; color: #65676b; font-size: 14px; }
+.text { padding: 10px 12px; border-top: 1px solid #dadde1; background: #f2f3f5; }
+.domain { font-size: 12px; color: #606770; text-transform: uppercase; }
+.title { font-size: 16px; font-weight: bold; color: #1d2129; margin: 4px 0 2px; overflow: hidden; white-space: nowrap; text-overflow: ellipsis; }
+.description { font-size: 14px; color: #606770; overflow: hidden; white-space: nowrap; text-overflow: ellipsis; }
+</style></head><body><div class="card">` + imageTag +
+		`<div class="text"><div class="domain">` + html.EscapeString(domain) + `</div>` +
+		`<div class="title">` + html.EscapeString(title) + `</div>` +
+		`<div class="description">` + html.EscapeString(report.OGDescription) + `</div></div></div></body></html>`
+}
+
+// sameURL compares two URLs ignoring the case of the host and a trailing slash
+func sameURL(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) &&
+		strings.EqualFold(ua.Host, ub.Host) &&
+		strings.TrimSuffix(ua.Path, "/") == strings.TrimSuffix(ub.Path, "/") &&
+		ua.RawQuery == ub.RawQuery
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestSocialPlatformCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform socialPlatform
+		image    SocialImage
+		want     string
+	}{
+		{"recommended size", facebookImage, SocialImage{Width: 1200, Height: 630, AspectRatio: 1.9, Bytes: 200 * 1024}, ""},
+		{"too small", facebookImage, SocialImage{Width: 400, Height: 210, AspectRatio: 1.9}, "400×210 is below the 600×315 minimum"},
+		{"too large", linkedInImage, SocialImage{Width: 1200, Height: 627, AspectRatio: 1.91, Bytes: 6 * 1024 * 1024}, "6.0 MB exceeds the 5.0 MB limit"},
+		{"cropped", xLargeImage, SocialImage{Width: 1000, Height: 1000, AspectRatio: 1}, "aspect ratio 1.00:1 will be cropped to 1200×600 (2:1)"},
+		{"square summary", xSummaryImage, SocialImage{Width: 400, Height: 400, AspectRatio: 1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := tt.platform.check(&tt.image)
+			if check.Problem != tt.want || check.Meets != (tt.want == "") {
+				t.Errorf("check() = %q (meets %v), want %q", check.Problem, check.Meets, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameURL(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"https://example.com/page/", "https://EXAMPLE.com/page", true},
+		{"https://example.com/page?a=1", "https://example.com/page?a=1", true},
+		{"https://example.com/page?a=1", "https://example.com/page?a=2", false},
+		{"http://example.com/page", "https://example.com/page", false},
+		{"https://www.example.com/", "https://example.com/", false},
+	}
+	for _, tt := range tests {
+		if got := sameURL(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameURL(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
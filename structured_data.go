@@ -0,0 +1,457 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Structured data syntaxes recorded in SchemaEntity.Syntax
+const (
+	SyntaxJSONLD    = "json-ld"
+	SyntaxMicrodata = "microdata"
+	SyntaxRDFa      = "rdfa"
+)
+
+// SchemaEntity is a typed item extracted from structured data
+type SchemaEntity struct {
+	Types              []string `json:"types"`
+	ID                 string   `json:"id,omitempty"`
+	Syntax             string   `json:"syntax"`
+	Block              int      `json:"block"` // Index of the source block within its syntax
+	Path               string   `json:"path"`  // Location within the block, e.g. "Product.offers"
+	Properties         []string `json:"properties"`
+	MissingRequired    []string `json:"missing_required"`
+	MissingRecommended []string `json:"missing_recommended"`
+	Valid              bool     `json:"valid"`
+
+	props  map[string]interface{}
+	nested bool
+}
+
+// SchemaSyntaxError describes a structured data block that could not be parsed
+type SchemaSyntaxError struct {
+	Syntax  string `json:"syntax"`
+	Block   int    `json:"block"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Offset  int64  `json:"offset"`
+	Message string `json:"message"`
+}
+
+// structuredData holds every entity found on a page and an index of their @id values
+type structuredData struct {
+	entities []*SchemaEntity
+	ids      map[string]*SchemaEntity
+	errors   []SchemaSyntaxError
+}
+
+func newStructuredData() *structuredData {
+	return &structuredData{
+		ids: map[string]*SchemaEntity{},
+	}
+}
+
+// jsonLDScript returns the raw text of every JSON-LD block on the page
+const jsonLDScript = `() => Array.from(document.querySelectorAll('script[type="application/ld+json" i]')).map(s => s.textContent || '')`
+
+// extractJSONLD reads and parses every JSON-LD block on the page
+func extractJSONLD(page playwright.Page, data *structuredData) int {
+	var blocks []string
+	if err := evaluateInto(page, jsonLDScript, &blocks); err != nil {
+		return 0
+	}
+
+	for i, block := range blocks {
+		data.parseJSONLD(i, block)
+	}
+
+	return len(blocks)
+}
+
+// microdataScript converts every top-level microdata item into a JSON-LD shaped object
+const microdataScript = `() => {
+	const words = (value) => (value || '').trim().split(/\s+/).filter(Boolean);
+	const add = (item, name, value) => {
+		if (!(name in item)) item[name] = value;
+		else if (Array.isArray(item[name])) item[name].push(value);
+		else item[name] = [item[name], value];
+	};
+	const valueOf = (el) => {
+		const tag = el.tagName.toLowerCase();
+		if (tag === 'meta') return el.getAttribute('content') || '';
+		if (['audio', 'embed', 'iframe', 'img', 'source', 'track', 'video'].includes(tag)) return el.src || el.getAttribute('src') || '';
+		if (['a', 'area', 'link'].includes(tag)) return el.href || el.getAttribute('href') || '';
+		if (tag === 'object') return el.data || el.getAttribute('data') || '';
+		if (tag === 'data' || tag === 'meter') return el.getAttribute('value') || '';
+		if (tag === 'time') return el.getAttribute('datetime') || (el.textContent || '').trim();
+		if (el.hasAttribute('content')) return el.getAttribute('content');
+		return (el.textContent || '').trim().replace(/\s+/g, ' ');
+	};
+	const item = (root, seen) => {
+		const result = {};
+		if (seen.has(root)) return result;
+		seen.add(root);
+
+		const types = words(root.getAttribute('itemtype'));
+		if (types.length) result['@type'] = types;
+		if (root.getAttribute('itemid')) result['@id'] = root.getAttribute('itemid');
+
+		const props = [];
+		const crawl = (el) => {
+			for (const child of el.children) {
+				if (child.hasAttribute('itemprop')) props.push(child);
+				if (!child.hasAttribute('itemscope')) crawl(child);
+			}
+		};
+		crawl(root);
+		for (const id of words(root.getAttribute('itemref'))) {
+			const ref = document.getElementById(id);
+			if (!ref) continue;
+			if (ref.hasAttribute('itemprop')) props.push(ref);
+			if (!ref.hasAttribute('itemscope')) crawl(ref);
+		}
+
+		for (const el of props) {
+			const value = el.hasAttribute('itemscope') ? item(el, seen) : valueOf(el);
+			for (const name of words(el.getAttribute('itemprop'))) add(result, name, value);
+		}
+		return result;
+	};
+	return Array.from(document.querySelectorAll('[itemscope]:not([itemprop])')).map(el => item(el, new Set()));
+}`
+
+// rdfaScript converts every top-level RDFa Lite item into a JSON-LD shaped object
+const rdfaScript = `() => {
+	const words = (value) => (value || '').trim().split(/\s+/).filter(Boolean);
+	const clean = (name) => name.replace(/^(https?:\/\/schema\.org\/|schema:)/, '');
+	const add = (item, name, value) => {
+		if (!(name in item)) item[name] = value;
+		else if (Array.isArray(item[name])) item[name].push(value);
+		else item[name] = [item[name], value];
+	};
+	const valueOf = (el) => {
+		if (el.hasAttribute('content')) return el.getAttribute('content');
+		if (el.hasAttribute('resource')) return el.getAttribute('resource');
+		if (el.hasAttribute('href')) return el.href || el.getAttribute('href');
+		if (el.hasAttribute('src')) return el.src || el.getAttribute('src');
+		if (el.hasAttribute('datetime')) return el.getAttribute('datetime');
+		return (el.textContent || '').trim().replace(/\s+/g, ' ');
+	};
+	const item = (root) => {
+		const result = {};
+		const types = words(root.getAttribute('typeof')).map(clean);
+		if (types.length) result['@type'] = types;
+		if (root.getAttribute('resource')) result['@id'] = root.getAttribute('resource');
+
+		const crawl = (el) => {
+			for (const child of el.children) {
+				if (child.hasAttribute('property')) {
+					const value = child.hasAttribute('typeof') ? item(child) : valueOf(child);
+					for (const name of words(child.getAttribute('property'))) add(result, clean(name), value);
+				}
+				if (!child.hasAttribute('typeof')) crawl(child);
+			}
+		};
+		crawl(root);
+		return result;
+	};
+	return Array.from(document.querySelectorAll('[typeof]:not([property])')).map(item);
+}`
+
+// extractMicrodata reads every top-level microdata item on the page
+func extractMicrodata(page playwright.Page, data *structuredData) int {
+	return data.extractItems(page, microdataScript, SyntaxMicrodata)
+}
+
+// extractRDFa reads every top-level RDFa Lite item on the page
+func extractRDFa(page playwright.Page, data *structuredData) int {
+	return data.extractItems(page, rdfaScript, SyntaxRDFa)
+}
+
+// extractItems runs an extraction script returning JSON-LD shaped items and adds their entities
+func (d *structuredData) extractItems(page playwright.Page, script, syntax string) int {
+	var items []interface{}
+	if err := evaluateInto(page, script, &items); err != nil {
+		return 0
+	}
+
+	for i, item := range items {
+		d.walk(item, syntax, i, "", false)
+	}
+
+	return len(items)
+}
+
+// parseJSONLD parses one JSON-LD block and adds its entities
+func (d *structuredData) parseJSONLD(block int, content string) {
+	content = stripJSONLDWrapper(content)
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		d.errors = append(d.errors, newSchemaSyntaxError(block, content, err))
+		return
+	}
+
+	d.walk(value, SyntaxJSONLD, block, "", false)
+}
+
+// walk collects typed entities from a decoded structured data value
+func (d *structuredData) walk(value interface{}, syntax string, block int, path string, nested bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			d.walk(item, syntax, block, path, nested)
+		}
+	case map[string]interface{}:
+		// @graph holds a list of top-level items
+		if graph, ok := v["@graph"]; ok {
+			d.walk(graph, syntax, block, path, nested)
+		}
+
+		types := schemaTypes(v["@type"])
+		childPath := path
+		if len(types) > 0 {
+			entity := &SchemaEntity{
+				Types:              types,
+				Syntax:             syntax,
+				Block:              block,
+				Path:               path,
+				Properties:         []string{},
+				MissingRequired:    []string{},
+				MissingRecommended: []string{},
+				props:              v,
+				nested:             nested,
+			}
+			if entity.Path == "" {
+				entity.Path = types[0]
+			}
+			if id, ok := v["@id"].(string); ok {
+				entity.ID = id
+				if existing, ok := d.ids[id]; !ok || len(existing.props) < len(v) {
+					d.ids[id] = entity
+				}
+			}
+			for name := range v {
+				if !strings.HasPrefix(name, "@") {
+					entity.Properties = append(entity.Properties, name)
+				}
+			}
+			sort.Strings(entity.Properties)
+			d.entities = append(d.entities, entity)
+			childPath = entity.Path
+			nested = true
+		}
+
+		// Walk properties in a stable order so entity order doesn't change between runs
+		names := make([]string, 0, len(v))
+		for name := range v {
+			if !strings.HasPrefix(name, "@") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			next := name
+			if childPath != "" {
+				next = childPath + "." + name
+			}
+			d.walk(v[name], syntax, block, next, nested)
+		}
+	}
+}
+
+// validate checks every entity against the schema.org rule set
+func (d *structuredData) validate() {
+	for _, entity := range d.entities {
+		for _, t := range entity.Types {
+			rule, _, ok := schemaRuleFor(t)
+			if !ok {
+				continue
+			}
+			entity.MissingRequired = append(entity.MissingRequired, d.missingProperties(entity, rule.required)...)
+			entity.MissingRecommended = append(entity.MissingRecommended, d.missingProperties(entity, rule.recommended)...)
+		}
+		entity.Valid = len(entity.MissingRequired) == 0
+	}
+}
+
+// missingProperties returns the property rules the entity does not satisfy
+func (d *structuredData) missingProperties(entity *SchemaEntity, rules []string) []string {
+	missing := []string{}
+	for _, rule := range rules {
+		if !d.hasProperty(entity.props, rule) {
+			missing = append(missing, strings.ReplaceAll(rule, "|", " or "))
+		}
+	}
+	return missing
+}
+
+// hasProperty reports whether an item satisfies a property rule such as "offers.price" or "sku|gtin"
+func (d *structuredData) hasProperty(props map[string]interface{}, rule string) bool {
+	for _, alternative := range strings.Split(rule, "|") {
+		if d.hasPath(props, strings.Split(alternative, "."), 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPath follows a property path through nested items, arrays and @id references
+func (d *structuredData) hasPath(props map[string]interface{}, path []string, depth int) bool {
+	if depth > 10 {
+		return false
+	}
+	return d.valuePresent(props[path[0]], path[1:], depth)
+}
+
+func (d *structuredData) valuePresent(value interface{}, rest []string, depth int) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != "" && len(rest) == 0
+	case []interface{}:
+		for _, item := range v {
+			if d.valuePresent(item, rest, depth) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		v = d.resolve(v)
+		if len(rest) == 0 {
+			return len(v) > 0
+		}
+		return d.hasPath(v, rest, depth+1)
+	default:
+		return len(rest) == 0
+	}
+}
+
+// resolve replaces an {"@id": ...} reference with the item it points to, when it is on the page
+func (d *structuredData) resolve(item map[string]interface{}) map[string]interface{} {
+	id, ok := item["@id"].(string)
+	if !ok || len(item) > 2 {
+		return item
+	}
+	if target, ok := d.ids[id]; ok {
+		return target.props
+	}
+	return item
+}
+
+// unresolvedReferences lists same-page @id references ("#...") that point to nothing
+func (d *structuredData) unresolvedReferences() []string {
+	missing := []string{}
+	seen := map[string]bool{}
+	var check func(value interface{})
+	check = func(value interface{}) {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				check(item)
+			}
+		case map[string]interface{}:
+			if id, ok := v["@id"].(string); ok && len(v) == 1 && strings.Contains(id, "#") {
+				if _, found := d.ids[id]; !found && !seen[id] {
+					seen[id] = true
+					missing = append(missing, id)
+				}
+			}
+			for name, child := range v {
+				if !strings.HasPrefix(name, "@") {
+					check(child)
+				}
+			}
+		}
+	}
+	for _, entity := range d.entities {
+		if !entity.nested {
+			check(entity.props)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// schemaTypes normalizes an @type value into plain schema.org type names
+func schemaTypes(value interface{}) []string {
+	raw := []string{}
+	switch v := value.(type) {
+	case string:
+		raw = append(raw, v)
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+
+	types := []string{}
+	for _, t := range raw {
+		for _, prefix := range []string{"https://schema.org/", "http://schema.org/", "schema:"} {
+			t = strings.TrimPrefix(t, prefix)
+		}
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// stripJSONLDWrapper removes HTML comment and CDATA wrappers some CMSs put around JSON-LD
+func stripJSONLDWrapper(content string) string {
+	content = strings.TrimSpace(content)
+	for _, wrapper := range [][2]string{{"<!--", "-->"}, {"<![CDATA[", "]]>"}, {"//<![CDATA[", "//]]>"}} {
+		if strings.HasPrefix(content, wrapper[0]) && strings.HasSuffix(content, wrapper[1]) {
+			content = strings.TrimSpace(content[len(wrapper[0]) : len(content)-len(wrapper[1])])
+		}
+	}
+	return content
+}
+
+// newSchemaSyntaxError converts a JSON decoding error into a positioned syntax error
+func newSchemaSyntaxError(block int, content string, err error) SchemaSyntaxError {
+	syntaxErr := SchemaSyntaxError{
+		Syntax:  SyntaxJSONLD,
+		Block:   block,
+		Message: err.Error(),
+	}
+
+	var jsonSyntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &jsonSyntaxErr):
+		syntaxErr.Offset = jsonSyntaxErr.Offset
+	case errors.As(err, &typeErr):
+		syntaxErr.Offset = typeErr.Offset
+	default:
+		syntaxErr.Offset = int64(len(content))
+	}
+
+	syntaxErr.Line, syntaxErr.Column = 1, 1
+	for i, r := range content {
+		if int64(i) >= syntaxErr.Offset {
+			break
+		}
+		if r == '\n' {
+			syntaxErr.Line++
+			syntaxErr.Column = 1
+		} else {
+			syntaxErr.Column++
+		}
+	}
+
+	return syntaxErr
+}
+
+// String formats the error for issue lists
+func (e SchemaSyntaxError) String() string {
+	return fmt.Sprintf("%s block %d has a syntax error at line %d, column %d: %s", strings.ToUpper(e.Syntax), e.Block+1, e.Line, e.Column, e.Message)
+}
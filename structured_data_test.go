@@ -0,0 +1,165 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONLD(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantPaths   []string
+		wantNested  []bool
+		wantErrors  int
+		wantInvalid []string // Paths of entities missing required properties
+	}{
+		{
+			name:       "single product",
+			content:    `{"@context":"https://schema.org","@type":"Product","name":"Shoe","offers":{"@type":"Offer","price":"10","priceCurrency":"EUR"}}`,
+			wantPaths:  []string{"Product", "Product.offers"},
+			wantNested: []bool{false, true},
+		},
+		{
+			name:       "graph",
+			content:    `{"@context":"https://schema.org","@graph":[{"@type":"WebSite","url":"https://example.com"},{"@type":"Organization","name":"Example"}]}`,
+			wantPaths:  []string{"WebSite", "Organization"},
+			wantNested: []bool{false, false},
+		},
+		{
+			name:       "html comment wrapper",
+			content:    "<!--\n{\"@type\":\"Person\",\"name\":\"Ada\"}\n-->",
+			wantPaths:  []string{"Person"},
+			wantNested: []bool{false},
+		},
+		{
+			name:        "missing required property",
+			content:     `{"@type":"Person","url":"https://example.com/ada"}`,
+			wantPaths:   []string{"Person"},
+			wantNested:  []bool{false},
+			wantInvalid: []string{"Person"},
+		},
+		{
+			name:       "syntax error",
+			content:    `{"@type":"Product",}`,
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newStructuredData()
+			d.parseJSONLD(0, tt.content)
+			d.validate()
+
+			if len(d.errors) != tt.wantErrors {
+				t.Fatalf("errors = %d, want %d", len(d.errors), tt.wantErrors)
+			}
+			paths, nested, invalid := []string{}, []bool{}, []string{}
+			for _, entity := range d.entities {
+				paths = append(paths, entity.Path)
+				nested = append(nested, entity.nested)
+				if !entity.Valid {
+					invalid = append(invalid, entity.Path)
+				}
+			}
+			if tt.wantPaths == nil {
+				tt.wantPaths, tt.wantNested = []string{}, []bool{}
+			}
+			if tt.wantInvalid == nil {
+				tt.wantInvalid = []string{}
+			}
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if !reflect.DeepEqual(nested, tt.wantNested) {
+				t.Errorf("nested = %v, want %v", nested, tt.wantNested)
+			}
+			if !reflect.DeepEqual(invalid, tt.wantInvalid) {
+				t.Errorf("invalid = %v, want %v", invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestHasProperty(t *testing.T) {
+	d := newStructuredData()
+	d.parseJSONLD(0, `{"@type":"Product","name":"Shoe","gtin13":"123","offers":[{"@type":"Offer","price":"10"}],"brand":{"@id":"#brand"}}`)
+	d.parseJSONLD(1, `{"@type":"Brand","@id":"#brand","name":"Acme"}`)
+	product := d.entities[0]
+
+	tests := []struct {
+		rule string
+		want bool
+	}{
+		{"name", true},
+		{"sku|gtin13", true},
+		{"sku", false},
+		{"offers.price", true},
+		{"offers.priceCurrency", false},
+		{"brand.name", true}, // Resolved through @id
+	}
+	for _, tt := range tests {
+		if got := d.hasProperty(product.props, tt.rule); got != tt.want {
+			t.Errorf("hasProperty(%q) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaTypeIs(t *testing.T) {
+	tests := []struct {
+		schemaType, ancestor string
+		want                 bool
+	}{
+		{"Product", "Product", true},
+		{"NewsArticle", "Article", true},
+		{"Restaurant", "LocalBusiness", true},
+		{"Restaurant", "Organization", true},
+		{"Product", "Article", false},
+	}
+	for _, tt := range tests {
+		if got := schemaTypeIs(tt.schemaType, tt.ancestor); got != tt.want {
+			t.Errorf("schemaTypeIs(%q, %q) = %v, want %v", tt.schemaType, tt.ancestor, got, tt.want)
+		}
+	}
+}
+
+func TestWalkExtractedItems(t *testing.T) {
+	// Shaped like the output of microdataScript and rdfaScript
+	microdata := map[string]interface{}{
+		"@type": []interface{}{"https://schema.org/Product"},
+		"name":  "Shoe",
+		"offers": map[string]interface{}{
+			"@type":         []interface{}{"http://schema.org/Offer"},
+			"price":         "10",
+			"priceCurrency": "EUR",
+		},
+	}
+	rdfa := map[string]interface{}{
+		"@type": []interface{}{"Person"},
+		"@id":   "#ada",
+		"name":  "Ada",
+	}
+
+	d := newStructuredData()
+	d.walk(microdata, SyntaxMicrodata, 0, "", false)
+	d.walk(rdfa, SyntaxRDFa, 0, "", false)
+
+	want := []struct {
+		path, syntax, id string
+		types            []string
+	}{
+		{"Product", SyntaxMicrodata, "", []string{"Product"}},
+		{"Product.offers", SyntaxMicrodata, "", []string{"Offer"}},
+		{"Person", SyntaxRDFa, "#ada", []string{"Person"}},
+	}
+	if len(d.entities) != len(want) {
+		t.Fatalf("got %d entities, want %d", len(d.entities), len(want))
+	}
+	for i, w := range want {
+		entity := d.entities[i]
+		if entity.Path != w.path || entity.Syntax != w.syntax || entity.ID != w.id || !reflect.DeepEqual(entity.Types, w.types) {
+			t.Errorf("entity %d = %s %s %q %v, want %s %s %q %v", i, entity.Path, entity.Syntax, entity.ID, entity.Types, w.path, w.syntax, w.id, w.types)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Topic extraction limits
+const (
+	maxTopicTerms    = 10  // Terms reported per page
+	maxTopicNGram    = 3   // Longest phrase considered, in words
+	topicCoverage    = 3   // The title and H1 should mention one of this many top terms
+	minTopicWords    = 100 // Shorter content has no reliable topic
+	minTopicCount    = 2   // Terms must occur at least this often
+	topicPhraseBoost = 0.5 // Extra weight per additional word, since phrases are more specific than words
+)
+
+// TopicReport lists the most distinctive terms of the main content and whether the title and H1 reflect them
+type TopicReport struct {
+	Terms              []TopicTerm `json:"terms"`
+	DominantTopic      string      `json:"dominant_topic"`
+	Title              string      `json:"title"`
+	H1                 string      `json:"h1"`
+	TitleReflectsTopic bool        `json:"title_reflects_topic"`
+	H1ReflectsTopic    bool        `json:"h1_reflects_topic"`
+}
+
+// TopicTerm is a word or phrase ranked by TF-IDF against documentFrequencies
+type TopicTerm struct {
+	Term        string  `json:"term"` // Most frequent surface form
+	Words       int     `json:"words"`
+	Occurrences int     `json:"occurrences"`
+	Score       float64 `json:"score"`
+}
+
+// topicStopwords extend languageStopwords with the pronouns, auxiliaries and fillers that never make a topic
+var topicStopwords = map[string][]string{
+	"en": {"a", "i", "me", "my", "he", "she", "him", "his", "her", "its", "them", "us", "do", "does", "did", "had", "having", "should", "could", "may", "might", "must", "shall", "so", "no", "just", "only", "very", "into", "out", "up", "down", "over", "then", "who", "whom", "whose", "where", "why", "here", "each", "any", "some", "such", "own", "same", "too", "other", "being", "am", "those", "after", "before", "under", "again", "further", "once", "both", "few", "most", "nor", "off", "through", "while", "above", "below", "between", "against", "during", "until", "yours", "ours", "theirs", "itself", "myself", "get", "got", "one", "many", "much", "even", "well", "way", "like", "make", "use", "new", "need", "want", "know", "see", "let", "don't", "it's", "i'm", "you're", "we're", "that's", "can't", "won't"},
+	"es": {"mi", "tu", "te", "su", "mis", "tus", "ser", "fue", "era", "ha", "han", "he", "había", "si", "así", "aquí", "cada", "bien", "solo", "sólo", "puedes", "pueden", "hacer", "tan", "vez"},
+	"pt": {"meu", "teu", "te", "lhe", "ser", "são", "sendo", "aqui", "cada", "bem", "pode", "podem", "fazer", "tão", "vez", "este", "esta", "isto", "aquele", "aquela"},
+	"fr": {"a", "ai", "as", "mon", "ma", "mes", "ton", "ta", "tes", "lui", "leurs", "cet", "ici", "chaque", "fait", "peuvent", "était", "sera", "ça", "cela", "ceci"},
+	"de": {"ihm", "ihn", "ihnen", "mein", "meine", "dein", "deine", "sein", "seine", "hier", "jede", "jeder", "jedes", "gibt", "wurden", "waren", "alle", "alles", "schon", "immer", "dann", "viel", "viele"},
+	"it": {"mio", "mia", "tuo", "tua", "ci", "vi", "ne", "qui", "ogni", "bene", "può", "possono", "fatto", "così", "questi", "queste", "quella", "quelli"},
+	"nl": {"mijn", "jouw", "zijn", "haar", "hier", "elke", "ieder", "goed", "alle", "alles", "kunnen", "moet", "moeten", "zal", "zou", "was", "waren", "veel"},
+}
+
+// documentFrequencies holds approximate document frequencies of words common on the web: how many of
+// documentFrequencyScale pages contain each word. English has the longest table; other languages only cover
+// site chrome and the most frequent nouns, and words missing from a table count as appearing on no page.
+var documentFrequencies = map[string]map[string]int{
+	"en": {
+		"home": 900, "contact": 900, "search": 900, "privacy": 900, "policy": 900, "more": 900, "about": 900, "new": 800,
+		"terms": 800, "cookies": 800, "cookie": 800, "menu": 800, "page": 800, "information": 800, "services": 700,
+		"service": 700, "help": 700, "site": 700, "email": 700, "account": 700, "login": 700, "copyright": 700, "reserved": 700,
+		"rights": 700, "time": 600, "free": 600, "best": 600, "find": 600, "read": 600, "view": 600, "news": 600,
+		"subscribe": 600, "newsletter": 600, "share": 600, "follow": 600, "learn": 600, "first": 500, "day": 500, "year": 500,
+		"years": 500, "work": 500, "good": 500, "world": 500, "business": 500, "online": 500, "data": 500, "click": 500,
+		"support": 500, "team": 500, "blog": 500, "update": 500, "updated": 500, "post": 420, "posted": 420, "comments": 420,
+		"comment": 420, "reply": 420, "back": 420, "top": 420, "previous": 420, "related": 420, "articles": 420, "article": 420,
+		"product": 420, "products": 420, "price": 420, "shop": 420, "cart": 420, "order": 420, "shipping": 420, "customer": 420,
+		"customers": 420, "company": 360, "people": 360, "number": 360, "life": 360, "part": 360, "place": 360, "system": 360,
+		"group": 360, "case": 360, "point": 360, "program": 360, "week": 360, "question": 360, "right": 360, "high": 360,
+		"small": 360, "large": 360, "next": 360, "last": 360, "long": 360, "great": 360, "little": 300, "old": 300, "big": 300,
+		"different": 300, "important": 300, "public": 300, "able": 300, "use": 300, "user": 300, "users": 300, "website": 300,
+		"web": 300, "internet": 300, "content": 300, "link": 300, "links": 300, "list": 300, "type": 300, "example": 300,
+		"form": 300, "name": 300, "address": 300, "phone": 300, "open": 300, "today": 300, "month": 250, "months": 250,
+		"days": 250, "hours": 250, "minutes": 250, "way": 250, "thing": 250, "things": 250, "family": 250, "friend": 250,
+		"friends": 250, "community": 250, "market": 250, "money": 250, "job": 250, "jobs": 250, "health": 250, "care": 250,
+		"quality": 250, "review": 250, "reviews": 250, "man": 200, "woman": 200, "child": 200, "children": 200, "school": 200,
+		"student": 200, "students": 200, "country": 200, "state": 200, "city": 200, "area": 200, "house": 200, "room": 200,
+		"car": 200, "book": 200, "books": 200, "game": 200, "games": 200, "music": 200, "video": 200, "videos": 200,
+		"photo": 200, "photos": 200, "image": 200, "images": 200, "story": 170, "stories": 170, "idea": 170, "ideas": 170,
+		"experience": 170, "process": 170, "project": 170, "projects": 170, "result": 170, "results": 170, "research": 170,
+		"report": 170, "level": 170, "member": 170, "members": 170, "power": 170, "change": 170, "changes": 170,
+		"development": 170, "management": 170, "design": 170, "security": 170, "access": 170, "social": 170, "media": 170,
+		"local": 170, "national": 140, "international": 140, "global": 140, "general": 140, "special": 140, "personal": 140,
+		"full": 140, "easy": 140, "simple": 140, "real": 140, "sure": 140, "possible": 140, "available": 140, "current": 140,
+		"recent": 140, "early": 140, "late": 140, "major": 140, "main": 140, "common": 140, "certain": 140, "whole": 140,
+		"low": 140, "short": 140, "true": 140, "start": 120, "end": 120, "making": 120, "provide": 120, "including": 120,
+		"based": 120, "used": 120, "using": 120, "make": 120, "take": 120, "give": 120, "show": 120, "keep": 120, "call": 120,
+		"try": 120, "ask": 120, "feel": 120, "become": 120, "leave": 120, "put": 120, "mean": 120, "seem": 120, "turn": 120,
+		"hold": 120, "bring": 120, "write": 120, "sit": 90, "stand": 90, "lose": 90, "pay": 90, "meet": 90, "include": 90,
+		"continue": 90, "set": 90, "lead": 90, "understand": 90, "watch": 90, "create": 90, "offer": 90, "remember": 90,
+		"love": 90, "consider": 90, "appear": 90, "buy": 90, "wait": 90, "serve": 90, "send": 90, "expect": 90, "build": 90,
+		"stay": 90, "fall": 90, "reach": 90, "remain": 90, "suggest": 90, "raise": 90, "pass": 90, "sell": 90, "require": 90,
+		"decide": 90, "pull": 90, "inc": 80, "llc": 80, "ltd": 80, "faq": 80, "sign": 80, "register": 80, "download": 80,
+		"app": 80, "mobile": 80, "version": 80, "details": 80, "features": 80, "feature": 80, "options": 80, "option": 80,
+		"solutions": 80, "solution": 80, "resources": 80, "tools": 80, "tool": 80, "guide": 80, "tips": 80, "overview": 80,
+		"introduction": 80, "government": 60, "food": 60, "water": 60,
+	},
+	"es": {
+		"inicio": 850, "contacto": 850, "privacidad": 850, "política": 850, "cookies": 850, "buscar": 850, "más": 850,
+		"página": 850, "información": 600, "servicios": 600, "servicio": 600, "nuevo": 600, "nueva": 600, "noticias": 600,
+		"leer": 600, "año": 450, "años": 450, "día": 450, "días": 450, "tiempo": 450, "mejor": 450, "gran": 450,
+		"producto": 450, "productos": 450, "precio": 450, "cliente": 450, "clientes": 450, "empresa": 450, "vida": 250,
+		"parte": 250, "casa": 250, "mundo": 250, "trabajo": 250, "país": 250, "gobierno": 120,
+	},
+	"pt": {
+		"início": 850, "contato": 850, "privacidade": 850, "política": 850, "cookies": 850, "buscar": 850, "página": 850,
+		"informação": 600, "informações": 600, "serviços": 600, "serviço": 600, "novo": 600, "nova": 600, "notícias": 600,
+		"ler": 600, "ano": 450, "anos": 450, "dia": 450, "dias": 450, "tempo": 450, "melhor": 450, "grande": 450,
+		"produto": 450, "produtos": 450, "preço": 450, "cliente": 450, "clientes": 450, "empresa": 450, "vida": 250,
+		"parte": 250, "casa": 250, "mundo": 250, "trabalho": 250, "país": 250, "governo": 120,
+	},
+	"fr": {
+		"accueil": 850, "contact": 850, "confidentialité": 850, "politique": 850, "cookies": 850, "rechercher": 850,
+		"page": 850, "information": 600, "informations": 600, "services": 600, "service": 600, "nouveau": 600, "nouvelle": 600,
+		"actualités": 600, "lire": 600, "an": 450, "ans": 450, "année": 450, "jour": 450, "jours": 450, "temps": 450,
+		"meilleur": 450, "grand": 450, "produit": 450, "produits": 450, "prix": 450, "client": 450, "clients": 450,
+		"entreprise": 450, "vie": 250, "partie": 250, "maison": 250, "monde": 250, "travail": 250, "pays": 250,
+		"gouvernement": 120,
+	},
+	"de": {
+		"startseite": 850, "kontakt": 850, "datenschutz": 850, "impressum": 850, "cookies": 850, "suche": 850, "seite": 850,
+		"informationen": 600, "leistungen": 600, "service": 600, "neu": 600, "neue": 600, "nachrichten": 600, "lesen": 600,
+		"jahr": 450, "jahre": 450, "tag": 450, "tage": 450, "zeit": 450, "besten": 450, "groß": 450, "produkt": 450,
+		"produkte": 450, "preis": 450, "kunden": 450, "unternehmen": 450, "leben": 250, "teil": 250, "haus": 250, "welt": 250,
+		"arbeit": 250, "land": 250, "regierung": 120,
+	},
+	"it": {
+		"home": 850, "contatti": 850, "privacy": 850, "politica": 850, "cookie": 850, "cerca": 850, "pagina": 850,
+		"informazioni": 600, "servizi": 600, "servizio": 600, "nuovo": 600, "nuova": 600, "notizie": 600, "leggi": 600,
+		"anno": 450, "anni": 450, "giorno": 450, "giorni": 450, "tempo": 450, "migliore": 450, "grande": 450, "prodotto": 450,
+		"prodotti": 450, "prezzo": 450, "cliente": 450, "clienti": 450, "azienda": 450, "vita": 250, "parte": 250, "casa": 250,
+		"mondo": 250, "lavoro": 250, "paese": 250, "governo": 120,
+	},
+	"nl": {
+		"home": 850, "contact": 850, "privacybeleid": 850, "cookies": 850, "zoeken": 850, "pagina": 850, "informatie": 600,
+		"diensten": 600, "dienst": 600, "nieuw": 600, "nieuwe": 600, "nieuws": 600, "lees": 600, "jaar": 450, "jaren": 450,
+		"dag": 450, "dagen": 450, "tijd": 450, "beste": 450, "groot": 450, "product": 450, "producten": 450, "prijs": 450,
+		"klant": 450, "klanten": 450, "bedrijf": 450, "leven": 250, "deel": 250, "huis": 250, "wereld": 250, "werk": 250,
+		"land": 250, "regering": 120,
+	},
+}
+
+// extractTopics finds the dominant terms of the main content and checks them against the title and H1
+func extractTopics(page playwright.Page, content MainContent, lang string) (TopicReport, []string) {
+	report := TopicReport{Terms: []TopicTerm{}}
+	issues := []string{}
+	if content.WordCount < minTopicWords {
+		return report, issues
+	}
+
+	report.Terms = topTerms(strings.Split(content.Text, "\n"), lang)
+	if len(report.Terms) == 0 {
+		return report, issues
+	}
+	report.DominantTopic = report.Terms[0].Term
+
+	var sources keywordSources
+	if err := evaluateInto(page, keywordSourcesScript, &sources); err != nil {
+		return report, issues
+	}
+	report.Title = sources.Title
+	if len(sources.H1) > 0 {
+		report.H1 = sources.H1[0]
+	}
+
+	covered := report.Terms
+	if len(covered) > topicCoverage {
+		covered = covered[:topicCoverage]
+	}
+	report.TitleReflectsTopic = mentionsAnyTerm(report.Title, covered, lang)
+	report.H1ReflectsTopic = mentionsAnyTerm(report.H1, covered, lang)
+
+	if report.Title != "" && !report.TitleReflectsTopic {
+		issues = append(issues, fmt.Sprintf("Title doesn't mention the main topic of the content (%q)", report.DominantTopic))
+	}
+	if report.H1 != "" && !report.H1ReflectsTopic {
+		issues = append(issues, fmt.Sprintf("H1 doesn't mention the main topic of the content (%q)", report.DominantTopic))
+	}
+	return report, issues
+}
+
+// topicCandidate accumulates the occurrences of one stemmed n-gram
+type topicCandidate struct {
+	stems []string
+	count int
+	forms map[string]int
+}
+
+// topTerms ranks the words and phrases of the text by TF-IDF. Phrases never span a stopword,
+// a number or a line break, and a term overlapping a better ranked one is dropped.
+func topTerms(lines []string, lang string) []TopicTerm {
+	stopwords := topicStopwordSets[lang]
+	candidates := map[string]*topicCandidate{}
+	for _, line := range lines {
+		tokens := keywordTokens(line, lang)
+		run := []keywordToken{}
+		flush := func() {
+			for n := 1; n <= maxTopicNGram; n++ {
+				for i := 0; i+n <= len(run); i++ {
+					gram := run[i : i+n]
+					stems := make([]string, n)
+					for j, token := range gram {
+						stems[j] = token.stem
+					}
+					key := strings.Join(stems, " ")
+					candidate, ok := candidates[key]
+					if !ok {
+						candidate = &topicCandidate{stems: stems, forms: map[string]int{}}
+						candidates[key] = candidate
+					}
+					candidate.count++
+					candidate.forms[joinTokenWords(gram)]++
+				}
+			}
+			run = run[:0]
+		}
+		for _, token := range tokens {
+			if stopwords[token.word] || isDigits(token.word) || len([]rune(token.word)) < 2 {
+				flush()
+				continue
+			}
+			run = append(run, token)
+		}
+		flush()
+	}
+
+	terms := []TopicTerm{}
+	scored := map[string][]string{}
+	for _, candidate := range candidates {
+		if candidate.count < minTopicCount {
+			continue
+		}
+		idf := 0.0
+		for _, stem := range candidate.stems {
+			idf += inverseDocumentFrequency(stem, lang)
+		}
+		idf /= float64(len(candidate.stems))
+		weight := 1 + topicPhraseBoost*float64(len(candidate.stems)-1)
+
+		term := TopicTerm{
+			Term:        mostFrequentForm(candidate.forms),
+			Words:       len(candidate.stems),
+			Occurrences: candidate.count,
+			Score:       round2(float64(candidate.count) * idf * weight),
+		}
+		terms = append(terms, term)
+		scored[term.Term] = candidate.stems
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Score != terms[j].Score {
+			return terms[i].Score > terms[j].Score
+		}
+		return terms[i].Term < terms[j].Term
+	})
+
+	selected := []TopicTerm{}
+	for _, term := range terms {
+		overlaps := false
+		for _, chosen := range selected {
+			if containsStems(scored[chosen.Term], scored[term.Term]) || containsStems(scored[term.Term], scored[chosen.Term]) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		selected = append(selected, term)
+		if len(selected) == maxTopicTerms {
+			break
+		}
+	}
+	return selected
+}
+
+// documentFrequencyScale is the number of pages the counts in documentFrequencies are out of
+const documentFrequencyScale = 1000
+
+// inverseDocumentFrequency returns log(N/(df+1)) for a stem, using the English table for languages without one
+func inverseDocumentFrequency(stem, lang string) float64 {
+	frequencies, ok := stemDocumentFrequencies[lang]
+	if !ok {
+		frequencies = stemDocumentFrequencies["en"]
+	}
+	return math.Log(float64(documentFrequencyScale) / float64(frequencies[stem]+1))
+}
+
+// stemDocumentFrequencies indexes documentFrequencies by stem, keeping the highest frequency of words sharing a stem
+var stemDocumentFrequencies = func() map[string]map[string]int {
+	stems := map[string]map[string]int{}
+	for lang, words := range documentFrequencies {
+		stems[lang] = map[string]int{}
+		for word, frequency := range words {
+			stem := lightStem(diacriticFolds.Replace(word), lang)
+			if frequency > stems[lang][stem] {
+				stems[lang][stem] = frequency
+			}
+		}
+	}
+	return stems
+}()
+
+// topicStopwordSets combines languageStopwords and topicStopwords for lookups
+var topicStopwordSets = func() map[string]map[string]bool {
+	sets := map[string]map[string]bool{}
+	for code, words := range languageStopwords {
+		sets[code] = map[string]bool{}
+		for _, word := range append(words, topicStopwords[code]...) {
+			sets[code][word] = true
+		}
+	}
+	return sets
+}()
+
+// mentionsAnyTerm reports whether text contains any of the terms, compared by stem
+func mentionsAnyTerm(text string, terms []TopicTerm, lang string) bool {
+	if text == "" {
+		return false
+	}
+	for _, term := range terms {
+		if matchKeyword(text, keywordTokens(term.Term, lang), lang).Found {
+			return true
+		}
+	}
+	return false
+}
+
+// containsStems reports whether the stem sequence inner appears contiguously in outer
+func containsStems(outer, inner []string) bool {
+	for i := 0; i+len(inner) <= len(outer); i++ {
+		if strings.Join(outer[i:i+len(inner)], " ") == strings.Join(inner, " ") {
+			return true
+		}
+	}
+	return false
+}
+
+func mostFrequentForm(forms map[string]int) string {
+	best := ""
+	for form, count := range forms {
+		if best == "" || count > forms[best] || (count == forms[best] && form < best) {
+			best = form
+		}
+	}
+	return best
+}
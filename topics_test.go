@@ -0,0 +1,100 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+var espressoLines = []string{
+	"The espresso machine heats water quickly.",
+	"Clean the espresso machine every week.",
+	"An espresso machine needs fresh water and time.",
+	"Many people buy an espresso machine for the kitchen, and people use it daily for 2 years.",
+}
+
+func TestTopTerms(t *testing.T) {
+	terms := topTerms(espressoLines, "en")
+
+	// Words inside a better ranked phrase are dropped, and common words rank below rare ones
+	got := []string{}
+	for _, term := range terms {
+		got = append(got, term.Term)
+	}
+	want := []string{"espresso machine", "water", "people"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("topTerms() = %q, want %q", got, want)
+	}
+	if terms[0].Words != 2 || terms[0].Occurrences != 4 {
+		t.Errorf("espresso machine = %+v, want 2 words and 4 occurrences", terms[0])
+	}
+	if terms[1].Score <= terms[2].Score {
+		t.Errorf("water scored %.2f, not above people (%.2f)", terms[1].Score, terms[2].Score)
+	}
+}
+
+func TestMentionsAnyTerm(t *testing.T) {
+	terms := []TopicTerm{{Term: "espresso machine"}, {Term: "water"}}
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Best Espresso Machines of 2024", true},
+		{"Waters of the world", true},
+		{"Coffee grinders", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := mentionsAnyTerm(tt.text, terms, "en"); got != tt.want {
+			t.Errorf("mentionsAnyTerm(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestContainsStems(t *testing.T) {
+	tests := []struct {
+		outer, inner []string
+		want         bool
+	}{
+		{[]string{"espresso", "machin"}, []string{"espresso"}, true},
+		{[]string{"espresso", "machin"}, []string{"machin"}, true},
+		{[]string{"espresso", "machin", "clean"}, []string{"espresso", "clean"}, false},
+		{[]string{"espresso"}, []string{"espresso", "machin"}, false},
+	}
+	for _, tt := range tests {
+		if got := containsStems(tt.outer, tt.inner); got != tt.want {
+			t.Errorf("containsStems(%q, %q) = %v, want %v", tt.outer, tt.inner, got, tt.want)
+		}
+	}
+}
+
+func TestMostFrequentForm(t *testing.T) {
+	tests := []struct {
+		forms map[string]int
+		want  string
+	}{
+		{map[string]int{"shoes": 3, "shoe": 1}, "shoes"},
+		{map[string]int{"shoes": 2, "shoe": 2}, "shoe"}, // Ties go to the alphabetically first form
+		{map[string]int{}, ""},
+	}
+	for _, tt := range tests {
+		if got := mostFrequentForm(tt.forms); got != tt.want {
+			t.Errorf("mostFrequentForm(%v) = %q, want %q", tt.forms, got, tt.want)
+		}
+	}
+}
+
+func TestInverseDocumentFrequency(t *testing.T) {
+	common := inverseDocumentFrequency(lightStem("privacy", "en"), "en")
+	rare := inverseDocumentFrequency(lightStem("espresso", "en"), "en")
+	if want := math.Log(documentFrequencyScale); math.Abs(rare-want) > 1e-9 {
+		t.Errorf("idf(espresso) = %.3f, want %.3f for a word on no page", rare, want)
+	}
+	if common >= rare {
+		t.Errorf("idf(privacy) = %.3f, want below idf(espresso) = %.3f", common, rare)
+	}
+	// Languages without a table use the English one
+	if got := inverseDocumentFrequency(lightStem("privacy", "en"), "ja"); got != common {
+		t.Errorf("idf(privacy, ja) = %.3f, want the English %.3f", got, common)
+	}
+}